@@ -0,0 +1,190 @@
+// Package consistent mirrors API of github.com/buraksezer/consistent backed
+// by HRW selection, so call sites written against that package can switch
+// algorithms by changing import path:
+//
+//	import "github.com/im-kulikov/hrw/adapter/buraksezer/consistent"
+//
+// Like the original, keys are mapped to partitions by hash modulo
+// PartitionCount and partitions are owned by members. Owner of partition is
+// the most preferable member by HRW, so there's no ring of virtual nodes:
+// ReplicationFactor is ignored, Load isn't bounding partitions per member
+// and only scales AverageLoad.
+package consistent
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/im-kulikov/hrw"
+)
+
+// DefaultPartitionCount is used when Config.PartitionCount isn't set
+const DefaultPartitionCount = 271
+
+// ErrInsufficientMemberCount returned when there are fewer members
+// than requested
+var ErrInsufficientMemberCount = errors.New("insufficient member count")
+
+type (
+	// Member is a member of Consistent, it's identified by String
+	Member interface {
+		String() string
+	}
+
+	// Hasher hashes keys and member names
+	Hasher interface {
+		Sum64([]byte) uint64
+	}
+
+	// Config of Consistent
+	Config struct {
+		// Hasher hashes keys and members, nil means hrw.Hash
+		Hasher Hasher
+		// PartitionCount is count of partitions keys are mapped to
+		PartitionCount int
+		// ReplicationFactor is kept for compatibility and ignored
+		ReplicationFactor int
+		// Load scales AverageLoad
+		Load float64
+	}
+
+	// Consistent distributes partitions among members by HRW,
+	// it's safe for concurrent use
+	Consistent struct {
+		mu      sync.RWMutex
+		config  Config
+		hash    func([]byte) uint64
+		ring    *hrw.Ring
+		members map[string]Member
+	}
+)
+
+// New creates Consistent with members
+func New(members []Member, config Config) *Consistent {
+	c := &Consistent{
+		config:  config,
+		hash:    hrw.Hash,
+		ring:    hrw.NewRing(),
+		members: make(map[string]Member, len(members)),
+	}
+
+	if c.config.PartitionCount <= 0 {
+		c.config.PartitionCount = DefaultPartitionCount
+	}
+
+	if config.Hasher != nil {
+		c.hash = config.Hasher.Sum64
+		c.ring.SetHash(c.hash)
+	}
+
+	for _, m := range members {
+		c.Add(m)
+	}
+	return c
+}
+
+// Add adds member, member with the same name is replaced
+func (c *Consistent) Add(member Member) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.members[member.String()] = member
+	c.ring.Add(hrw.Node{ID: member.String()})
+}
+
+// Remove removes member by name
+func (c *Consistent) Remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.members, name)
+	c.ring.Remove(name)
+}
+
+// GetMembers returns members ordered by name
+func (c *Consistent) GetMembers() []Member {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	nodes := c.ring.Nodes()
+	result := make([]Member, 0, len(nodes))
+	for _, n := range nodes {
+		result = append(result, c.members[n.ID])
+	}
+	return result
+}
+
+// FindPartitionID returns partition of key
+func (c *Consistent) FindPartitionID(key []byte) int {
+	return int(c.hash(key) % uint64(c.config.PartitionCount))
+}
+
+// GetPartitionOwner returns member owning partition,
+// nil is returned when there are no members
+func (c *Consistent) GetPartitionOwner(partID int) Member {
+	list := c.closest(partID, 1)
+	if len(list) == 0 {
+		return nil
+	}
+	return list[0]
+}
+
+// LocateKey returns member owning partition of key
+func (c *Consistent) LocateKey(key []byte) Member {
+	return c.GetPartitionOwner(c.FindPartitionID(key))
+}
+
+// GetClosestN returns count members for partition of key in order
+// of preference
+func (c *Consistent) GetClosestN(key []byte, count int) ([]Member, error) {
+	return c.GetClosestNForPartition(c.FindPartitionID(key), count)
+}
+
+// GetClosestNForPartition returns count members for partition in order
+// of preference
+func (c *Consistent) GetClosestNForPartition(partID, count int) ([]Member, error) {
+	list := c.closest(partID, count)
+	if len(list) < count {
+		return nil, ErrInsufficientMemberCount
+	}
+	return list, nil
+}
+
+// AverageLoad returns average count of partitions per member scaled by Load
+func (c *Consistent) AverageLoad() float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.members) == 0 {
+		return 0
+	}
+	return float64(c.config.PartitionCount) / float64(len(c.members)) * c.config.Load
+}
+
+// LoadDistribution returns count of partitions owned by every member
+func (c *Consistent) LoadDistribution() map[string]float64 {
+	result := make(map[string]float64)
+	for partID := 0; partID < c.config.PartitionCount; partID++ {
+		if m := c.GetPartitionOwner(partID); m != nil {
+			result[m.String()]++
+		}
+	}
+	return result
+}
+
+// closest returns up to count members for partition in order of preference
+func (c *Consistent) closest(partID, count int) []Member {
+	var key [8]byte
+	binary.LittleEndian.PutUint64(key[:], uint64(partID))
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	nodes := c.ring.GetN(key[:], count)
+	result := make([]Member, 0, len(nodes))
+	for _, n := range nodes {
+		result = append(result, c.members[n.ID])
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+package consistent
+
+import (
+	"errors"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type member string
+
+func (m member) String() string { return string(m) }
+
+type fnvHasher struct{}
+
+func (fnvHasher) Sum64(data []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	return h.Sum64()
+}
+
+func testMembers(n int) []Member {
+	members := make([]Member, 0, n)
+	for i := 0; i < n; i++ {
+		members = append(members, member("node-"+strconv.Itoa(i)))
+	}
+	return members
+}
+
+func TestConsistent(t *testing.T) {
+	c := New(testMembers(5), Config{Hasher: fnvHasher{}, PartitionCount: 71, Load: 1.25})
+
+	key := []byte("key")
+	owner := c.LocateKey(key)
+	closest, err := c.GetClosestN(key, 3)
+	if err != nil {
+		t.Fatal(err)
+	} else if closest[0] != owner {
+		t.Errorf("Was %#v, but expected %#v", closest[0], owner)
+	}
+
+	if _, err := c.GetClosestN(key, 6); !errors.Is(err, ErrInsufficientMemberCount) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrInsufficientMemberCount)
+	}
+
+	c.Remove(owner.String())
+	if next := c.LocateKey(key); next != closest[1] {
+		t.Errorf("Was %#v, but expected %#v", next, closest[1])
+	}
+
+	if actual, expect := c.AverageLoad(), 71.0/4*1.25; actual != expect {
+		t.Errorf("Was %v, but expected %v", actual, expect)
+	}
+
+	var total float64
+	for _, load := range c.LoadDistribution() {
+		total += load
+	}
+	if total != 71 {
+		t.Errorf("Was %v, but expected %v", total, 71)
+	}
+}
+
+func TestGetMembers(t *testing.T) {
+	c := New(nil, Config{})
+	if c.LocateKey([]byte("key")) != nil {
+		t.Errorf("Expected nil owner of empty Consistent")
+	}
+
+	c.Add(member("b"))
+	c.Add(member("a"))
+	if actual, expect := c.GetMembers(), []Member{member("a"), member("b")}; !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
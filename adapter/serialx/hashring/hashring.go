@@ -0,0 +1,138 @@
+// Package hashring mirrors API of github.com/serialx/hashring backed by HRW
+// selection, so services using it can evaluate HRW by changing import path:
+//
+//	import "github.com/im-kulikov/hrw/adapter/serialx/hashring"
+//
+// Like the original, HashRing is immutable: changes return new HashRing.
+// Weights are relative capacities of nodes, so node of weight 2 receives
+// twice as many keys as node of weight 1.
+package hashring
+
+import (
+	"sort"
+
+	"github.com/im-kulikov/hrw"
+)
+
+// HashRing selects weighted nodes for keys, it's safe for concurrent use
+type HashRing struct {
+	ring    *hrw.Ring
+	weights map[string]int
+}
+
+// New creates HashRing of nodes of weight 1
+func New(nodes []string) *HashRing {
+	weights := make(map[string]int, len(nodes))
+	for _, node := range nodes {
+		weights[node] = 1
+	}
+	return NewWithWeights(weights)
+}
+
+// NewWithWeights creates HashRing of nodes with weights
+func NewWithWeights(weights map[string]int) *HashRing {
+	h := &HashRing{
+		ring:    hrw.NewRing(),
+		weights: make(map[string]int, len(weights)),
+	}
+
+	nodes := make([]hrw.Node, 0, len(weights))
+	for node, weight := range weights {
+		h.weights[node] = weight
+		nodes = append(nodes, hrw.Node{ID: node, Weight: float64(weight)})
+	}
+
+	h.ring.Add(nodes...)
+	return h
+}
+
+// Size returns count of nodes
+func (h *HashRing) Size() int {
+	return len(h.weights)
+}
+
+// GetNode returns node for key, ok is false when there are no nodes
+func (h *HashRing) GetNode(stringKey string) (node string, ok bool) {
+	n, ok := h.ring.Get([]byte(stringKey))
+	return n.ID, ok
+}
+
+// GetNodePos returns position of node for key among nodes ordered by name
+func (h *HashRing) GetNodePos(stringKey string) (pos int, ok bool) {
+	node, ok := h.GetNode(stringKey)
+	if !ok {
+		return 0, false
+	}
+
+	names := h.names()
+	return sort.SearchStrings(names, node), true
+}
+
+// GetNodes returns size distinct nodes for key in order of preference,
+// ok is false when there are fewer nodes
+func (h *HashRing) GetNodes(stringKey string, size int) (nodes []string, ok bool) {
+	if size > h.Size() {
+		return []string{}, false
+	}
+
+	list := h.ring.GetN([]byte(stringKey), size)
+	nodes = make([]string, 0, len(list))
+	for _, n := range list {
+		nodes = append(nodes, n.ID)
+	}
+	return nodes, true
+}
+
+// AddNode returns HashRing with node of weight 1 added
+func (h *HashRing) AddNode(node string) *HashRing {
+	return h.AddWeightedNode(node, 1)
+}
+
+// AddWeightedNode returns HashRing with node added, h is returned when
+// node is known or weight isn't positive
+func (h *HashRing) AddWeightedNode(node string, weight int) *HashRing {
+	if _, ok := h.weights[node]; ok || weight <= 0 {
+		return h
+	}
+	return h.with(node, weight)
+}
+
+// UpdateWeightedNode returns HashRing with weight of node changed, h is
+// returned when weight isn't positive or isn't changed
+func (h *HashRing) UpdateWeightedNode(node string, weight int) *HashRing {
+	if w, ok := h.weights[node]; !ok || w == weight || weight <= 0 {
+		return h
+	}
+	return h.with(node, weight)
+}
+
+// RemoveNode returns HashRing without node
+func (h *HashRing) RemoveNode(node string) *HashRing {
+	weights := make(map[string]int, len(h.weights))
+	for n, w := range h.weights {
+		if n != node {
+			weights[n] = w
+		}
+	}
+	return NewWithWeights(weights)
+}
+
+// with returns copy of h with node of weight
+func (h *HashRing) with(node string, weight int) *HashRing {
+	weights := make(map[string]int, len(h.weights)+1)
+	for n, w := range h.weights {
+		weights[n] = w
+	}
+	weights[node] = weight
+	return NewWithWeights(weights)
+}
+
+// names returns names of nodes in ascending order
+func (h *HashRing) names() []string {
+	names := make([]string, 0, len(h.weights))
+	for n := range h.weights {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
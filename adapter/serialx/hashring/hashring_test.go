@@ -0,0 +1,73 @@
+package hashring
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestHashRing(t *testing.T) {
+	h := New([]string{"a", "b", "c"})
+
+	nodes, ok := h.GetNodes("key", 3)
+	if !ok || len(nodes) != 3 {
+		t.Fatalf("Was %#v (%v), but expected 3 nodes", nodes, ok)
+	}
+
+	if node, ok := h.GetNode("key"); !ok || node != nodes[0] {
+		t.Errorf("Was %q (%v), but expected %q", node, ok, nodes[0])
+	}
+
+	if _, ok := h.GetNodes("key", 4); ok {
+		t.Errorf("Expected GetNodes to fail for too many nodes")
+	}
+
+	removed := h.RemoveNode(nodes[0])
+	if node, _ := removed.GetNode("key"); node != nodes[1] {
+		t.Errorf("Was %q, but expected %q", node, nodes[1])
+	}
+	if h.Size() != 3 || removed.Size() != 2 {
+		t.Errorf("Was %d and %d, but expected %d and %d", h.Size(), removed.Size(), 3, 2)
+	}
+
+	if pos, ok := h.GetNodePos("key"); !ok || h.names()[pos] != nodes[0] {
+		t.Errorf("Was %d (%v), but expected position of %q", pos, ok, nodes[0])
+	}
+}
+
+func TestHashRingUnchanged(t *testing.T) {
+	h := New([]string{"a"})
+
+	for _, next := range []*HashRing{
+		h.AddNode("a"),
+		h.AddWeightedNode("b", 0),
+		h.UpdateWeightedNode("a", 1),
+		h.UpdateWeightedNode("b", 2),
+	} {
+		if next != h {
+			t.Errorf("Expected the same HashRing")
+		}
+	}
+
+	if _, ok := New(nil).GetNode("key"); ok {
+		t.Errorf("Expected no node of empty HashRing")
+	}
+}
+
+func TestHashRingWeights(t *testing.T) {
+	h := NewWithWeights(map[string]int{"a": 1, "b": 1}).UpdateWeightedNode("b", 3)
+
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		node, _ := h.GetNode(strconv.Itoa(i))
+		counts[node]++
+	}
+
+	if counts["b"] < 7000 || counts["b"] > 8000 {
+		t.Errorf("Was %d keys of heavy node, but expected about %d", counts["b"], 7500)
+	}
+
+	if actual, expect := h.AddNode("c").names(), []string{"a", "b", "c"}; !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
@@ -0,0 +1,145 @@
+// Package consistent mirrors API of stathat.com/c/consistent backed by HRW
+// selection, so legacy services can migrate from ring-based consistent
+// hashing by changing import path:
+//
+//	import "github.com/im-kulikov/hrw/adapter/stathat/consistent"
+//
+// There are no virtual nodes, so NumberOfReplicas is ignored.
+package consistent
+
+import (
+	"errors"
+	"hash/fnv"
+	"sync"
+
+	"github.com/im-kulikov/hrw"
+)
+
+// ErrEmptyCircle returned when there are no members
+var ErrEmptyCircle = errors.New("empty circle")
+
+// Consistent holds members and selects them for names,
+// it's safe for concurrent use
+type Consistent struct {
+	// NumberOfReplicas is kept for compatibility and ignored
+	NumberOfReplicas int
+	// UseFnv makes Consistent hash names and members by 64-bit FNV-1a
+	// instead of murmur3
+	UseFnv bool
+
+	sync.RWMutex
+	ring *hrw.Ring
+	fnv  bool
+}
+
+// New creates empty Consistent
+func New() *Consistent {
+	return &Consistent{NumberOfReplicas: 20, ring: hrw.NewRing()}
+}
+
+// Add adds member
+func (c *Consistent) Add(elt string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.ring.Add(hrw.Node{ID: elt})
+}
+
+// Remove removes member
+func (c *Consistent) Remove(elt string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.ring.Remove(elt)
+}
+
+// Set replaces all members by elts
+func (c *Consistent) Set(elts []string) {
+	c.Lock()
+	defer c.Unlock()
+
+	var stale []string
+	for _, n := range c.ring.Nodes() {
+		stale = append(stale, n.ID)
+	}
+	c.ring.Remove(stale...)
+
+	for _, elt := range elts {
+		c.ring.Add(hrw.Node{ID: elt})
+	}
+}
+
+// Members returns members ordered by name
+func (c *Consistent) Members() []string {
+	c.RLock()
+	defer c.RUnlock()
+
+	nodes := c.ring.Nodes()
+	result := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		result = append(result, n.ID)
+	}
+	return result
+}
+
+// Get returns member for name
+func (c *Consistent) Get(name string) (string, error) {
+	list, err := c.GetN(name, 1)
+	if err != nil {
+		return "", err
+	}
+	return list[0], nil
+}
+
+// GetTwo returns two members for name, the second one is empty
+// when there's only one member
+func (c *Consistent) GetTwo(name string) (string, string, error) {
+	list, err := c.GetN(name, 2)
+	if err != nil {
+		return "", "", err
+	} else if len(list) == 1 {
+		return list[0], "", nil
+	}
+	return list[0], list[1], nil
+}
+
+// GetN returns up to n members for name in order of preference
+func (c *Consistent) GetN(name string, n int) ([]string, error) {
+	s := c.view().Snapshot()
+	if s.Len() == 0 {
+		return nil, ErrEmptyCircle
+	}
+
+	nodes := s.GetN([]byte(name), n)
+	result := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, node.ID)
+	}
+	return result, nil
+}
+
+// view returns Ring hashing by function UseFnv selects
+func (c *Consistent) view() *hrw.Ring {
+	c.RLock()
+	if c.fnv == c.UseFnv {
+		defer c.RUnlock()
+		return c.ring
+	}
+	c.RUnlock()
+
+	c.Lock()
+	defer c.Unlock()
+
+	if c.fnv = c.UseFnv; c.fnv {
+		c.ring.SetHash(hashFnv)
+	} else {
+		c.ring.SetHash(nil)
+	}
+	return c.ring
+}
+
+func hashFnv(data []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	return h.Sum64()
+}
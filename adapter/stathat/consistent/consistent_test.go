@@ -0,0 +1,64 @@
+package consistent
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestConsistent(t *testing.T) {
+	c := New()
+	if _, err := c.Get("key"); !errors.Is(err, ErrEmptyCircle) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrEmptyCircle)
+	}
+
+	c.Add("a")
+	first, second, err := c.GetTwo("key")
+	if err != nil || first != "a" || second != "" {
+		t.Errorf("Was %q, %q (%v), but expected %q, %q", first, second, err, "a", "")
+	}
+
+	c.Set([]string{"b", "c", "d"})
+	if actual, expect := c.Members(), []string{"b", "c", "d"}; !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	list, err := c.GetN("key", 5)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(list) != 3 {
+		t.Errorf("Was %d, but expected %d", len(list), 3)
+	}
+
+	c.Remove(list[0])
+	if owner, _ := c.Get("key"); owner != list[1] {
+		t.Errorf("Was %q, but expected %q", owner, list[1])
+	}
+}
+
+func TestUseFnv(t *testing.T) {
+	var (
+		murmur = New()
+		fnv    = New()
+		moved  int
+	)
+
+	fnv.UseFnv = true
+	for i := 0; i < 10; i++ {
+		murmur.Add("node-" + strconv.Itoa(i))
+		fnv.Add("node-" + strconv.Itoa(i))
+	}
+
+	for i := 0; i < 100; i++ {
+		a, _ := murmur.Get(strconv.Itoa(i))
+		b, _ := fnv.Get(strconv.Itoa(i))
+		if a != b {
+			moved++
+		}
+	}
+
+	if moved == 0 {
+		t.Errorf("Expected FNV to place keys differently")
+	}
+}
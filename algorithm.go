@@ -0,0 +1,171 @@
+package hrw
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Algorithm identifies the way nodes are ordered. Ordering of every
+// released algorithm is frozen: the same inputs produce the same order in
+// all future versions of package, improvements are shipped as new versions.
+type Algorithm uint8
+
+const (
+	// V1 orders nodes like SortSliceByValue orders their IDs,
+	// weights are applied by logarithmic method
+	V1 Algorithm = iota
+	// NSPCC reproduces ordering of github.com/nspcc-dev/hrw fork,
+	// which scales distance of node by it's weight linearly
+	NSPCC
+	// V2 mixes value with key hash only once and encodes all integers as
+	// 8 byte big endian two's complement, so equal numbers have equal
+	// hashes regardless of their type; weights applied like in V1
+	V2
+	// V3 is like V2, but mixes node value before combining it with key
+	// hash, so sequential node values don't correlate orderings of keys
+	V3
+)
+
+// String returns name of algorithm
+func (a Algorithm) String() string {
+	switch a {
+	case V1:
+		return "V1"
+	case NSPCC:
+		return "NSPCC"
+	case V2:
+		return "V2"
+	case V3:
+		return "V3"
+	default:
+		return "Algorithm(" + strconv.Itoa(int(a)) + ")"
+	}
+}
+
+// known reports whether algorithm is one of released ones
+func (a Algorithm) known() bool {
+	return a <= V3
+}
+
+// SortSliceByValue sorts slice like SortSliceByValue using algorithm
+func (a Algorithm) SortSliceByValue(slice interface{}, hash uint64) {
+	checkStrict(a.TrySortSliceByValue(slice, hash))
+}
+
+// TrySortSliceByValue sorts slice like TrySortSliceByValue using algorithm
+func (a Algorithm) TrySortSliceByValue(slice interface{}, hash uint64) error {
+	switch a {
+	case V1:
+		return TrySortSliceByValue(slice, hash)
+	case NSPCC:
+		return sortSliceByWeightValueNSPCC(slice, nil, hash)
+	case V2, V3:
+		swap, length, ok := sliceSwapper(slice)
+		if !ok {
+			return notSlice(slice)
+		} else if length == 0 {
+			return nil
+		}
+
+		rule, nils, err := prepareRuleV2(slice, length)
+		if err != nil {
+			return err
+		}
+
+		return applyOrder(swap, length, a.sortByWeight(rule, hash).sorted, nils)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownAlgorithm, a)
+	}
+}
+
+// SortByWeight sorts nodes like SortByWeight using algorithm,
+// only V3 differs from SortByWeight
+func (a Algorithm) SortByWeight(nodes []uint64, hash uint64) []uint64 {
+	return a.sortByWeight(nodes, hash).order(nil)
+}
+
+func (a Algorithm) sortByWeight(nodes []uint64, hash uint64) hashed {
+	if a == V3 {
+		return sortByWeightFunc(nodes, hash, MixPremixed)
+	}
+	return sortByWeightFunc(nodes, hash, weight)
+}
+
+func (a Algorithm) candidate(value, hash uint64, nodeWeight float64) candidate {
+	switch a {
+	case V3:
+		raw := MixPremixed(value, hash)
+		return candidate{raw: raw, score: score(raw, nodeWeight)}
+	case NSPCC:
+		raw := weight(value, hash)
+		return candidate{raw: raw, score: -float64(^raw) * nodeWeight}
+	case V2:
+		raw := weight(value, hash)
+		return candidate{raw: raw, score: score(raw, nodeWeight)}
+	default:
+		raw := valueWeight(value, hash)
+		return candidate{raw: raw, score: score(raw, nodeWeight)}
+	}
+}
+
+// HashInt returns hash of integer used by V2
+func HashInt(v int64) uint64 {
+	return HashUint(uint64(v))
+}
+
+// HashUint returns hash of unsigned integer used by V2
+func HashUint(v uint64) uint64 {
+	return HashUint64(v)
+}
+
+func prepareRuleV2(slice interface{}, length int) ([]uint64, []int, error) {
+	rule := make([]uint64, 0, length)
+
+	switch slice := slice.(type) {
+	case []int:
+		for i := range slice {
+			rule = append(rule, HashInt(int64(slice[i])))
+		}
+	case []int32:
+		for i := range slice {
+			rule = append(rule, HashInt(int64(slice[i])))
+		}
+	case []int16:
+		for i := range slice {
+			rule = append(rule, HashInt(int64(slice[i])))
+		}
+	case []int8:
+		for i := range slice {
+			rule = append(rule, HashInt(int64(slice[i])))
+		}
+	case []int64:
+		for i := range slice {
+			rule = append(rule, HashInt(slice[i]))
+		}
+	case []uint:
+		for i := range slice {
+			rule = append(rule, HashUint(uint64(slice[i])))
+		}
+	case []uint32:
+		for i := range slice {
+			rule = append(rule, HashUint(uint64(slice[i])))
+		}
+	case []uint64:
+		for i := range slice {
+			rule = append(rule, HashUint(slice[i]))
+		}
+	case []string:
+		for i := range slice {
+			rule = append(rule, Hash([]byte(slice[i])))
+		}
+	default:
+		at, err := sliceHashers(slice)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		rule, nils := hashersRule(at, length, rule, nil)
+		return rule, nils, nil
+	}
+	return rule, nil, nil
+}
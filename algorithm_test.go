@@ -0,0 +1,132 @@
+package hrw
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestAlgorithmFrozen guards orderings of released algorithms,
+// expected values must never be changed.
+func TestAlgorithmFrozen(t *testing.T) {
+	cases := []struct {
+		alg     Algorithm
+		strings []string
+		ints    []int
+		ring    []string
+	}{
+		{
+			alg:     V1,
+			strings: []string{"d", "b", "a", "f", "c", "e"},
+			ints:    []int{2, -1, 1, -2, 0, -3},
+			ring:    []string{"d", "f", "b", "c", "e", "a"},
+		},
+		{
+			alg:     NSPCC,
+			strings: []string{"d", "f", "c", "b", "a", "e"},
+			ints:    []int{2, -1, 0, -2, 1, -3},
+			ring:    []string{"f", "c", "b", "d", "a", "e"},
+		},
+		{
+			alg:     V2,
+			strings: []string{"d", "f", "c", "b", "a", "e"},
+			ints:    []int{-1, 0, 2, -2, 1, -3},
+			ring:    []string{"f", "d", "c", "b", "a", "e"},
+		},
+		{
+			alg:     V3,
+			strings: []string{"e", "d", "a", "b", "c", "f"},
+			ints:    []int{-3, -2, 0, 2, 1, -1},
+			ring:    []string{"e", "d", "c", "b", "f", "a"},
+		},
+	}
+
+	hash := Hash(testKey)
+	for _, tc := range cases {
+		t.Run(tc.alg.String(), func(t *testing.T) {
+			strings := []string{"a", "b", "c", "d", "e", "f"}
+			tc.alg.SortSliceByValue(strings, hash)
+			if !reflect.DeepEqual(strings, tc.strings) {
+				t.Errorf("Was %#v, but expected %#v", strings, tc.strings)
+			}
+
+			ints := []int{-3, -2, -1, 0, 1, 2}
+			tc.alg.SortSliceByValue(ints, hash)
+			if !reflect.DeepEqual(ints, tc.ints) {
+				t.Errorf("Was %#v, but expected %#v", ints, tc.ints)
+			}
+
+			r := NewRing(
+				Node{ID: "a", Weight: 1}, Node{ID: "b", Weight: 2}, Node{ID: "c", Weight: 3},
+				Node{ID: "d", Weight: 1}, Node{ID: "e", Weight: 2}, Node{ID: "f", Weight: 3},
+			)
+			r.SetAlgorithm(tc.alg)
+			if actual := nodeIDs(r.GetN(testKey, 6)); !reflect.DeepEqual(actual, tc.ring) {
+				t.Errorf("Was %#v, but expected %#v", actual, tc.ring)
+			}
+		})
+	}
+}
+
+func TestV2IntegerTypes(t *testing.T) {
+	var (
+		hash   = Hash(testKey)
+		ints   = []int{-2, -1, 0, 1, 2, 3}
+		int32s = []int32{-2, -1, 0, 1, 2, 3}
+		int64s = []int64{-2, -1, 0, 1, 2, 3}
+	)
+
+	V2.SortSliceByValue(ints, hash)
+	V2.SortSliceByValue(int32s, hash)
+	V2.SortSliceByValue(int64s, hash)
+
+	for i := range ints {
+		if int64(ints[i]) != int64(int32s[i]) || int64(ints[i]) != int64s[i] {
+			t.Fatalf("Different orders: %v, %v, %v", ints, int32s, int64s)
+		}
+	}
+
+	var (
+		uints  = []uint64{0, 1, 2, 3}
+		signed = []int64{0, 1, 2, 3}
+	)
+
+	V2.SortSliceByValue(uints, hash)
+	V2.SortSliceByValue(signed, hash)
+	for i := range uints {
+		if int64(uints[i]) != signed[i] {
+			t.Fatalf("Different orders: %v, %v", uints, signed)
+		}
+	}
+}
+
+func TestAlgorithmString(t *testing.T) {
+	if actual := Algorithm(100).String(); actual != "Algorithm(100)" {
+		t.Errorf("Was %q, but expected %q", actual, "Algorithm(100)")
+	}
+}
+
+func TestV3Sequential(t *testing.T) {
+	var (
+		hash  = Hash(testKey)
+		nodes = []uint64{0, 1, 2, 3, 4, 5}
+	)
+
+	if actual, expect := V3.SortByWeight(nodes, hash), []uint64{4, 5, 2, 3, 1, 0}; !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	if actual, expect := V2.SortByWeight(nodes, hash), SortByWeight(nodes, hash); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	// sequential keys select the same node with probability 1 / nodes only
+	// when node values are premixed
+	const expect = 0.1
+	if rep := MeasureBias(MixPremixed, 10, 20000); rep.SameWinner < expect*0.8 || rep.SameWinner > expect*1.2 {
+		t.Errorf("Was %#v, expected SameWinner close to %v", rep, expect)
+	}
+
+	if rep := MeasureBias(MixMurmur3, 10, 20000); rep.SameWinner > expect*0.5 {
+		t.Errorf("Was %#v, expected correlated placements without premix", rep)
+	}
+}
@@ -0,0 +1,16 @@
+package hrw
+
+// bitset marks indexes using one bit per index
+type bitset []uint64
+
+func newBitset(n int) bitset {
+	return make(bitset, (n+63)/64)
+}
+
+func (b bitset) has(i uint64) bool {
+	return b[i/64]&(1<<(i%64)) != 0
+}
+
+func (b bitset) set(i uint64) {
+	b[i/64] |= 1 << (i % 64)
+}
@@ -0,0 +1,20 @@
+package hrw
+
+import "testing"
+
+func TestBitset(t *testing.T) {
+	b := newBitset(130)
+	for _, i := range []uint64{0, 63, 64, 129} {
+		if b.has(i) {
+			t.Errorf("Expected %d not to be set", i)
+		}
+		b.set(i)
+	}
+
+	for i := uint64(0); i < 130; i++ {
+		expect := i == 0 || i == 63 || i == 64 || i == 129
+		if actual := b.has(i); actual != expect {
+			t.Errorf("Was %v for %d, but expected %v", actual, i, expect)
+		}
+	}
+}
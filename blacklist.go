@@ -0,0 +1,83 @@
+package hrw
+
+import "time"
+
+// listing is temporary state of node set by Blacklist or Graylist
+type listing struct {
+	state    State
+	deadline time.Time
+}
+
+// Blacklist excludes nodes from selection for d, d <= 0 excludes them
+// until Unlist. Nodes return to selection automatically when d is over.
+func (r *Ring) Blacklist(d time.Duration, ids ...string) {
+	r.list(StateDown, d, ids)
+}
+
+// Graylist makes nodes selected only after all active nodes for d,
+// d <= 0 keeps them graylisted until Unlist. Use it for nodes in brownout
+// that should receive traffic only when others are exhausted.
+func (r *Ring) Graylist(d time.Duration, ids ...string) {
+	r.list(StateGray, d, ids)
+}
+
+// Unlist removes nodes from blacklist and graylist
+func (r *Ring) Unlist(ids ...string) {
+	r.update(func(s *Snapshot) bool {
+		lists := s.copyLists(r.clock())
+		for _, id := range ids {
+			delete(lists, id)
+		}
+
+		s.lists = lists
+		return true
+	})
+}
+
+func (r *Ring) list(state State, d time.Duration, ids []string) {
+	r.update(func(s *Snapshot) bool {
+		now := r.clock()
+		lists := s.copyLists(now)
+
+		l := listing{state: state}
+		if d > 0 {
+			l.deadline = now.Add(d)
+		}
+
+		for _, id := range ids {
+			lists[id] = l
+		}
+
+		s.lists = lists
+		return true
+	})
+}
+
+// copyLists returns copy of unexpired listings
+func (s *Snapshot) copyLists(now time.Time) map[string]listing {
+	lists := make(map[string]listing, len(s.lists))
+	for id, l := range s.lists {
+		if !l.expired(now) {
+			lists[id] = l
+		}
+	}
+	return lists
+}
+
+// stateOf returns state of member taking listings into account,
+// listing can only make state of node worse
+func (s *Snapshot) stateOf(m *member, now time.Time) State {
+	l, ok := s.lists[m.ID]
+	if !ok || l.expired(now) || !m.State.selectable() {
+		return m.State
+	}
+
+	if l.state == StateDown || m.State == StateActive {
+		return l.state
+	}
+	return m.State
+}
+
+func (l listing) expired(now time.Time) bool {
+	return !l.deadline.IsZero() && !now.Before(l.deadline)
+}
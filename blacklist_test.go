@@ -0,0 +1,77 @@
+package hrw
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRingBlacklist(t *testing.T) {
+	var (
+		now    = time.Unix(0, 0)
+		r      = new(Ring)
+		expect []string
+	)
+
+	r.now = func() time.Time { return now }
+	r.Add(testNodes(5)...)
+	expect = nodeIDs(r.GetN(testKey, 5))
+
+	r.Blacklist(time.Minute, expect[0])
+	if actual := nodeIDs(r.GetN(testKey, 5)); !reflect.DeepEqual(actual, expect[1:]) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect[1:])
+	}
+
+	now = now.Add(time.Minute)
+	if actual := nodeIDs(r.GetN(testKey, 5)); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	r.Blacklist(0, expect[1])
+	now = now.Add(time.Hour)
+	if actual := len(r.GetN(testKey, 5)); actual != 4 {
+		t.Errorf("Was %d, but expected %d", actual, 4)
+	}
+
+	r.Unlist(expect[1])
+	if actual := nodeIDs(r.GetN(testKey, 5)); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
+
+func TestRingGraylist(t *testing.T) {
+	var (
+		now = time.Unix(0, 0)
+		r   = new(Ring)
+	)
+
+	r.now = func() time.Time { return now }
+	r.Add(testNodes(5)...)
+	expect := nodeIDs(r.GetN(testKey, 5))
+
+	r.Graylist(time.Minute, expect[0], expect[2])
+	grayed := []string{expect[1], expect[3], expect[4], expect[0], expect[2]}
+	if actual := nodeIDs(r.GetN(testKey, 5)); !reflect.DeepEqual(actual, grayed) {
+		t.Errorf("Was %#v, but expected %#v", actual, grayed)
+	}
+
+	// blacklist replaces graylist
+	r.Blacklist(0, expect[0])
+	if actual, listed := nodeIDs(r.GetN(testKey, 5)), []string{expect[1], expect[3], expect[4], expect[2]}; !reflect.DeepEqual(actual, listed) {
+		t.Errorf("Was %#v, but expected %#v", actual, listed)
+	}
+
+	now = now.Add(time.Minute)
+	r.Unlist(expect[0])
+	if actual := nodeIDs(r.GetN(testKey, 5)); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	t.Run("gray state", func(t *testing.T) {
+		r := NewRing(testNodes(5)...)
+		r.Add(Node{ID: expect[0], State: StateGray})
+		if actual := nodeIDs(r.GetN(testKey, 5)); !reflect.DeepEqual(actual, append(expect[1:], expect[0])) {
+			t.Errorf("Was %#v, but expected %#v", actual, append(expect[1:], expect[0]))
+		}
+	})
+}
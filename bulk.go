@@ -0,0 +1,132 @@
+package hrw
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/im-kulikov/hrw/internal/murmur3"
+)
+
+// bulkBlock is count of independent mixes computed per iteration of bulk
+// loops, it lets CPU pipeline them instead of waiting for each one
+const bulkBlock = 4
+
+// assignChunk is minimal count of hashes worth a goroutine
+const assignChunk = 256
+
+// Weights stores weights SortByWeight assigns to nodes for hash into dst,
+// dst must be at least as long as nodes
+func Weights(dst, nodes []uint64, hash uint64) {
+	dst = dst[:len(nodes)]
+
+	i := 0
+	for ; i+bulkBlock <= len(nodes); i += bulkBlock {
+		n := nodes[i : i+bulkBlock : i+bulkBlock]
+		d := dst[i : i+bulkBlock : i+bulkBlock]
+		d[0] = murmur3.Fmix64(n[0] ^ hash)
+		d[1] = murmur3.Fmix64(n[1] ^ hash)
+		d[2] = murmur3.Fmix64(n[2] ^ hash)
+		d[3] = murmur3.Fmix64(n[3] ^ hash)
+	}
+
+	for ; i < len(nodes); i++ {
+		dst[i] = murmur3.Fmix64(nodes[i] ^ hash)
+	}
+}
+
+// Assign stores index of the most preferable node for every hash into dst,
+// like Top1Index does. Dst must be at least as long as hashes.
+func Assign(dst []int, nodes []uint64, hashes []uint64) {
+	dst = dst[:len(hashes)]
+	for k, hash := range hashes {
+		dst[k] = Top1Index(nodes, hash)
+	}
+}
+
+// AssignParallel is like Assign, but splits hashes between workers
+// running concurrently. Every hash is assigned by exactly one worker, so
+// dst is the same as Assign stores. Workers <= 0 means GOMAXPROCS.
+func AssignParallel(dst []int, nodes []uint64, hashes []uint64, workers int) {
+	assignParallel(dst, hashes, workers, func(dst []int, hashes []uint64) {
+		Assign(dst, nodes, hashes)
+	})
+}
+
+// assignParallel splits hashes and their part of dst between workers
+// running assign concurrently
+func assignParallel(dst []int, hashes []uint64, workers int, assign func(dst []int, hashes []uint64)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	if limit := (len(hashes) + assignChunk - 1) / assignChunk; workers > limit {
+		workers = limit
+	}
+
+	dst = dst[:len(hashes)]
+	if workers <= 1 {
+		assign(dst, hashes)
+		return
+	}
+
+	chunk := (len(hashes) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for lo := 0; lo < len(hashes); lo += chunk {
+		hi := lo + chunk
+		if hi > len(hashes) {
+			hi = len(hashes)
+		}
+
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			assign(dst[lo:hi], hashes[lo:hi])
+		}(lo, hi)
+	}
+	wg.Wait()
+}
+
+// Top1Index returns index of the most preferable node for hash, it's the
+// same as SortByWeight(nodes, hash)[0], but scans nodes once without
+// sorting and allocations. It returns -1 when there are no nodes.
+func Top1Index(nodes []uint64, hash uint64) int {
+	best, bestWeight := -1, uint64(0)
+
+	i := 0
+	for ; i+bulkBlock <= len(nodes); i += bulkBlock {
+		n := nodes[i : i+bulkBlock : i+bulkBlock]
+		w0 := murmur3.Fmix64(n[0] ^ hash)
+		w1 := murmur3.Fmix64(n[1] ^ hash)
+		w2 := murmur3.Fmix64(n[2] ^ hash)
+		w3 := murmur3.Fmix64(n[3] ^ hash)
+
+		best, bestWeight = better(nodes, best, bestWeight, i, w0)
+		best, bestWeight = better(nodes, best, bestWeight, i+1, w1)
+		best, bestWeight = better(nodes, best, bestWeight, i+2, w2)
+		best, bestWeight = better(nodes, best, bestWeight, i+3, w3)
+	}
+
+	for ; i < len(nodes); i++ {
+		best, bestWeight = better(nodes, best, bestWeight, i, murmur3.Fmix64(nodes[i]^hash))
+	}
+	return best
+}
+
+// top1IndexFunc is like Top1Index, but weights are calculated by fn
+func top1IndexFunc(nodes []uint64, hash uint64, fn WeightFunc) int {
+	best, bestWeight := -1, uint64(0)
+	for i, node := range nodes {
+		best, bestWeight = better(nodes, best, bestWeight, i, fn(node, hash))
+	}
+	return best
+}
+
+// better returns node i when it precedes best node like in SortByWeight:
+// by weight and then by node value, earlier index wins otherwise
+func better(nodes []uint64, best int, bestWeight uint64, i int, w uint64) (int, uint64) {
+	if best < 0 || w < bestWeight || w == bestWeight && nodes[i] < nodes[best] {
+		return i, w
+	}
+	return best, bestWeight
+}
@@ -0,0 +1,108 @@
+package hrw
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestWeights(t *testing.T) {
+	nodes := []uint64{1, 2, 3, 4, 5, 6, 7}
+	dst := make([]uint64, len(nodes))
+
+	Weights(dst, nodes, 42)
+	for i := range nodes {
+		if expect := weight(nodes[i], 42); dst[i] != expect {
+			t.Errorf("Was %d, but expected %d", dst[i], expect)
+		}
+	}
+}
+
+func TestAssign(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 3, 4, 9, 100} {
+		var (
+			nodes  = make([]uint64, n)
+			hashes = make([]uint64, 100)
+			dst    = make([]int, len(hashes))
+		)
+
+		for i := range nodes {
+			// duplicates produce ties
+			nodes[i] = uint64(rnd.Intn(n))
+		}
+		for i := range hashes {
+			hashes[i] = rnd.Uint64()
+		}
+
+		Assign(dst, nodes, hashes)
+		for i, hash := range hashes {
+			if expect := int(SortByWeight(nodes, hash)[0]); dst[i] != expect {
+				t.Errorf("Was %d, but expected %d", dst[i], expect)
+			}
+		}
+	}
+
+	dst := []int{0}
+	if Assign(dst, nil, []uint64{1}); dst[0] != -1 {
+		t.Errorf("Was %d, but expected -1", dst[0])
+	}
+}
+
+func TestAssignParallel(t *testing.T) {
+	var (
+		nodes  = []uint64{1, 2, 3, 4, 5}
+		hashes = make([]uint64, 10000)
+		expect = make([]int, len(hashes))
+	)
+
+	for i := range hashes {
+		hashes[i] = HashUint64(uint64(i))
+	}
+	Assign(expect, nodes, hashes)
+
+	for _, workers := range []int{0, 1, 3, 64} {
+		actual := make([]int, len(hashes))
+		if AssignParallel(actual, nodes, hashes, workers); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Assignment of %d workers differs from Assign", workers)
+		}
+	}
+}
+
+func TestTop1Index(t *testing.T) {
+	nodes := []uint64{5, 1, 9, 7, 3}
+	for hash := uint64(0); hash < 100; hash++ {
+		if actual, expect := Top1Index(nodes, hash), int(SortByWeight(nodes, hash)[0]); actual != expect {
+			t.Errorf("Was %d, but expected %d", actual, expect)
+		}
+	}
+
+	if actual := Top1Index(nil, 0); actual != -1 {
+		t.Errorf("Was %d, but expected -1", actual)
+	}
+
+	if allocs := testing.AllocsPerRun(10, func() { Top1Index(nodes, 1) }); allocs != 0 {
+		t.Errorf("Was %.0f allocations, but expected 0", allocs)
+	}
+}
+
+func BenchmarkAssign(b *testing.B) {
+	var (
+		nodes  = make([]uint64, 100)
+		hashes = make([]uint64, 1000)
+		dst    = make([]int, len(hashes))
+	)
+
+	for i := range nodes {
+		nodes[i] = uint64(i)
+	}
+	for i := range hashes {
+		hashes[i] = HashUint64(uint64(i))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Assign(dst, nodes, hashes)
+	}
+}
@@ -0,0 +1,125 @@
+package hrw
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type (
+	// Cache memoizes orderings of Ring keyed by hash of key, count of nodes
+	// and version of Ring, so changes of Ring invalidate cached orderings.
+	// It holds up to size orderings evicting least recently used ones,
+	// orderings older than ttl are recomputed, ttl <= 0 disables expiration.
+	// Expiration of Blacklist and Graylist doesn't change version of Ring,
+	// so cached orderings reflect it only after ttl. Pinned keys aren't cached.
+	Cache struct {
+		ring  *Ring
+		size  int
+		ttl   time.Duration
+		now   func() time.Time
+		mu    sync.Mutex
+		order *list.List
+		items map[cacheKey]*list.Element
+	}
+
+	cacheKey struct {
+		hash    uint64
+		n       int
+		version uint64
+	}
+
+	cacheItem struct {
+		key     cacheKey
+		nodes   []Node
+		expires time.Time
+	}
+)
+
+// NewCache creates Cache over Ring
+func NewCache(r *Ring, size int, ttl time.Duration) *Cache {
+	return &Cache{
+		ring:  r,
+		size:  size,
+		ttl:   ttl,
+		now:   time.Now,
+		order: list.New(),
+		items: make(map[cacheKey]*list.Element, size),
+	}
+}
+
+// Len returns count of cached orderings
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Get returns most preferable active node for key
+func (c *Cache) Get(key []byte) (Node, bool) {
+	return first(c.GetN(key, 1))
+}
+
+// GetN returns up to n active nodes for key like Ring.GetN does
+func (c *Cache) GetN(key []byte, n int) []Node {
+	var (
+		s    = c.ring.view()
+		hash = s.hashFn.hash(key)
+		k    = cacheKey{hash: hash, n: n, version: s.version}
+	)
+
+	if _, ok := s.pinned(key); ok {
+		return s.GetN(key, n)
+	}
+
+	if nodes, ok := c.lookup(k); ok {
+		return nodes
+	}
+
+	nodes := s.pick(hash, n)
+	c.store(k, nodes)
+	return append([]Node(nil), nodes...)
+}
+
+func (c *Cache) lookup(k cacheKey) ([]Node, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[k]
+	if !ok {
+		return nil, false
+	}
+
+	item := el.Value.(*cacheItem)
+	if c.ttl > 0 && c.now().After(item.expires) {
+		c.order.Remove(el)
+		delete(c.items, k)
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+	return append([]Node(nil), item.nodes...), true
+}
+
+func (c *Cache) store(k cacheKey, nodes []Node) {
+	if c.size <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item := &cacheItem{key: k, nodes: nodes, expires: c.now().Add(c.ttl)}
+	if el, ok := c.items[k]; ok {
+		el.Value = item
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[k] = c.order.PushFront(item)
+	for c.order.Len() > c.size {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.items, el.Value.(*cacheItem).key)
+	}
+}
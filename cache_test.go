@@ -0,0 +1,109 @@
+package hrw
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("same as Ring", func(t *testing.T) {
+		r := NewRing(testNodes(10)...)
+		c := NewCache(r, 10, 0)
+
+		for i := 0; i < 2; i++ {
+			expect := nodeIDs(r.GetN(testKey, 3))
+			if actual := nodeIDs(c.GetN(testKey, 3)); !reflect.DeepEqual(actual, expect) {
+				t.Errorf("Was %#v, but expected %#v", actual, expect)
+			}
+		}
+
+		if actual := c.Len(); actual != 1 {
+			t.Errorf("Was %#v, but expected %#v", actual, 1)
+		}
+	})
+
+	t.Run("membership changed", func(t *testing.T) {
+		r := NewRing(testNodes(10)...)
+		c := NewCache(r, 10, 0)
+
+		before, _ := c.Get(testKey)
+		r.Remove(before.ID)
+
+		expect, _ := r.Get(testKey)
+		if actual, _ := c.Get(testKey); actual.ID != expect.ID {
+			t.Errorf("Was %#v, but expected %#v", actual.ID, expect.ID)
+		}
+	})
+
+	t.Run("evict least recently used", func(t *testing.T) {
+		c := NewCache(NewRing(testNodes(10)...), 2, 0)
+
+		c.Get([]byte("a"))
+		c.Get([]byte("b"))
+		c.Get([]byte("a"))
+		c.Get([]byte("c"))
+
+		if actual := c.Len(); actual != 2 {
+			t.Errorf("Was %#v, but expected %#v", actual, 2)
+		}
+
+		r := c.ring
+		if _, ok := c.lookup(cacheKey{hash: r.Hash([]byte("b")), n: 1, version: r.Version()}); ok {
+			t.Errorf("Expected key b to be evicted")
+		}
+		if _, ok := c.lookup(cacheKey{hash: r.Hash([]byte("a")), n: 1, version: r.Version()}); !ok {
+			t.Errorf("Expected key a to be cached")
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		var (
+			now = time.Unix(0, 0)
+			r   = NewRing(testNodes(10)...)
+			c   = NewCache(r, 10, time.Minute)
+			k   = cacheKey{hash: r.Hash(testKey), n: 1, version: r.Version()}
+		)
+
+		c.now = func() time.Time { return now }
+		c.Get(testKey)
+
+		now = now.Add(time.Second)
+		if _, ok := c.lookup(k); !ok {
+			t.Errorf("Expected key to be cached")
+		}
+
+		now = now.Add(time.Minute)
+		if _, ok := c.lookup(k); ok {
+			t.Errorf("Expected key to be expired")
+		}
+	})
+
+	t.Run("result is copied", func(t *testing.T) {
+		c := NewCache(NewRing(testNodes(10)...), 10, 0)
+		expect := nodeIDs(c.GetN(testKey, 3))
+
+		c.GetN(testKey, 3)[0].ID = "changed"
+		if actual := nodeIDs(c.GetN(testKey, 3)); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+}
+
+func BenchmarkCache(b *testing.B) {
+	var (
+		c    = NewCache(NewRing(testNodes(100)...), 1024, 0)
+		keys = make([][]byte, 64)
+	)
+
+	for i := range keys {
+		keys[i] = []byte(strconv.Itoa(i))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.GetN(keys[i%len(keys)], 3)
+	}
+}
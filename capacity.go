@@ -0,0 +1,102 @@
+package hrw
+
+import (
+	"fmt"
+	"math"
+)
+
+type (
+	// Capacity is remaining capacity reported by node, every resource is
+	// a free fraction in [0, 1], values out of range are clamped
+	Capacity struct {
+		Disk, Memory, CPU float64
+	}
+
+	// CapacityPolicy translates reported capacity of node into it's weight
+	CapacityPolicy func(n Node, c Capacity) float64
+)
+
+// minCapacityWeight is weight of exhausted nodes, weights <= 0 are
+// treated as 1, so such nodes would receive full share of keys
+const minCapacityWeight = 1e-6
+
+// SetCapacityPolicy sets policy used by ReportCapacity,
+// nil restores BottleneckCapacity
+func (r *Ring) SetCapacityPolicy(fn CapacityPolicy) {
+	r.update(func(s *Snapshot) bool {
+		s.capa = fn
+		return false
+	})
+}
+
+// ReportCapacity translates reported capacities into weights of nodes by
+// policy, see SetCapacityPolicy, and smooths them, see
+// SetCapacitySmoothing. All weights are replaced in one view, so
+// shares of nodes are re-normalized atomically. Nothing is changed and
+// error is returned when some node is unknown or capacity isn't a number.
+func (r *Ring) ReportCapacity(reports map[string]Capacity) error {
+	var err error
+
+	r.update(func(s *Snapshot) bool {
+		policy := s.capa
+		if policy == nil {
+			policy = BottleneckCapacity
+		}
+
+		weights := make(map[int]float64, len(reports))
+		for id, c := range reports {
+			i, ok := findMember(s.nodes, id)
+			if !ok {
+				err = fmt.Errorf("%w: %q", ErrUnknownNode, id)
+				return false
+			} else if math.IsNaN(c.Disk) || math.IsNaN(c.Memory) || math.IsNaN(c.CPU) {
+				err = fmt.Errorf("%w: capacity %+v of %q", ErrInvalidNode, c, id)
+				return false
+			}
+
+			w := policy(s.nodes[i].Node, c.clamp())
+			if !(w > minCapacityWeight) || math.IsInf(w, 1) {
+				w = minCapacityWeight
+			}
+			weights[i] = w
+		}
+
+		now := r.clock()
+		for i, w := range weights {
+			s.nodes[i].Weight = r.smooth(s.nodes, s.nodes[i].ID, w, now)
+		}
+		return len(weights) > 0
+	})
+
+	return err
+}
+
+// BottleneckCapacity is CapacityPolicy used by default, weight is
+// the scarcest resource, so node short of anything receives few keys
+func BottleneckCapacity(_ Node, c Capacity) float64 {
+	return math.Min(c.Disk, math.Min(c.Memory, c.CPU))
+}
+
+// WeightedCapacity returns CapacityPolicy which weight is weighted mean of
+// resources, e.g. WeightedCapacity(1, 0, 0) weights nodes by free disk
+func WeightedCapacity(disk, memory, cpu float64) CapacityPolicy {
+	total := disk + memory + cpu
+	return func(_ Node, c Capacity) float64 {
+		if total <= 0 {
+			return 0
+		}
+		return (c.Disk*disk + c.Memory*memory + c.CPU*cpu) / total
+	}
+}
+
+func (c Capacity) clamp() Capacity {
+	return Capacity{
+		Disk:   clampFraction(c.Disk),
+		Memory: clampFraction(c.Memory),
+		CPU:    clampFraction(c.CPU),
+	}
+}
+
+func clampFraction(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
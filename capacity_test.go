@@ -0,0 +1,51 @@
+package hrw
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReportCapacity(t *testing.T) {
+	r := NewRing(Node{ID: "a"}, Node{ID: "b"}, Node{ID: "c"})
+	version := r.Version()
+
+	err := r.ReportCapacity(map[string]Capacity{
+		"a": {Disk: 0.8, Memory: 0.5, CPU: 0.9},
+		"b": {Disk: 2, Memory: 1, CPU: 1},
+		"c": {},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []float64{0.5, 1, minCapacityWeight}
+	for i, n := range r.Nodes() {
+		if n.Weight != expect[i] {
+			t.Errorf("Was %#v, but expected %#v", n.Weight, expect[i])
+		}
+	}
+
+	if r.Version() == version {
+		t.Errorf("Expected version to change")
+	}
+
+	r.SetCapacityPolicy(WeightedCapacity(1, 0, 0))
+	if err := r.ReportCapacity(map[string]Capacity{"a": {Disk: 0.25}}); err != nil {
+		t.Fatal(err)
+	} else if n := r.Nodes()[0]; n.Weight != 0.25 {
+		t.Errorf("Was %#v, but expected %#v", n.Weight, 0.25)
+	}
+}
+
+func TestReportCapacityErrors(t *testing.T) {
+	r := NewRing(Node{ID: "a", Weight: 2})
+	version := r.Version()
+
+	if err := r.ReportCapacity(map[string]Capacity{"a": {Disk: 1}, "z": {}}); !errors.Is(err, ErrUnknownNode) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrUnknownNode)
+	}
+
+	if r.Version() != version || r.Nodes()[0].Weight != 2 {
+		t.Errorf("Expected nothing to change")
+	}
+}
@@ -0,0 +1,78 @@
+// Command hrw-ketama compares placement of keys by ketama and HRW and
+// prints report with expected cache miss rate at cutover as JSON.
+//
+// Usage:
+//
+//	hrw-ketama -servers servers.txt -keys keys.txt
+//	hrw-ketama -servers servers.txt -samples 100000
+//
+// Servers file has address and weight per line, keys file has key per line.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/im-kulikov/hrw/ketama"
+)
+
+func main() {
+	var (
+		serversPath = flag.String("servers", "", "path of servers file")
+		keysPath    = flag.String("keys", "", "path of keys file, samples are used when empty")
+		samples     = flag.Int("samples", 100000, "count of sampled keys")
+	)
+	flag.Parse()
+
+	if *serversPath == "" {
+		log.Fatal("-servers is required")
+	}
+
+	f, err := os.Open(*serversPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	servers, err := ketama.ParseServers(f)
+	_ = f.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var keys [][]byte
+	if *keysPath == "" {
+		for i := 0; i < *samples; i++ {
+			keys = append(keys, []byte("key-"+strconv.Itoa(i)))
+		}
+	} else if keys, err = readKeys(*keysPath); err != nil {
+		log.Fatal(err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(ketama.Compare(servers, keys)); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func readKeys(path string) ([][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		keys [][]byte
+		sc   = bufio.NewScanner(f)
+	)
+
+	for sc.Scan() {
+		keys = append(keys, append([]byte(nil), sc.Bytes()...))
+	}
+	return keys, sc.Err()
+}
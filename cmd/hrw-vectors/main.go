@@ -0,0 +1,42 @@
+// Command hrw-vectors prints canonical test vectors of hrw package as JSON.
+//
+// Usage:
+//
+//	hrw-vectors -nodes 10 -keys 100 > vectors.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/im-kulikov/hrw"
+)
+
+func main() {
+	var (
+		nodeCount  = flag.Int("nodes", 10, "count of nodes")
+		keyCount   = flag.Int("keys", 100, "count of keys")
+		nodePrefix = flag.String("node-prefix", "node-", "prefix of node names")
+		keyPrefix  = flag.String("key-prefix", "key-", "prefix of keys")
+	)
+	flag.Parse()
+
+	nodes := make([]string, 0, *nodeCount)
+	for i := 0; i < *nodeCount; i++ {
+		nodes = append(nodes, *nodePrefix+strconv.Itoa(i))
+	}
+
+	keys := make([][]byte, 0, *keyCount)
+	for i := 0; i < *keyCount; i++ {
+		keys = append(keys, []byte(*keyPrefix+strconv.Itoa(i)))
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(hrw.GenerateVectors(keys, nodes)); err != nil {
+		log.Fatal(err)
+	}
+}
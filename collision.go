@@ -0,0 +1,102 @@
+package hrw
+
+import "sort"
+
+// CollisionReport describes defects of node hashes which break balance
+// of HRW, e.g. Hasher returning only 32 bits of entropy
+type CollisionReport struct {
+	Nodes, Samples int
+	// Collisions are groups of indexes of nodes with equal hashes
+	Collisions [][]int
+	// ConstantBits is mask of bits equal in all node hashes, for 16 and more
+	// random hashes it's zero with overwhelming probability
+	ConstantBits uint64
+	// Ties is count of pairs of nodes with equal weights for sampled keys
+	Ties int
+	// MaxRatio is share of sampled keys of the most loaded node divided
+	// by fair share
+	MaxRatio float64
+}
+
+// minClusterNodes is count of nodes from which constant bits of hashes
+// are considered suspicious
+const minClusterNodes = 16
+
+// DetectCollisions scans hashes of nodes and their weights for samples
+// random keys, fn nil means MixMurmur3
+func DetectCollisions(nodes []uint64, fn WeightFunc, samples int) CollisionReport {
+	if fn == nil {
+		fn = MixMurmur3
+	}
+
+	rep := CollisionReport{Nodes: len(nodes), Samples: samples}
+	if len(nodes) == 0 {
+		return rep
+	}
+
+	groups := make(map[uint64][]int, len(nodes))
+	and, or := ^uint64(0), uint64(0)
+	for i, n := range nodes {
+		groups[n] = append(groups[n], i)
+		and, or = and&n, or|n
+	}
+	rep.ConstantBits = and | ^or
+
+	for _, g := range groups {
+		if len(g) > 1 {
+			rep.Collisions = append(rep.Collisions, g)
+		}
+	}
+	sort.Slice(rep.Collisions, func(i, j int) bool { return rep.Collisions[i][0] < rep.Collisions[j][0] })
+
+	var (
+		state   uint64
+		counts  = make([]int, len(nodes))
+		weights = make([]uint64, len(nodes))
+	)
+
+	for s := 0; s < samples; s++ {
+		hash := nextRandom(&state)
+		best := 0
+		for i, n := range nodes {
+			if weights[i] = fn(n, hash); weights[i] < weights[best] {
+				best = i
+			}
+		}
+
+		counts[best]++
+		sort.Slice(weights, func(i, j int) bool { return weights[i] < weights[j] })
+		for i := 1; i < len(weights); i++ {
+			if weights[i] == weights[i-1] {
+				rep.Ties++
+			}
+		}
+	}
+
+	if samples > 0 {
+		for _, c := range counts {
+			if ratio := float64(c) * float64(len(nodes)) / float64(samples); ratio > rep.MaxRatio {
+				rep.MaxRatio = ratio
+			}
+		}
+	}
+	return rep
+}
+
+// DetectHasherCollisions is like DetectCollisions for hashes of nodes,
+// nil elements are skipped
+func DetectHasherCollisions(nodes []Hasher, samples int) CollisionReport {
+	hashes := make([]uint64, 0, len(nodes))
+	for _, n := range nodes {
+		if n != nil {
+			hashes = append(hashes, n.Hash())
+		}
+	}
+	return DetectCollisions(hashes, nil, samples)
+}
+
+// Suspicious reports whether hashes have collisions, tied weights or
+// constant bits while there are enough nodes to expect them random
+func (r CollisionReport) Suspicious() bool {
+	return len(r.Collisions) > 0 || r.Ties > 0 || r.Nodes >= minClusterNodes && r.ConstantBits != 0
+}
@@ -0,0 +1,40 @@
+package hrw
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type hash32 string
+
+func (h hash32) Hash() uint64 { return uint64(uint32(Hash([]byte(h)))) }
+
+type hash64 string
+
+func (h hash64) Hash() uint64 { return Hash([]byte(h)) }
+
+func TestDetectCollisions(t *testing.T) {
+	var good, weak []Hasher
+	for i := 0; i < 32; i++ {
+		good = append(good, hash64("node-"+strconv.Itoa(i)))
+		weak = append(weak, hash32("node-"+strconv.Itoa(i)))
+	}
+
+	if rep := DetectHasherCollisions(good, 1000); rep.Suspicious() || rep.MaxRatio > 2 {
+		t.Errorf("Was %#v, but expected good hashes not to be suspicious", rep)
+	}
+
+	if rep := DetectHasherCollisions(weak, 1000); !rep.Suspicious() || rep.ConstantBits != 0xffffffff00000000 {
+		t.Errorf("Was %#v, but expected upper 32 bits to be constant", rep)
+	}
+
+	rep := DetectCollisions([]uint64{1, 2, 1, 3, 2}, nil, 10)
+	if expect := [][]int{{0, 2}, {1, 4}}; !reflect.DeepEqual(rep.Collisions, expect) {
+		t.Errorf("Was %#v, but expected %#v", rep.Collisions, expect)
+	}
+
+	if rep.Ties != 20 || !rep.Suspicious() {
+		t.Errorf("Was %d ties, but expected 20", rep.Ties)
+	}
+}
@@ -0,0 +1,81 @@
+package hrw
+
+type (
+	// Config holds settings applied by NewRingWithOptions and NewSorter,
+	// zero value means defaults of package-level functions
+	Config struct {
+		// Hash hashes keys and node IDs of Ring, nil means Hash
+		Hash HashFunc
+		// Weight combines node and key hashes, nil means default
+		// of Algorithm
+		Weight WeightFunc
+		// Algorithm orders nodes, V1 by default
+		Algorithm Algorithm
+		// Strict makes Sorter panic for unsupported input like strict
+		// mode does, see SetStrict
+		Strict bool
+		// Parallelism is count of workers used by Sorter.Assign,
+		// values <= 0 mean GOMAXPROCS
+		Parallelism int
+	}
+
+	// Option changes Config
+	Option func(*Config)
+)
+
+// NewConfig returns Config with options applied in order
+func NewConfig(opts ...Option) Config {
+	var cfg Config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithConfig replaces whole Config, following options change it
+func WithConfig(cfg Config) Option {
+	return func(c *Config) { *c = cfg }
+}
+
+// WithHash sets function hashing keys and node IDs
+func WithHash(fn HashFunc) Option {
+	return func(c *Config) { c.Hash = fn }
+}
+
+// WithWeightFunc sets function combining node and key hashes, it's used
+// for slices too, except NSPCC algorithm which returns
+// ErrUnsupportedWeightFunc
+func WithWeightFunc(fn WeightFunc) Option {
+	return func(c *Config) { c.Weight = fn }
+}
+
+// WithStrictErrors makes Sorter panic for unsupported input
+func WithStrictErrors() Option {
+	return func(c *Config) { c.Strict = true }
+}
+
+// WithParallelism sets count of workers used by bulk operations
+func WithParallelism(workers int) Option {
+	return func(c *Config) { c.Parallelism = workers }
+}
+
+// WithAlgorithmVersion sets algorithm ordering nodes
+func WithAlgorithmVersion(alg Algorithm) Option {
+	return func(c *Config) { c.Algorithm = alg }
+}
+
+// NewRingWithOptions creates Ring with given nodes configured by options,
+// Strict and Parallelism aren't used by Ring
+func NewRingWithOptions(nodes []Node, opts ...Option) *Ring {
+	cfg := NewConfig(opts...)
+
+	r := new(Ring)
+	r.update(func(s *Snapshot) bool {
+		s.hashFn, s.weight, s.alg = cfg.Hash, cfg.Weight, cfg.Algorithm
+		for _, n := range nodes {
+			s.nodes = upsertMember(s.nodes, n, s.hashFn)
+		}
+		return true
+	})
+	return r
+}
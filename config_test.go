@@ -0,0 +1,121 @@
+package hrw
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestNewConfig(t *testing.T) {
+	cfg := NewConfig(
+		WithConfig(Config{Parallelism: 3}),
+		WithAlgorithmVersion(V2),
+		WithStrictErrors(),
+	)
+
+	if expect := (Config{Algorithm: V2, Strict: true, Parallelism: 3}); !reflect.DeepEqual(cfg, expect) {
+		t.Errorf("Was %#v, but expected %#v", cfg, expect)
+	}
+}
+
+func TestNewRingWithOptions(t *testing.T) {
+	var (
+		nodes  = testNodes(5)
+		actual = NewRingWithOptions(nodes,
+			WithHash(NewHMACHash([]byte("secret"))),
+			WithWeightFunc(MixSplitMix64),
+			WithAlgorithmVersion(V2))
+		expect = NewRing(nodes...)
+	)
+
+	expect.SetHash(NewHMACHash([]byte("secret")))
+	expect.SetWeightFunc(MixSplitMix64)
+	expect.SetAlgorithm(V2)
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		a, e := nodeIDs(actual.GetN([]byte(key), 5)), nodeIDs(expect.GetN([]byte(key), 5))
+		if !reflect.DeepEqual(a, e) {
+			t.Errorf("Was %#v, but expected %#v", a, e)
+		}
+	}
+}
+
+func TestNewSorter(t *testing.T) {
+	nodes := []uint64{1, 2, 3, 4, 5, 6, 7, 8}
+
+	s := NewSorter(WithWeightFunc(MixSplitMix64))
+	if actual, expect := s.SortByWeight(nodes, 42), SortByWeightFunc(nodes, 42, MixSplitMix64); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	actual, expect := []int{1, 2, 3, 4, 5}, []int{1, 2, 3, 4, 5}
+	NewSorter(WithAlgorithmVersion(V2)).SortSliceByValue(actual, 42)
+	V2.SortSliceByValue(expect, 42)
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic for unsupported input")
+		}
+	}()
+	NewSorter(WithStrictErrors()).SortSliceByValue(42, 0)
+}
+
+func TestSorterWeightFuncSlices(t *testing.T) {
+	constant := func(uint64, uint64) uint64 { return 0 }
+
+	for _, alg := range []Algorithm{V1, V2, V3} {
+		s := NewSorter(WithWeightFunc(constant), WithAlgorithmVersion(alg))
+		actual := []string{"a", "b", "c", "d", "e"}
+		if err := s.TrySortSliceByValue(actual, 42); err != nil {
+			t.Fatal(err)
+		}
+
+		// V1 mixes weights twice, so equal weights keep input order,
+		// other algorithms order them by hashes of values
+		expect := []string{"a", "b", "c", "d", "e"}
+		if alg != V1 {
+			sort.Slice(expect, func(i, j int) bool {
+				return Hash([]byte(expect[i])) < Hash([]byte(expect[j]))
+			})
+		}
+
+		if !reflect.DeepEqual(actual, expect) {
+			t.Errorf("%s: Was %#v, but expected %#v", alg, actual, expect)
+		}
+	}
+
+	s := NewSorter(WithWeightFunc(constant), WithAlgorithmVersion(NSPCC))
+	if err := s.TrySortSliceByValue([]string{"a"}, 42); !errors.Is(err, ErrUnsupportedWeightFunc) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrUnsupportedWeightFunc)
+	}
+}
+
+func TestSorterAssign(t *testing.T) {
+	var (
+		nodes  = []uint64{1, 2, 3, 4, 5, 6, 7, 8}
+		hashes = make([]uint64, 1000)
+		dst    = make([]int, len(hashes))
+	)
+
+	for i := range hashes {
+		hashes[i] = uint64(i) * 0x9e3779b97f4a7c15
+	}
+
+	NewSorter(WithParallelism(4), WithAlgorithmVersion(V3)).Assign(dst, nodes, hashes)
+	for i, hash := range hashes {
+		if expect := int(V3.SortByWeight(nodes, hash)[0]); dst[i] != expect {
+			t.Fatalf("Was %d, but expected %d", dst[i], expect)
+		}
+	}
+
+	NewSorter(WithParallelism(1)).Assign(dst, nodes, hashes)
+	for i, hash := range hashes {
+		if expect := Top1Index(nodes, hash); dst[i] != expect {
+			t.Fatalf("Was %d, but expected %d", dst[i], expect)
+		}
+	}
+}
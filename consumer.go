@@ -0,0 +1,124 @@
+package hrw
+
+import (
+	"math"
+	"sort"
+)
+
+type (
+	// PartitionAssignment is assignment of partitions (queues, shards)
+	// to consumers
+	PartitionAssignment struct {
+		// Owners maps consumer ID to it's partitions in ascending order,
+		// every selectable consumer is present
+		Owners map[string][]int
+		// Moves are partitions which owner differs from previous one
+		// in ascending order of partitions
+		Moves []PartitionMove
+	}
+
+	// PartitionMove is a partition changing it's owner, From is empty
+	// for partitions without previous owner
+	PartitionMove struct {
+		Partition int
+		From      string
+		To        string
+	}
+)
+
+// AssignPartitions assigns partitions numbered [0, partitions) to
+// selectable consumers. Every partition goes to the most preferable by HRW
+// consumer that has capacity left, capacity of consumer is it's share of
+// partitions by Weight rounded up, so assignment stays balanced even for
+// few partitions. Previous maps consumer ID to partitions it owned, moves
+// are reported against it. When sticky is set partitions stay with their
+// previous owners while they have capacity, which minimizes moves after
+// assignment made by other strategy.
+func AssignPartitions(partitions int, consumers []Node, previous map[string][]int, sticky bool) PartitionAssignment {
+	var (
+		s      = NewRing(consumers...).view()
+		owner  = make([]string, partitions)
+		before = make([]string, partitions)
+		ranks  = make([][]Node, partitions)
+		left   = make(map[string]int)
+		total  float64
+	)
+
+	for id, list := range previous {
+		for _, p := range list {
+			if p >= 0 && p < partitions {
+				before[p] = id
+			}
+		}
+	}
+
+	for i := range s.nodes {
+		if s.nodes[i].State.selectable() {
+			total += s.nodes[i].weight()
+		}
+	}
+
+	result := PartitionAssignment{Owners: make(map[string][]int)}
+	for i := range s.nodes {
+		if n := s.nodes[i].Node; n.State.selectable() {
+			left[n.ID] = int(math.Ceil(float64(partitions) * n.weight() / total))
+			result.Owners[n.ID] = []int{}
+		}
+	}
+
+	for p := range ranks {
+		ranks[p] = s.pick(HashUint(uint64(p)), s.Len())
+	}
+
+	if sticky {
+		// partitions preferring their previous owners are kept first
+		order := make([]int, 0, partitions)
+		for p := range ranks {
+			if before[p] != "" {
+				order = append(order, p)
+			}
+		}
+
+		sort.SliceStable(order, func(i, j int) bool {
+			return rankOf(ranks[order[i]], before[order[i]]) < rankOf(ranks[order[j]], before[order[j]])
+		})
+
+		for _, p := range order {
+			if left[before[p]] > 0 {
+				owner[p] = before[p]
+				left[owner[p]]--
+			}
+		}
+	}
+
+	for p := range ranks {
+		for _, n := range ranks[p] {
+			if owner[p] != "" {
+				break
+			} else if left[n.ID] > 0 {
+				owner[p] = n.ID
+				left[n.ID]--
+			}
+		}
+
+		if owner[p] == "" {
+			continue
+		}
+
+		result.Owners[owner[p]] = append(result.Owners[owner[p]], p)
+		if owner[p] != before[p] {
+			result.Moves = append(result.Moves, PartitionMove{Partition: p, From: before[p], To: owner[p]})
+		}
+	}
+	return result
+}
+
+// rankOf returns position of node in list, len(list) when it's missing
+func rankOf(list []Node, id string) int {
+	for i, n := range list {
+		if n.ID == id {
+			return i
+		}
+	}
+	return len(list)
+}
@@ -0,0 +1,61 @@
+package hrw
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAssignPartitions(t *testing.T) {
+	consumers := testNodes(3)
+	a := AssignPartitions(10, consumers, nil, false)
+
+	var total int
+	for id, list := range a.Owners {
+		if len(list) > 4 {
+			t.Errorf("Was %d partitions of %q, but expected at most %d", len(list), id, 4)
+		}
+		total += len(list)
+	}
+
+	if total != 10 || len(a.Moves) != 10 {
+		t.Errorf("Was %d partitions and %d moves, but expected %d", total, len(a.Moves), 10)
+	}
+
+	again := AssignPartitions(10, consumers, a.Owners, false)
+	if !reflect.DeepEqual(again.Owners, a.Owners) || len(again.Moves) != 0 {
+		t.Errorf("Was %#v, but expected %#v without moves", again, a.Owners)
+	}
+
+	// removal of consumer moves only it's partitions
+	gone := consumers[0].ID
+	next := AssignPartitions(10, consumers[1:], a.Owners, true)
+	for _, m := range next.Moves {
+		if m.From != gone {
+			t.Errorf("Was %#v, but expected only partitions of %q to move", m, gone)
+		}
+	}
+	if len(next.Moves) != len(a.Owners[gone]) {
+		t.Errorf("Was %d, but expected %d", len(next.Moves), len(a.Owners[gone]))
+	}
+}
+
+func TestAssignPartitionsSticky(t *testing.T) {
+	var (
+		consumers = testNodes(2)
+		previous  = map[string][]int{
+			consumers[0].ID: {0, 1, 2},
+			consumers[1].ID: {3, 4, 5},
+		}
+	)
+
+	if a := AssignPartitions(6, consumers, previous, true); !reflect.DeepEqual(a.Owners, previous) || a.Moves != nil {
+		t.Errorf("Was %#v, but expected %#v", a.Owners, previous)
+	}
+
+	// weighted consumer takes bigger share
+	consumers[1].Weight = 2
+	a := AssignPartitions(6, consumers, previous, true)
+	if len(a.Owners[consumers[1].ID]) != 4 || len(a.Moves) != 1 {
+		t.Errorf("Was %#v, but expected one partition moved to %q", a, consumers[1].ID)
+	}
+}
@@ -0,0 +1,65 @@
+package hrw
+
+// CostFunc calculates weight of node used for selection from it's
+// attributes, like NeoFS placement combines capacity and price.
+// Values <= 0 treated as 1.
+type CostFunc func(n Node) float64
+
+// SetCost sets function used by Ring to calculate weights of nodes,
+// nil restores Node.Weight. It's called for every active node on every
+// selection, so it should be cheap.
+func (r *Ring) SetCost(fn CostFunc) {
+	r.update(func(s *Snapshot) bool {
+		s.cost = fn
+		return true
+	})
+}
+
+// Attr returns cost equal to value of attribute name
+func Attr(name string) CostFunc {
+	return func(n Node) float64 {
+		return n.Attrs[name]
+	}
+}
+
+// MaxNorm returns cost equal to value of attribute name divided by max,
+// e.g. nodes with more capacity are more preferable
+func MaxNorm(name string, max float64) CostFunc {
+	return func(n Node) float64 {
+		return n.Attrs[name] / max
+	}
+}
+
+// ReverseMinNorm returns cost equal to min divided by value of attribute
+// name, e.g. nodes with lower price are more preferable
+func ReverseMinNorm(name string, min float64) CostFunc {
+	return func(n Node) float64 {
+		v := n.Attrs[name]
+		if v <= 0 {
+			return 1
+		}
+		return min / v
+	}
+}
+
+// Product returns cost equal to product of costs
+func Product(fns ...CostFunc) CostFunc {
+	return func(n Node) float64 {
+		result := 1.0
+		for _, fn := range fns {
+			result *= fn(n)
+		}
+		return result
+	}
+}
+
+func (fn CostFunc) weight(n Node) float64 {
+	if fn == nil {
+		return n.weight()
+	}
+
+	if w := fn(n); w > 0 {
+		return w
+	}
+	return 1
+}
@@ -0,0 +1,61 @@
+package hrw
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestCostFunc(t *testing.T) {
+	n := Node{ID: "a", Weight: 3, Attrs: map[string]float64{"capacity": 50, "price": 4}}
+
+	cases := []struct {
+		name   string
+		fn     CostFunc
+		expect float64
+	}{
+		{name: "nil", fn: nil, expect: 3},
+		{name: "attr", fn: Attr("capacity"), expect: 50},
+		{name: "unknown attr", fn: Attr("unknown"), expect: 1},
+		{name: "max norm", fn: MaxNorm("capacity", 100), expect: 0.5},
+		{name: "reverse min norm", fn: ReverseMinNorm("price", 2), expect: 0.5},
+		{name: "product", fn: Product(MaxNorm("capacity", 100), ReverseMinNorm("price", 2)), expect: 0.25},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.fn.weight(n); actual != tc.expect {
+				t.Errorf("Was %#v, but expected %#v", actual, tc.expect)
+			}
+		})
+	}
+}
+
+func TestRingSetCost(t *testing.T) {
+	const keys = 100000
+	var (
+		key    = make([]byte, 8)
+		counts = make(map[string]int)
+		r      = NewRing(
+			Node{ID: "a", Weight: 3, Attrs: map[string]float64{"capacity": 1, "price": 1}},
+			Node{ID: "b", Weight: 1, Attrs: map[string]float64{"capacity": 6, "price": 2}},
+		)
+	)
+
+	r.SetCost(Product(Attr("capacity"), ReverseMinNorm("price", 1)))
+	for i := uint64(0); i < keys; i++ {
+		binary.BigEndian.PutUint64(key, i)
+		n, _ := r.Get(key)
+		counts[n.ID]++
+	}
+
+	if share := float64(counts["b"]) / keys; share < 0.74 || share > 0.76 {
+		t.Errorf("Node b received %.3f of keys, expected 0.75", share)
+	}
+
+	r.SetCost(nil)
+	expect := NewRing(Node{ID: "a", Weight: 3}, Node{ID: "b", Weight: 1}).GetN(testKey, 2)
+	if actual := r.GetN(testKey, 2); !reflect.DeepEqual(nodeIDs(actual), nodeIDs(expect)) {
+		t.Errorf("Was %#v, but expected %#v", nodeIDs(actual), nodeIDs(expect))
+	}
+}
@@ -0,0 +1,40 @@
+package hrw
+
+// GetReplicas returns per-datacenter preference lists for key, counts maps
+// Node.Datacenter to required count of replicas in it. All lists come
+// from one ranking of nodes, so they are consistent with GetN: every list
+// is GetN result filtered by datacenter. Lists of datacenters without
+// enough active nodes are shorter than requested.
+func (r *Ring) GetReplicas(key []byte, counts map[string]int) map[string][]Node {
+	return r.view().GetReplicas(key, counts)
+}
+
+// GetReplicas is like Ring.GetReplicas
+func (s *Snapshot) GetReplicas(key []byte, counts map[string]int) map[string][]Node {
+	var (
+		missing int
+		result  = make(map[string][]Node, len(counts))
+	)
+
+	for _, c := range counts {
+		if c > 0 {
+			missing += c
+		}
+	}
+
+	if missing == 0 {
+		return result
+	}
+
+	for _, n := range s.GetN(key, s.Len()) {
+		if len(result[n.Datacenter]) >= counts[n.Datacenter] {
+			continue
+		}
+
+		result[n.Datacenter] = append(result[n.Datacenter], n)
+		if missing--; missing == 0 {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,32 @@
+package hrw
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetReplicas(t *testing.T) {
+	nodes := testNodes(9)
+	for i := range nodes {
+		nodes[i].Datacenter = []string{"dc-a", "dc-b", "dc-c"}[i%3]
+	}
+
+	r := NewRing(nodes...)
+	order := r.GetN(testKey, len(nodes))
+	actual := r.GetReplicas(testKey, map[string]int{"dc-a": 2, "dc-b": 1, "dc-d": 1})
+
+	expect := make(map[string][]Node)
+	for _, n := range order {
+		if dc := n.Datacenter; dc == "dc-a" && len(expect[dc]) < 2 || dc == "dc-b" && len(expect[dc]) < 1 {
+			expect[dc] = append(expect[dc], n)
+		}
+	}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	if actual := r.GetReplicas(testKey, nil); len(actual) != 0 {
+		t.Errorf("Was %#v, but expected empty result", actual)
+	}
+}
@@ -0,0 +1,153 @@
+// Package dbshard maps keys to database shards selected by HRW. Connection
+// info of shard is stored in labels of it's node, so membership is managed
+// by hrw.Ring and Router opens and caches *sql.DB of every DSN.
+package dbshard
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/im-kulikov/hrw"
+)
+
+const (
+	// LabelDSN is label of node holding DSN of shard primary
+	LabelDSN = "dsn"
+	// LabelReplicaPrefix prefixes labels of node holding DSNs of shard
+	// replicas, it's followed by index of replica
+	LabelReplicaPrefix = "dsn.replica."
+)
+
+// ErrNoShard returned when there are no shards for key
+var ErrNoShard = errors.New("dbshard: no shard")
+
+type (
+	// Shard is connection info of database shard
+	Shard struct {
+		// ID of node of shard
+		ID string
+		// DSN of primary receiving writes
+		DSN string
+		// Replicas are DSNs of read replicas
+		Replicas []string
+	}
+
+	// Router returns connections of shards for keys,
+	// it's safe for concurrent use
+	Router struct {
+		ring *hrw.Ring
+		open func(dsn string) (*sql.DB, error)
+
+		mu  sync.Mutex
+		dbs map[string]*sql.DB
+	}
+)
+
+// Node returns node of shard with given weight
+func (s Shard) Node(weight float64) hrw.Node {
+	labels := map[string]string{LabelDSN: s.DSN}
+	for i, dsn := range s.Replicas {
+		labels[LabelReplicaPrefix+strconv.Itoa(i)] = dsn
+	}
+	return hrw.Node{ID: s.ID, Weight: weight, Labels: labels}
+}
+
+// ShardOf returns Shard stored in labels of node
+func ShardOf(n hrw.Node) Shard {
+	s := Shard{ID: n.ID, DSN: n.Labels[LabelDSN]}
+
+	var indexes []int
+	for label := range n.Labels {
+		if !strings.HasPrefix(label, LabelReplicaPrefix) {
+			continue
+		}
+
+		if i, err := strconv.Atoi(label[len(LabelReplicaPrefix):]); err == nil {
+			indexes = append(indexes, i)
+		}
+	}
+
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		s.Replicas = append(s.Replicas, n.Labels[LabelReplicaPrefix+strconv.Itoa(i)])
+	}
+	return s
+}
+
+// NewRouter creates Router of shards of ring, open is called once for
+// every DSN, e.g. func(dsn string) (*sql.DB, error) { return sql.Open("pgx", dsn) }
+func NewRouter(ring *hrw.Ring, open func(dsn string) (*sql.DB, error)) *Router {
+	return &Router{ring: ring, open: open, dbs: make(map[string]*sql.DB)}
+}
+
+// Shard returns shard of key
+func (r *Router) Shard(key []byte) (Shard, error) {
+	n, ok := r.ring.Get(key)
+	if !ok {
+		return Shard{}, fmt.Errorf("%w for %q", ErrNoShard, key)
+	}
+	return ShardOf(n), nil
+}
+
+// Writer returns connection of primary of key shard
+func (r *Router) Writer(key []byte) (*sql.DB, error) {
+	s, err := r.Shard(key)
+	if err != nil {
+		return nil, err
+	}
+	return r.db(s.DSN)
+}
+
+// Reader returns connection of replica of key shard, replica is selected
+// by HRW, so reads of the same key hit the same replica. Primary is
+// returned for shards without replicas.
+func (r *Router) Reader(key []byte) (*sql.DB, error) {
+	s, err := r.Shard(key)
+	if err != nil {
+		return nil, err
+	} else if len(s.Replicas) == 0 {
+		return r.db(s.DSN)
+	}
+
+	replicas := append([]string(nil), s.Replicas...)
+	hrw.SortSliceByValue(replicas, hrw.Hash(key))
+	return r.db(replicas[0])
+}
+
+// Close closes all opened connections
+func (r *Router) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var first error
+	for dsn, db := range r.dbs {
+		if err := db.Close(); err != nil && first == nil {
+			first = err
+		}
+		delete(r.dbs, dsn)
+	}
+	return first
+}
+
+// db returns cached connection of dsn or opens new one
+func (r *Router) db(dsn string) (*sql.DB, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if db, ok := r.dbs[dsn]; ok {
+		return db, nil
+	}
+
+	db, err := r.open(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	r.dbs[dsn] = db
+	return db, nil
+}
@@ -0,0 +1,74 @@
+package dbshard
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/im-kulikov/hrw"
+)
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("not implemented")
+}
+
+func init() {
+	sql.Register("dbshard-fake", fakeDriver{})
+}
+
+func TestShardNode(t *testing.T) {
+	s := Shard{ID: "a", DSN: "primary", Replicas: []string{"r0", "r1", "r2"}}
+	if actual := ShardOf(s.Node(1)); !reflect.DeepEqual(actual, s) {
+		t.Errorf("Was %#v, but expected %#v", actual, s)
+	}
+}
+
+func TestRouter(t *testing.T) {
+	var (
+		opened []string
+		ring   = hrw.NewRing(
+			Shard{ID: "a", DSN: "a-primary", Replicas: []string{"a-r0", "a-r1"}}.Node(1),
+			Shard{ID: "b", DSN: "b-primary"}.Node(1),
+		)
+		router = NewRouter(ring, func(dsn string) (*sql.DB, error) {
+			opened = append(opened, dsn)
+			return sql.Open("dbshard-fake", dsn)
+		})
+	)
+	defer router.Close()
+
+	for _, key := range []string{"x", "y", "z", "x"} {
+		s, err := router.Shard([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w, err := router.Writer([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := router.Reader([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		} else if (len(s.Replicas) == 0) != (r == w) {
+			t.Errorf("Expected reads of %q to hit replica when shard has one", key)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, dsn := range opened {
+		if seen[dsn] {
+			t.Errorf("Expected %q to be opened once", dsn)
+		}
+		seen[dsn] = true
+	}
+
+	if _, err := NewRouter(hrw.NewRing(), nil).Writer([]byte("x")); !errors.Is(err, ErrNoShard) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrNoShard)
+	}
+}
@@ -0,0 +1,66 @@
+package hrw
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Built with hrwdebug tag package validates results of every sort and
+// panics with diagnostics when order isn't a permutation of input or
+// differs between repeated calls. Checks are compiled out otherwise.
+//
+//	go test -tags hrwdebug ./...
+
+// debugCheckSorted validates h sorted for nodes and hash by fn
+func debugCheckSorted(h hashed, nodes []uint64, hash uint64, fn WeightFunc) {
+	if err := checkPermutation(h.sorted, h.length); err != nil {
+		panic(fmt.Sprintf("hrw: SortByWeight of %d nodes for hash %#x: %v", len(nodes), hash, err))
+	}
+
+	for i := 1; i < h.length; i++ {
+		if h.Less(i, i-1) {
+			panic(fmt.Sprintf("hrw: SortByWeight of %d nodes for hash %#x: position %d (node %d) precedes %d (node %d)",
+				len(nodes), hash, i, h.sorted[i], i-1, h.sorted[i-1]))
+		}
+	}
+
+	again := hashed{length: len(nodes), nodes: nodes}
+	for i, node := range nodes {
+		again.sorted = append(again.sorted, int32(i))
+		again.weight = append(again.weight, fn(node, hash))
+	}
+	sort.Sort(again)
+
+	for i := range again.sorted {
+		if again.sorted[i] != h.sorted[i] {
+			panic(fmt.Sprintf("hrw: SortByWeight of %d nodes for hash %#x isn't deterministic: %v and %v",
+				len(nodes), hash, h.sorted, again.sorted))
+		}
+	}
+}
+
+// debugCheckRule validates rule applied to slice of length
+func debugCheckRule(rule []int32, length int) {
+	if err := checkPermutation(rule, length); err != nil {
+		panic(fmt.Sprintf("hrw: rule of slice of %d elements: %v", length, err))
+	}
+}
+
+// checkPermutation reports error unless order holds every index
+// in [0, length) exactly once
+func checkPermutation(order []int32, length int) error {
+	if len(order) != length {
+		return fmt.Errorf("%d indexes for %d elements", len(order), length)
+	}
+
+	seen := newBitset(length)
+	for i, v := range order {
+		if v < 0 || int(v) >= length {
+			return fmt.Errorf("index %d out of range at position %d", v, i)
+		} else if seen.has(uint64(v)) {
+			return fmt.Errorf("index %d repeated at position %d", v, i)
+		}
+		seen.set(uint64(v))
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+//go:build hrwdebug
+// +build hrwdebug
+
+package hrw
+
+// debugChecks enables validation of every sort, see debug.go
+const debugChecks = true
@@ -0,0 +1,35 @@
+package hrw
+
+import "testing"
+
+func TestCheckPermutation(t *testing.T) {
+	for _, tc := range []struct {
+		order []int32
+		ok    bool
+	}{
+		{order: []int32{2, 0, 1}, ok: true},
+		{order: []int32{2, 0}},
+		{order: []int32{2, 0, 3}},
+		{order: []int32{2, 0, 2}},
+		{order: []int32{2, 0, -1}},
+	} {
+		if err := checkPermutation(tc.order, 3); (err == nil) != tc.ok {
+			t.Errorf("Was %v for %v, but expected ok=%v", err, tc.order, tc.ok)
+		}
+	}
+}
+
+func TestDebugCheckSorted(t *testing.T) {
+	nodes := []uint64{1, 2, 3, 4}
+	h := sortByWeightFunc(nodes, 42, MixMurmur3)
+	debugCheckSorted(h, nodes, 42, MixMurmur3)
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic for unsorted order")
+		}
+	}()
+
+	h.sorted[0], h.sorted[1] = h.sorted[1], h.sorted[0]
+	debugCheckSorted(h, nodes, 42, MixMurmur3)
+}
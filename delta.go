@@ -0,0 +1,89 @@
+package hrw
+
+type (
+	// DeltaOp is a kind of membership change
+	DeltaOp uint8
+
+	// Delta is an incremental membership change. Seq is a sequence number
+	// of change for the node, deltas with Seq lower or equal to the last
+	// applied one for the same node are ignored, so deltas may be delivered
+	// out of order or more than once.
+	Delta struct {
+		Seq uint64
+		Op  DeltaOp
+		// Node to add, for DeltaRemove only ID is used,
+		// for DeltaWeight only ID and Weight are used.
+		Node Node
+	}
+)
+
+const (
+	// DeltaAdd adds node or replaces known one
+	DeltaAdd DeltaOp = iota + 1
+	// DeltaRemove removes node
+	DeltaRemove
+	// DeltaWeight updates weight of known node
+	DeltaWeight
+)
+
+// Apply applies deltas to Ring and returns count of applied ones.
+// Stale deltas and weight updates of unknown nodes are skipped.
+// Removed nodes leave tombstones, so delayed DeltaAdd can't resurrect them.
+func (r *Ring) Apply(deltas ...Delta) int {
+	var applied int
+	r.update(func(s *Snapshot) bool {
+		for _, d := range deltas {
+			if r.applyDelta(s, d) {
+				applied++
+			}
+		}
+		return applied > 0
+	})
+	return applied
+}
+
+// PruneTombstones forgets removed nodes with sequence lower or equal to seq.
+// Call it when deltas older than seq can't be delivered anymore.
+func (r *Ring) PruneTombstones(seq uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, s := range r.tombstones {
+		if s <= seq {
+			delete(r.tombstones, id)
+		}
+	}
+}
+
+func (r *Ring) applyDelta(s *Snapshot, d Delta) bool {
+	i, ok := findMember(s.nodes, d.Node.ID)
+	switch {
+	case ok && d.Seq <= s.nodes[i].seq:
+		return false
+	case !ok && d.Seq <= r.tombstones[d.Node.ID]:
+		return false
+	}
+
+	switch d.Op {
+	case DeltaAdd:
+		s.nodes = upsertMember(s.nodes, d.Node, s.hashFn)
+		i, _ = findMember(s.nodes, d.Node.ID)
+		s.nodes[i].seq = d.Seq
+		delete(r.tombstones, d.Node.ID)
+	case DeltaRemove:
+		if r.tombstones == nil {
+			r.tombstones = make(map[string]uint64)
+		}
+		r.tombstones[d.Node.ID] = d.Seq
+		s.nodes = removeMember(s.nodes, d.Node.ID)
+	case DeltaWeight:
+		if !ok {
+			return false
+		}
+		s.nodes[i].Weight = d.Node.Weight
+		s.nodes[i].seq = d.Seq
+	default:
+		return false
+	}
+	return true
+}
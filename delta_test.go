@@ -0,0 +1,80 @@
+package hrw
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRingApply(t *testing.T) {
+	t.Run("in order", func(t *testing.T) {
+		r := NewRing()
+		applied := r.Apply(
+			Delta{Seq: 1, Op: DeltaAdd, Node: Node{ID: "a"}},
+			Delta{Seq: 1, Op: DeltaAdd, Node: Node{ID: "b"}},
+			Delta{Seq: 2, Op: DeltaWeight, Node: Node{ID: "a", Weight: 2}},
+			Delta{Seq: 2, Op: DeltaRemove, Node: Node{ID: "b"}},
+		)
+
+		expect := []Node{{ID: "a", Weight: 2}}
+		if actual := r.Nodes(); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+		if applied != 4 {
+			t.Errorf("Was %d, but expected %d", applied, 4)
+		}
+	})
+
+	t.Run("out of order", func(t *testing.T) {
+		r := NewRing()
+		applied := r.Apply(
+			Delta{Seq: 3, Op: DeltaWeight, Node: Node{ID: "a", Weight: 3}},
+			Delta{Seq: 2, Op: DeltaAdd, Node: Node{ID: "a", Weight: 2}},
+			Delta{Seq: 1, Op: DeltaAdd, Node: Node{ID: "a", Weight: 1}},
+			Delta{Seq: 2, Op: DeltaAdd, Node: Node{ID: "a", Weight: 2}},
+		)
+
+		expect := []Node{{ID: "a", Weight: 2}}
+		if actual := r.Nodes(); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+		if applied != 1 {
+			t.Errorf("Was %d, but expected %d", applied, 1)
+		}
+	})
+
+	t.Run("tombstones", func(t *testing.T) {
+		r := NewRing()
+		r.Apply(
+			Delta{Seq: 5, Op: DeltaRemove, Node: Node{ID: "a"}},
+			Delta{Seq: 4, Op: DeltaAdd, Node: Node{ID: "a"}},
+		)
+		if r.Len() != 0 {
+			t.Errorf("Removed node was resurrected by stale delta")
+		}
+
+		r.PruneTombstones(5)
+		r.Apply(Delta{Seq: 4, Op: DeltaAdd, Node: Node{ID: "a"}})
+		if r.Len() != 1 {
+			t.Errorf("Expected node to be added after pruning tombstones")
+		}
+	})
+
+	t.Run("unknown node", func(t *testing.T) {
+		r := NewRing()
+		if applied := r.Apply(
+			Delta{Seq: 1, Op: DeltaWeight, Node: Node{ID: "a", Weight: 2}},
+			Delta{Seq: 1, Node: Node{ID: "a"}},
+		); applied != 0 {
+			t.Errorf("Was %d, but expected %d", applied, 0)
+		}
+	})
+
+	t.Run("full add keeps sequence", func(t *testing.T) {
+		r := NewRing()
+		r.Apply(Delta{Seq: 2, Op: DeltaAdd, Node: Node{ID: "a"}})
+		r.Add(Node{ID: "a", Weight: 5})
+		if applied := r.Apply(Delta{Seq: 1, Op: DeltaRemove, Node: Node{ID: "a"}}); applied != 0 {
+			t.Errorf("Was %d, but expected %d", applied, 0)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package hrw
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteDOT writes Graphviz graph of nodes placement for sample of keys:
+// nodes are grouped into clusters by topology (see SetTopology), sized by
+// share of keys they are the most preferable for and connected by edges
+// of replica chains, e.g. edge a -> b counts keys with b following a among
+// replicas most preferable nodes.
+func (s *Snapshot) WriteDOT(w io.Writer, keys [][]byte, replicas int) error {
+	var (
+		shares = make(map[string]int)
+		edges  = make(map[[2]string]int)
+	)
+
+	for _, key := range keys {
+		nodes := s.GetN(key, replicas)
+		for i := range nodes {
+			if i == 0 {
+				shares[nodes[i].ID]++
+			} else {
+				edges[[2]string{nodes[i-1].ID, nodes[i].ID}]++
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph hrw {")
+	fmt.Fprintln(bw, "\tnode [shape=box];")
+	writeDomain(bw, s.Topology(), shares, len(keys), "\t", new(int))
+
+	pairs := make([][2]string, 0, len(edges))
+	for e := range edges {
+		pairs = append(pairs, e)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i][0] < pairs[j][0] || pairs[i][0] == pairs[j][0] && pairs[i][1] < pairs[j][1]
+	})
+
+	for _, e := range pairs {
+		fmt.Fprintf(bw, "\t\"%s\" -> \"%s\" [label=\"%d\"];\n", escapeDOT(e[0]), escapeDOT(e[1]), edges[e])
+	}
+
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+func writeDomain(w io.Writer, d *Domain, shares map[string]int, total int, indent string, clusters *int) {
+	for _, n := range d.Nodes {
+		var share float64
+		if total > 0 {
+			share = float64(shares[n.ID]) / float64(total)
+		}
+
+		id := escapeDOT(n.ID)
+		fmt.Fprintf(w, "%s\"%s\" [label=\"%s\\n%.1f%%\", width=%.2f];\n",
+			indent, id, id, share*100, 0.75+share*4)
+	}
+
+	for _, c := range d.Children {
+		*clusters++
+		fmt.Fprintf(w, "%ssubgraph cluster_%d {\n", indent, *clusters)
+		fmt.Fprintf(w, "%s\tlabel=\"%s: %s\";\n", indent, escapeDOT(c.Level), escapeDOT(c.Name))
+		writeDomain(w, c, shares, total, indent+"\t", clusters)
+		fmt.Fprintf(w, "%s}\n", indent)
+	}
+}
+
+func escapeDOT(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
@@ -0,0 +1,40 @@
+package hrw
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	r := NewRing(topologyNodes()...)
+	r.SetTopology("zone", "rack")
+
+	keys := make([][]byte, 0, 100)
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, []byte("key-"+strconv.Itoa(i)))
+	}
+
+	buf := new(bytes.Buffer)
+	if err := r.Snapshot().WriteDOT(buf, keys, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, expect := range []string{
+		"digraph hrw {",
+		`label="zone: zone-0";`,
+		`label="rack: zone-1/rack-1";`,
+		`"node-0" [label="node-0\n`,
+		" -> ",
+	} {
+		if !strings.Contains(out, expect) {
+			t.Errorf("Expected output to contain %q:\n%s", expect, out)
+		}
+	}
+
+	if strings.Count(out, "subgraph") != 9 {
+		t.Errorf("Was %d clusters, but expected 9", strings.Count(out, "subgraph"))
+	}
+}
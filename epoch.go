@@ -0,0 +1,48 @@
+package hrw
+
+import "encoding/binary"
+
+// EpochHash salts key hash with epoch, so placement is deterministically
+// rotated every epoch and every peer gets the same rotation.
+func EpochHash(hash, epoch uint64) uint64 {
+	var salt [8]byte
+	binary.BigEndian.PutUint64(salt[:], epoch)
+	return saltHash(hash, Hash(salt[:]))
+}
+
+// GetAtEpoch returns most preferable active node for key at epoch
+func (r *Ring) GetAtEpoch(key []byte, epoch uint64) (Node, bool) {
+	return r.view().GetAtEpoch(key, epoch)
+}
+
+// GetNAtEpoch returns up to n active nodes for key at epoch
+func (r *Ring) GetNAtEpoch(key []byte, epoch uint64, n int) []Node {
+	return r.view().GetNAtEpoch(key, epoch, n)
+}
+
+// GetAtEpoch returns most preferable active node for key at epoch
+func (s *Snapshot) GetAtEpoch(key []byte, epoch uint64) (Node, bool) {
+	return first(s.GetNAtEpoch(key, epoch, 1))
+}
+
+// GetNAtEpoch returns up to n active nodes for key at epoch,
+// key is salted like EpochHash does using Ring hash and weight functions
+func (s *Snapshot) GetNAtEpoch(key []byte, epoch uint64, n int) []Node {
+	var salt [8]byte
+	binary.BigEndian.PutUint64(salt[:], epoch)
+	return s.pick(s.saltKey(s.Hash(key), salt[:]), n)
+}
+
+func saltHash(hash, salt uint64) uint64 {
+	return weight(hash, salt)
+}
+
+// saltKey is like saltHash of hashed salt, but uses hash and weight
+// functions of Ring, so no other primitives are involved
+func (s *Snapshot) saltKey(hash uint64, salt []byte) uint64 {
+	mix := s.weight
+	if mix == nil {
+		mix = weight
+	}
+	return mix(hash, s.hashFn.hash(salt))
+}
@@ -0,0 +1,46 @@
+package hrw
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestEpochHash(t *testing.T) {
+	hash := Hash(testKey)
+	if EpochHash(hash, 1) != EpochHash(hash, 1) {
+		t.Errorf("Expected EpochHash to be deterministic")
+	}
+
+	if EpochHash(hash, 1) == EpochHash(hash, 2) {
+		t.Errorf("Expected different hashes for different epochs")
+	}
+}
+
+func TestRingGetAtEpoch(t *testing.T) {
+	const keys = 1000
+	var (
+		moved int
+		r     = NewRing(testNodes(10)...)
+		key   = make([]byte, 8)
+	)
+
+	for i := uint64(0); i < keys; i++ {
+		binary.BigEndian.PutUint64(key, i)
+
+		a, _ := r.GetAtEpoch(key, 1)
+		b, _ := r.GetAtEpoch(key, 2)
+		if a.ID != b.ID {
+			moved++
+		}
+
+		if actual, expect := r.GetNAtEpoch(key, 1, 3), r.GetNAtEpoch(key, 1, 3); !reflect.DeepEqual(actual, expect) {
+			t.Fatalf("Was %#v, but expected %#v", actual, expect)
+		}
+	}
+
+	// with 10 nodes about 90% of keys must be moved to another node
+	if moved < keys*8/10 {
+		t.Errorf("Only %d of %d keys moved between epochs", moved, keys)
+	}
+}
@@ -0,0 +1,104 @@
+package hrw
+
+import "fmt"
+
+// ErasureCode describes placement of Data data and Parity parity fragments
+// of object, every fragment is placed on distinct node
+type ErasureCode struct {
+	Data, Parity int
+	// PerDomain is maximum count of fragments in one failure domain
+	// (Node.Group), values <= 0 treated as 1. Nodes without group form
+	// their own domains.
+	PerDomain int
+}
+
+// PlaceFragments returns nodes for fragments of key: first ec.Data nodes
+// are for data fragments, the rest are for parity ones. Nodes are taken
+// in order of preference skipping ones of exhausted domains.
+// ErrNoEligibleNodes is returned when none of nodes is selectable.
+func (r *Ring) PlaceFragments(key []byte, ec ErasureCode) ([]Node, error) {
+	return r.view().PlaceFragments(key, ec)
+}
+
+// PlaceFragments is like Ring.PlaceFragments
+func (s *Snapshot) PlaceFragments(key []byte, ec ErasureCode) ([]Node, error) {
+	if ec.Data <= 0 || ec.Parity < 0 {
+		return nil, invalidErasureCode(ec)
+	}
+
+	list, err := s.eligible(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		total  = ec.Data + ec.Parity
+		result = make([]Node, 0, total)
+		used   = make(map[string]int, total)
+	)
+
+	for _, n := range list {
+		if len(result) == total {
+			break
+		}
+
+		d := n.domain()
+		if used[d] >= ec.perDomain() {
+			continue
+		}
+
+		used[d]++
+		result = append(result, n)
+	}
+
+	if len(result) < total {
+		return nil, fmt.Errorf("%w: %d of %d fragments placed",
+			ErrPlacement, len(result), total)
+	}
+	return result, nil
+}
+
+// Validate checks that fragments are placed on ec.Data+ec.Parity distinct
+// nodes without exceeding ec.PerDomain fragments in any failure domain
+func (ec ErasureCode) Validate(fragments []Node) error {
+	if total := ec.Data + ec.Parity; len(fragments) != total {
+		return fmt.Errorf("%w: %d fragments, expected %d",
+			ErrPlacement, len(fragments), total)
+	}
+
+	var (
+		nodes = make(map[string]struct{}, len(fragments))
+		used  = make(map[string]int, len(fragments))
+	)
+
+	for i, n := range fragments {
+		if _, ok := nodes[n.ID]; ok {
+			return fmt.Errorf("%w: fragment %d shares node %q",
+				ErrPlacement, i, n.ID)
+		}
+		nodes[n.ID] = struct{}{}
+
+		d := n.domain()
+		if used[d]++; used[d] > ec.perDomain() {
+			return fmt.Errorf("%w: fragment %d exceeds %d fragments in domain %q",
+				ErrPlacement, i, ec.perDomain(), d)
+		}
+	}
+	return nil
+}
+
+func (ec ErasureCode) perDomain() int {
+	if ec.PerDomain <= 0 {
+		return 1
+	}
+	return ec.PerDomain
+}
+
+// domain returns failure domain of node, nodes without group
+// are domains of their own
+func (n Node) domain() string {
+	if n.Group == "" {
+		return "\x00" + n.ID
+	}
+	return n.Group
+}
@@ -0,0 +1,55 @@
+package hrw
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestPlaceFragments(t *testing.T) {
+	nodes := testNodes(12)
+	for i := range nodes {
+		nodes[i].Group = "rack-" + strconv.Itoa(i%4)
+	}
+	r := NewRing(nodes...)
+
+	t.Run("distinct domains", func(t *testing.T) {
+		ec := ErasureCode{Data: 3, Parity: 1}
+		fragments, err := r.PlaceFragments(testKey, ec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ec.Validate(fragments); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("per domain", func(t *testing.T) {
+		ec := ErasureCode{Data: 4, Parity: 2, PerDomain: 2}
+		fragments, err := r.PlaceFragments(testKey, ec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ec.Validate(fragments); err != nil {
+			t.Error(err)
+		}
+
+		if err := (ErasureCode{Data: 4, Parity: 2}).Validate(fragments); !errors.Is(err, ErrPlacement) {
+			t.Errorf("Was %#v, but expected %#v", err, ErrPlacement)
+		}
+	})
+
+	t.Run("not enough domains", func(t *testing.T) {
+		if _, err := r.PlaceFragments(testKey, ErasureCode{Data: 4, Parity: 1}); !errors.Is(err, ErrPlacement) {
+			t.Errorf("Was %#v, but expected %#v", err, ErrPlacement)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := r.PlaceFragments(testKey, ErasureCode{Parity: 1}); !errors.Is(err, ErrInvalidErasureCode) {
+			t.Errorf("Was %#v, but expected %#v", err, ErrInvalidErasureCode)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package hrw
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotSlice returned when value expected to be a slice is not
+	ErrNotSlice = errors.New("hrw: value is not a slice")
+	// ErrUnsupportedElement returned when slice elements can't be hashed
+	ErrUnsupportedElement = errors.New("hrw: unsupported slice element type")
+	// ErrEmptyInput returned when input required to be non-empty is empty
+	ErrEmptyInput = errors.New("hrw: empty input")
+	// ErrWeightsLengthMismatch returned when weights don't match elements
+	ErrWeightsLengthMismatch = errors.New("hrw: weights length mismatch")
+	// ErrNoEligibleNodes returned when there are no selectable nodes
+	ErrNoEligibleNodes = errors.New("hrw: no eligible nodes")
+	// ErrUnknownAlgorithm returned for unknown Algorithm values
+	ErrUnknownAlgorithm = errors.New("hrw: unknown algorithm")
+	// ErrNotMap returned when value expected to be a map with string keys is not
+	ErrNotMap = errors.New("hrw: value is not a map with string keys")
+	// ErrNilElement matches NilElementsError
+	ErrNilElement = errors.New("hrw: nil slice element")
+	// ErrNotStruct returned when value expected to be a struct is not
+	ErrNotStruct = errors.New("hrw: value is not a struct")
+	// ErrUnsupportedField returned when struct key field can't be encoded
+	ErrUnsupportedField = errors.New("hrw: unsupported key field type")
+	// ErrInvalidQuorum returned when quorum sizes are inconsistent
+	ErrInvalidQuorum = errors.New("hrw: invalid quorum")
+	// ErrNoQuorum returned when too few nodes are available for quorum
+	ErrNoQuorum = errors.New("hrw: not enough nodes for quorum")
+	// ErrInvalidErasureCode returned when fragment counts are invalid
+	ErrInvalidErasureCode = errors.New("hrw: invalid erasure code")
+	// ErrPlacement returned when fragments can't be placed
+	// respecting failure domains
+	ErrPlacement = errors.New("hrw: fragments placement violates constraints")
+	// ErrUnknownLevel returned for topology levels missing in SetTopology
+	ErrUnknownLevel = errors.New("hrw: unknown topology level")
+	// ErrPolicy returned when policy can't select enough nodes
+	ErrPolicy = errors.New("hrw: policy is not satisfied")
+	// ErrFormat returned when saved Ring can't be decoded
+	ErrFormat = errors.New("hrw: invalid ring snapshot format")
+	// ErrInvalidNode returned when node list fails validation
+	ErrInvalidNode = errors.New("hrw: invalid node")
+	// ErrUnknownNode returned for IDs missing in Ring
+	ErrUnknownNode = errors.New("hrw: unknown node")
+	// ErrTransition returned for changes of state lifecycle doesn't allow
+	ErrTransition = errors.New("hrw: state transition isn't allowed")
+	// ErrUnsupportedWeightFunc returned when algorithm can't use WeightFunc
+	ErrUnsupportedWeightFunc = errors.New("hrw: weight function isn't supported")
+)
+
+// NilElementsError reports nil elements of Hasher slice,
+// which were placed last in original order
+type NilElementsError struct {
+	// Indexes of nil elements in original slice
+	Indexes []int
+}
+
+func (e *NilElementsError) Error() string {
+	return fmt.Sprintf("%s at %v", ErrNilElement, e.Indexes)
+}
+
+// Is allows to match error with ErrNilElement
+func (e *NilElementsError) Is(target error) bool {
+	return target == ErrNilElement
+}
+
+func notSlice(v interface{}) error {
+	return fmt.Errorf("%w: %T", ErrNotSlice, v)
+}
+
+func notMap(v interface{}) error {
+	return fmt.Errorf("%w: %T", ErrNotMap, v)
+}
+
+func unsupportedElement(v interface{}) error {
+	return fmt.Errorf("%w: %T", ErrUnsupportedElement, v)
+}
+
+func unsupportedElementAt(v interface{}, i int, elem interface{}) error {
+	return fmt.Errorf("%w: element %d of %T is %T", ErrUnsupportedElement, i, v, elem)
+}
+
+func notStruct(v interface{}) error {
+	return fmt.Errorf("%w: %T", ErrNotStruct, v)
+}
+
+func weightsMismatch(weights, length int) error {
+	return fmt.Errorf("%w: %d weights for %d elements", ErrWeightsLengthMismatch, weights, length)
+}
+
+func noEligibleNodes(total int) error {
+	return fmt.Errorf("%w: none of %d nodes is selectable", ErrNoEligibleNodes, total)
+}
+
+func invalidQuorum(q Quorum) error {
+	return fmt.Errorf("%w: N=%d, R=%d, W=%d", ErrInvalidQuorum, q.N, q.R, q.W)
+}
+
+func invalidErasureCode(ec ErasureCode) error {
+	return fmt.Errorf("%w: data=%d, parity=%d", ErrInvalidErasureCode, ec.Data, ec.Parity)
+}
+
+func unknownLevel(level string) error {
+	return fmt.Errorf("%w: %q", ErrUnknownLevel, level)
+}
@@ -0,0 +1,42 @@
+package hrw
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	var (
+		empty = NewRing(Node{ID: "down", State: StateDown})
+		key   = []byte("key")
+	)
+
+	cases := []struct {
+		name   string
+		err    error
+		expect error
+	}{
+		{name: "not slice", err: TrySortSliceByValue(42, 0), expect: ErrNotSlice},
+		{name: "unsupported", err: TrySortSliceByValue([]interface{}{struct{}{}}, 0), expect: ErrUnsupportedElement},
+		{name: "weights", err: TrySortSliceByWeightValueNSPCC([]int{1, 2}, []float64{1}, 0), expect: ErrWeightsLengthMismatch},
+		{name: "histogram", err: second(NewRing(testNodes(2)...).LoadHistogram(nil, "", nil)), expect: ErrEmptyInput},
+		{name: "quorum", err: second(empty.Quorum(key, Quorum{N: 1, R: 1, W: 1})), expect: ErrNoEligibleNodes},
+		{name: "fragments", err: second(empty.PlaceFragments(key, ErasureCode{Data: 1})), expect: ErrNoEligibleNodes},
+		{name: "spread", err: second(empty.GetSpread(key, 1)), expect: ErrNoEligibleNodes},
+		{name: "policy", err: second(empty.GetPolicy(key, Policy{Select(1)})), expect: ErrNoEligibleNodes},
+	}
+
+	for _, tc := range cases {
+		if !errors.Is(tc.err, tc.expect) {
+			t.Errorf("%s: Was %#v, but expected %#v", tc.name, tc.err, tc.expect)
+		}
+	}
+
+	if err := TrySortSliceByWeightValueNSPCC([]int{1, 2}, nil, 0); err != nil {
+		t.Errorf("Was %#v, but expected nil", err)
+	}
+}
+
+func second(_ interface{}, err error) error {
+	return err
+}
@@ -0,0 +1,56 @@
+package hrw
+
+import (
+	"math"
+	"time"
+)
+
+// ewma is smoothed weight of node and time of the last report
+type ewma struct {
+	value float64
+	at    time.Time
+}
+
+// SetCapacitySmoothing sets half-life of exponentially weighted moving
+// average applied by ReportCapacity, so weight moves halfway to reported
+// value in halfLife and noisy metrics don't thrash selection.
+// Values <= 0 disable smoothing, smoothed state is reset anyway.
+func (r *Ring) SetCapacitySmoothing(halfLife time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.halfLife = halfLife
+	r.ewma = nil
+}
+
+// smooth returns weight of node id after report of w at now,
+// r.mu must be held. The first report is taken as is.
+func (r *Ring) smooth(nodes []member, id string, w float64, now time.Time) float64 {
+	if r.halfLife <= 0 {
+		return w
+	}
+
+	if r.ewma == nil {
+		r.ewma = make(map[string]ewma)
+	}
+
+	prev, ok := r.ewma[id]
+	if !ok {
+		// forget removed nodes before state grows
+		for known := range r.ewma {
+			if _, ok := findMember(nodes, known); !ok {
+				delete(r.ewma, known)
+			}
+		}
+	} else {
+		if dt := now.Sub(prev.at); dt > 0 {
+			alpha := 1 - math.Exp2(-float64(dt)/float64(r.halfLife))
+			w = prev.value + alpha*(w-prev.value)
+		} else {
+			w = prev.value
+		}
+	}
+
+	r.ewma[id] = ewma{value: w, at: now}
+	return w
+}
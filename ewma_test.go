@@ -0,0 +1,41 @@
+package hrw
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCapacitySmoothing(t *testing.T) {
+	var (
+		now = time.Unix(0, 0)
+		r   = NewRing(Node{ID: "a"})
+	)
+
+	r.now = func() time.Time { return now }
+	r.SetCapacitySmoothing(time.Minute)
+
+	report := func(free float64) float64 {
+		if err := r.ReportCapacity(map[string]Capacity{"a": {Disk: free, Memory: free, CPU: free}}); err != nil {
+			t.Fatal(err)
+		}
+		return r.Nodes()[0].Weight
+	}
+
+	if w := report(1); w != 1 {
+		t.Errorf("Was %#v, but expected %#v", w, 1.0)
+	}
+
+	now = now.Add(time.Minute)
+	if w := report(0.5); w != 0.75 {
+		t.Errorf("Was %#v, but expected %#v", w, 0.75)
+	}
+
+	if w := report(0); w != 0.75 {
+		t.Errorf("Was %#v, but expected %#v", w, 0.75)
+	}
+
+	r.SetCapacitySmoothing(0)
+	if w := report(0.5); w != 0.5 {
+		t.Errorf("Was %#v, but expected %#v", w, 0.5)
+	}
+}
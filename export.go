@@ -0,0 +1,67 @@
+package hrw
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Score is a node with it's score for key, nodes with lower scores
+// are preferred
+type Score struct {
+	Node  Node
+	Score float64
+}
+
+// Scores returns up to n selectable nodes for key with their scores in HRW
+// order. Pins, penalties and anti-affinity groups aren't applied.
+func (r *Ring) Scores(key []byte, n int) []Score {
+	return r.view().Scores(key, n)
+}
+
+// Scores is like Ring.Scores
+func (s *Snapshot) Scores(key []byte, n int) []Score {
+	if n <= 0 {
+		return nil
+	}
+
+	list := s.rank(s.Hash(key))
+	sort.Slice(list, func(i, j int) bool { return list[i].less(list[j]) })
+	if n < len(list) {
+		list = list[:n]
+	}
+
+	result := make([]Score, 0, len(list))
+	for _, c := range list {
+		result = append(result, Score{Node: s.nodes[c.index].Node, Score: c.score})
+	}
+	return result
+}
+
+// WriteCSV writes rows of key, rank, node ID and score of n most
+// preferable nodes for every key, see Scores. Rows are preceded by header.
+func (s *Snapshot) WriteCSV(w io.Writer, keys [][]byte, n int) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"key", "rank", "node", "score"}); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		for rank, sc := range s.Scores(key, n) {
+			row := []string{
+				string(key),
+				strconv.Itoa(rank),
+				sc.Node.ID,
+				strconv.FormatFloat(sc.Score, 'g', -1, 64),
+			}
+
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,56 @@
+package hrw
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+)
+
+func TestScores(t *testing.T) {
+	r := NewRing(testNodes(5)...)
+	scores := r.Scores(testKey, 3)
+
+	var ids []string
+	for i, sc := range scores {
+		ids = append(ids, sc.Node.ID)
+		if i > 0 && sc.Score < scores[i-1].Score {
+			t.Errorf("Expected scores in ascending order: %#v", scores)
+		}
+	}
+
+	if expect := nodeIDs(r.GetN(testKey, 3)); !reflect.DeepEqual(ids, expect) {
+		t.Errorf("Was %#v, but expected %#v", ids, expect)
+	}
+
+	for _, n := range []int{0, -1} {
+		if actual := r.Scores(testKey, n); actual != nil {
+			t.Errorf("Was %#v, but expected nil for n = %d", actual, n)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var (
+		buf  = new(bytes.Buffer)
+		s    = NewRing(testNodes(5)...).Snapshot()
+		keys = [][]byte{[]byte("a"), []byte("b,c")}
+	)
+
+	if err := s.WriteCSV(buf, keys, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 5 || !reflect.DeepEqual(rows[0], []string{"key", "rank", "node", "score"}) {
+		t.Fatalf("Was %#v, but expected header and 4 rows", rows)
+	}
+
+	if expect := s.Scores(keys[1], 2)[1].Node.ID; rows[4][0] != "b,c" || rows[4][1] != "1" || rows[4][2] != expect {
+		t.Errorf("Was %#v, but expected key b,c ranked 1 on %s", rows[4], expect)
+	}
+}
@@ -0,0 +1,129 @@
+package hrw
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileSource keeps membership of Ring in sync with file of nodes.
+// Nodes of file replace all members of Ring in one change, so readers
+// never observe partially applied file. File which can't be decoded or
+// fails validation is rejected and Ring keeps previous membership.
+type FileSource struct {
+	Ring *Ring
+	Path string
+	// Decode decodes content of file, by default it's JSON array of
+	// nodes, e.g. [{"id": "a", "weight": 2}]. Set it to use other
+	// formats like YAML.
+	Decode func(data []byte) ([]Node, error)
+	// OnError is called by Run for rejected files
+	OnError func(err error)
+
+	mu   sync.Mutex
+	last uint64
+}
+
+// DecodeJSON decodes JSON array of nodes
+func DecodeJSON(data []byte) ([]Node, error) {
+	var nodes []Node
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// ValidateNodes checks that nodes have non-empty unique IDs
+// and non-negative finite weights
+func ValidateNodes(nodes []Node) error {
+	ids := make(map[string]struct{}, len(nodes))
+	for i, n := range nodes {
+		switch _, ok := ids[n.ID]; {
+		case n.ID == "":
+			return fmt.Errorf("%w: node %d has empty ID", ErrInvalidNode, i)
+		case ok:
+			return fmt.Errorf("%w: duplicate ID %q", ErrInvalidNode, n.ID)
+		case n.Weight < 0:
+			return fmt.Errorf("%w: node %q has negative weight", ErrInvalidNode, n.ID)
+		case math.IsNaN(n.Weight) || math.IsInf(n.Weight, 0):
+			return fmt.Errorf("%w: node %q has weight %v", ErrInvalidNode, n.ID, n.Weight)
+		}
+		ids[n.ID] = struct{}{}
+	}
+	return nil
+}
+
+// Load reads file and applies it to Ring, it reports whether membership
+// was changed. Unchanged file isn't applied again.
+func (f *FileSource) Load() (bool, error) {
+	data, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		return false, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sum := Hash(data)
+	if sum == f.last {
+		return false, nil
+	}
+
+	decode := f.Decode
+	if decode == nil {
+		decode = DecodeJSON
+	}
+
+	nodes, err := decode(data)
+	if err != nil {
+		return false, fmt.Errorf("hrw: decode %s: %w", f.Path, err)
+	} else if err = ValidateNodes(nodes); err != nil {
+		return false, fmt.Errorf("hrw: validate %s: %w", f.Path, err)
+	}
+
+	f.Ring.replace(nodes)
+	f.last = sum
+	return true, nil
+}
+
+// Run loads file every interval until ctx is done
+func (f *FileSource) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := f.Load(); err != nil && f.OnError != nil {
+			f.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// replace replaces all members of Ring by nodes,
+// nodes must have unique IDs
+func (r *Ring) replace(nodes []Node) {
+	r.update(func(s *Snapshot) bool {
+		members := make([]member, 0, len(nodes))
+		for _, n := range nodes {
+			m := newMember(n, s.hashFn)
+			if i, ok := findMember(s.nodes, n.ID); ok {
+				m.seq = s.nodes[i].seq
+			}
+			members = append(members, m)
+		}
+
+		sort.Slice(members, func(i, j int) bool { return members[i].ID < members[j].ID })
+		s.nodes = members
+		return true
+	})
+}
@@ -0,0 +1,78 @@
+package hrw
+
+import (
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFileSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hrw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		r   = NewRing(testNodes(2)...)
+		src = &FileSource{Ring: r, Path: filepath.Join(dir, "nodes.json")}
+	)
+
+	write := func(data string) {
+		if err := ioutil.WriteFile(src.Path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`[{"id": "b", "weight": 2}, {"id": "a", "state": 1}]`)
+	if ok, err := src.Load(); !ok || err != nil {
+		t.Fatalf("Was %v and %v, but expected change", ok, err)
+	}
+
+	expect := []Node{{ID: "a", State: StateDown}, {ID: "b", Weight: 2}}
+	if actual := r.Nodes(); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	version := r.Version()
+	if ok, err := src.Load(); ok || err != nil {
+		t.Errorf("Was %v and %v, but expected no change", ok, err)
+	}
+
+	write(`[{"id": "c"}`)
+	if _, err := src.Load(); err == nil {
+		t.Errorf("Expected decode error")
+	}
+
+	write(`[{"id": "c"}, {"id": "c"}]`)
+	if _, err := src.Load(); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrInvalidNode)
+	}
+
+	if actual := r.Nodes(); !reflect.DeepEqual(actual, expect) || r.Version() != version {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
+
+func TestValidateNodes(t *testing.T) {
+	if err := ValidateNodes(testNodes(3)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, nodes := range [][]Node{
+		{{ID: "a"}, {}},
+		{{ID: "a"}, {ID: "a"}},
+		{{ID: "a", Weight: -1}},
+		{{ID: "a", Weight: math.NaN()}},
+		{{ID: "a", Weight: math.Inf(1)}},
+		{{ID: "a", Weight: math.Inf(-1)}},
+	} {
+		if err := ValidateNodes(nodes); !errors.Is(err, ErrInvalidNode) {
+			t.Errorf("Was %#v for %#v, but expected %#v", err, nodes, ErrInvalidNode)
+		}
+	}
+}
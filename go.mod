@@ -1,3 +1 @@
 module github.com/im-kulikov/hrw
-
-require github.com/spaolacci/murmur3 v0.0.0-20180118202830-f09979ecbc72
@@ -0,0 +1,25 @@
+package hrw
+
+// Relaxation reports constraints relaxed to select requested count of nodes
+type Relaxation struct {
+	// Groups is count of selected nodes sharing anti-affinity group
+	// with more preferable selected node
+	Groups int
+}
+
+// GetNWithReport returns up to n active nodes for key like GetN does
+// and reports constraints relaxed to select them
+func (r *Ring) GetNWithReport(key []byte, n int) ([]Node, Relaxation) {
+	return r.view().GetNWithReport(key, n)
+}
+
+// GetNWithReport is like Ring.GetNWithReport
+func (s *Snapshot) GetNWithReport(key []byte, n int) ([]Node, Relaxation) {
+	if n <= 0 {
+		return nil, Relaxation{}
+	}
+
+	list := s.rank(s.Hash(key))
+	s.penalize(list)
+	return topMembersReport(s.nodes, list, n)
+}
@@ -0,0 +1,58 @@
+package hrw
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRingGroups(t *testing.T) {
+	var (
+		nodes = testNodes(6)
+		plain = nodeIDs(NewRing(nodes...).GetN(testKey, 6))
+		group = map[string]string{}
+	)
+
+	// the first two preferable nodes share group
+	group[plain[0]], group[plain[1]] = "hv-1", "hv-1"
+	group[plain[2]], group[plain[3]] = "hv-2", "hv-3"
+	for i := range nodes {
+		nodes[i].Group = group[nodes[i].ID]
+	}
+
+	r := NewRing(nodes...)
+
+	t.Run("distinct groups", func(t *testing.T) {
+		expect := []string{plain[0], plain[2], plain[3]}
+		actual, rel := r.GetNWithReport(testKey, 3)
+		if !reflect.DeepEqual(nodeIDs(actual), expect) {
+			t.Errorf("Was %#v, but expected %#v", nodeIDs(actual), expect)
+		}
+		if rel.Groups != 0 {
+			t.Errorf("Was %d, but expected %d", rel.Groups, 0)
+		}
+	})
+
+	t.Run("relaxed", func(t *testing.T) {
+		r := NewRing(nodes[:0]...)
+		for _, n := range nodes {
+			if n.Group == "hv-1" {
+				r.Add(n)
+			}
+		}
+
+		actual, rel := r.GetNWithReport(testKey, 2)
+		if expect := plain[:2]; !reflect.DeepEqual(nodeIDs(actual), expect) {
+			t.Errorf("Was %#v, but expected %#v", nodeIDs(actual), expect)
+		}
+		if rel.Groups != 1 {
+			t.Errorf("Was %d, but expected %d", rel.Groups, 1)
+		}
+	})
+
+	t.Run("GetN respects groups", func(t *testing.T) {
+		expect := []string{plain[0], plain[2], plain[3], plain[4], plain[5], plain[1]}
+		if actual := nodeIDs(r.GetN(testKey, 6)); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package hrw
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// Prober checks health of node, nil error means node is healthy
+	Prober interface {
+		Probe(ctx context.Context, n Node) error
+	}
+
+	// ProberFunc is an adapter to use function as Prober
+	ProberFunc func(ctx context.Context, n Node) error
+
+	// TCPProber considers node healthy when TCP connection to it succeeds
+	TCPProber struct {
+		// Address returns address of node, node ID used by default
+		Address func(n Node) string
+	}
+
+	// HTTPProber considers node healthy when GET request to it
+	// returns 2xx status code
+	HTTPProber struct {
+		// Client used to send requests, http.DefaultClient by default
+		Client *http.Client
+		// URL returns URL of node health endpoint
+		URL func(n Node) string
+	}
+
+	// HealthChecker probes nodes of Ring and marks unhealthy ones as
+	// StateDown. To damp flapping node is marked down after Fall failed
+	// probes in a row and marked active after Rise successful ones.
+	// Only nodes marked down by HealthChecker are marked active again,
+	// nodes removed from Ring or changed by someone else are forgotten.
+	HealthChecker struct {
+		Ring   *Ring
+		Prober Prober
+		// Timeout of single probe, no timeout by default
+		Timeout time.Duration
+		// Rise and Fall are counts of probes in a row required to change
+		// state of node, values <= 0 treated as 1
+		Rise, Fall int
+
+		mu    sync.Mutex
+		nodes map[string]*health
+	}
+
+	health struct {
+		down         bool
+		success, err int
+	}
+)
+
+// Probe calls fn(ctx, n)
+func (fn ProberFunc) Probe(ctx context.Context, n Node) error {
+	return fn(ctx, n)
+}
+
+// Probe dials node
+func (p TCPProber) Probe(ctx context.Context, n Node) error {
+	addr := n.ID
+	if p.Address != nil {
+		addr = p.Address(n)
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// Probe requests health endpoint of node
+func (p HTTPProber) Probe(ctx context.Context, n Node) error {
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL(n), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("hrw: node %s is unhealthy: %s", n.ID, resp.Status)
+	}
+	return nil
+}
+
+// Check probes every node of Ring concurrently and updates their states,
+// it returns IDs of nodes which state was changed
+func (h *HealthChecker) Check(ctx context.Context) []string {
+	var (
+		nodes = h.Ring.Nodes()
+		errs  = make([]error, len(nodes))
+		wg    sync.WaitGroup
+	)
+
+	h.reconcile(nodes)
+	for i := range nodes {
+		// skip nodes marked down by someone else
+		if nodes[i].State != StateActive && !h.isDown(nodes[i].ID) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = h.probe(ctx, nodes[i])
+		}(i)
+	}
+	wg.Wait()
+
+	var up, down []string
+
+	h.mu.Lock()
+	if h.nodes == nil {
+		h.nodes = make(map[string]*health, len(nodes))
+	}
+
+	for i := range nodes {
+		if nodes[i].State != StateActive && !h.down(nodes[i].ID) {
+			continue
+		}
+
+		s, ok := h.nodes[nodes[i].ID]
+		if !ok {
+			s = new(health)
+			h.nodes[nodes[i].ID] = s
+		}
+
+		if errs[i] != nil {
+			s.success, s.err = 0, s.err+1
+			if !s.down && s.err >= atLeastOne(h.Fall) {
+				s.down = true
+				down = append(down, nodes[i].ID)
+			}
+			continue
+		}
+
+		s.success, s.err = s.success+1, 0
+		if s.down && s.success >= atLeastOne(h.Rise) {
+			s.down = false
+			up = append(up, nodes[i].ID)
+		}
+	}
+	h.mu.Unlock()
+
+	if len(down) > 0 {
+		h.Ring.setState(StateDown, down)
+	}
+	if len(up) > 0 {
+		h.Ring.setState(StateActive, up)
+	}
+	return append(down, up...)
+}
+
+// Run calls Check every interval until ctx is done
+func (h *HealthChecker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		h.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *HealthChecker) probe(ctx context.Context, n Node) error {
+	if h.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.Timeout)
+		defer cancel()
+	}
+	return h.Prober.Probe(ctx, n)
+}
+
+// reconcile forgets nodes removed from Ring and nodes which state was
+// changed by someone else, e.g. activated by operator or re-added
+func (h *HealthChecker) reconcile(nodes []Node) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	states := make(map[string]State, len(nodes))
+	for i := range nodes {
+		states[nodes[i].ID] = nodes[i].State
+	}
+
+	for id, s := range h.nodes {
+		if state, ok := states[id]; !ok || s.down != (state == StateDown) {
+			delete(h.nodes, id)
+		}
+	}
+}
+
+func (h *HealthChecker) isDown(id string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.down(id)
+}
+
+func (h *HealthChecker) down(id string) bool {
+	s, ok := h.nodes[id]
+	return ok && s.down
+}
+
+func atLeastOne(v int) int {
+	if v <= 0 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,125 @@
+package hrw
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestHealthChecker(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		r       = NewRing(Node{ID: "a"}, Node{ID: "b"}, Node{ID: "admin", State: StateDown})
+		healthy = map[string]bool{"a": true, "admin": false}
+		h       = &HealthChecker{
+			Ring: r,
+			Rise: 2,
+			Fall: 2,
+			Prober: ProberFunc(func(_ context.Context, n Node) error {
+				if healthy[n.ID] {
+					return nil
+				}
+				return errors.New("unhealthy")
+			}),
+		}
+	)
+
+	if changed := h.Check(ctx); changed != nil {
+		t.Errorf("Expected no changes after first failure, got %#v", changed)
+	}
+
+	if changed := h.Check(ctx); !reflect.DeepEqual(changed, []string{"b"}) {
+		t.Errorf("Was %#v, but expected %#v", changed, []string{"b"})
+	}
+
+	if actual := nodeIDs(r.GetN(testKey, 3)); !reflect.DeepEqual(actual, []string{"a"}) {
+		t.Errorf("Was %#v, but expected %#v", actual, []string{"a"})
+	}
+
+	healthy["b"], healthy["admin"] = true, true
+	if changed := h.Check(ctx); changed != nil {
+		t.Errorf("Expected no changes after first success, got %#v", changed)
+	}
+
+	if changed := h.Check(ctx); !reflect.DeepEqual(changed, []string{"b"}) {
+		t.Errorf("Was %#v, but expected %#v", changed, []string{"b"})
+	}
+
+	if actual := len(r.GetN(testKey, 3)); actual != 2 {
+		t.Errorf("Was %d, but expected %d", actual, 2)
+	}
+}
+
+func TestHealthCheckerReconcile(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		r       = NewRing(Node{ID: "a"})
+		healthy bool
+		h       = &HealthChecker{
+			Ring: r,
+			Prober: ProberFunc(func(context.Context, Node) error {
+				if healthy {
+					return nil
+				}
+				return errors.New("unhealthy")
+			}),
+		}
+	)
+
+	if changed := h.Check(ctx); !reflect.DeepEqual(changed, []string{"a"}) {
+		t.Fatalf("Was %#v, but expected %#v", changed, []string{"a"})
+	}
+
+	// re-added node is marked down again
+	r.Remove("a")
+	r.Add(Node{ID: "a"})
+	if changed := h.Check(ctx); !reflect.DeepEqual(changed, []string{"a"}) {
+		t.Errorf("Was %#v, but expected %#v", changed, []string{"a"})
+	}
+
+	// removed nodes are forgotten
+	r.Remove("a")
+	h.Check(ctx)
+	if len(h.nodes) != 0 {
+		t.Errorf("Was %d nodes, but expected %d", len(h.nodes), 0)
+	}
+}
+
+func TestTCPProber(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	addr := l.Addr().String()
+	if err := (TCPProber{}).Probe(context.Background(), Node{ID: addr}); err != nil {
+		t.Errorf("Expected node to be healthy, got %v", err)
+	}
+
+	_ = l.Close()
+	if err := (TCPProber{}).Probe(context.Background(), Node{ID: addr}); err == nil {
+		t.Errorf("Expected node to be unhealthy")
+	}
+}
+
+func TestHTTPProber(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/a/health" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	p := HTTPProber{URL: func(n Node) string { return srv.URL + "/" + n.ID + "/health" }}
+	if err := p.Probe(context.Background(), Node{ID: "a"}); err != nil {
+		t.Errorf("Expected node to be healthy, got %v", err)
+	}
+
+	if err := p.Probe(context.Background(), Node{ID: "b"}); err == nil {
+		t.Errorf("Expected node to be unhealthy")
+	}
+}
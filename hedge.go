@@ -0,0 +1,71 @@
+package hrw
+
+import "time"
+
+type (
+	// HedgeDelayFunc returns how long to wait for response of node before
+	// hedging request to the next one, e.g. 95th percentile of it's latency
+	HedgeDelayFunc func(n Node) time.Duration
+
+	// Hedge is plan of hedged request: request is sent to Primary and then
+	// to Backups[i] after Delays[i] since start unless it's completed
+	Hedge struct {
+		Primary Node
+		Backups []Node
+		Delays  []time.Duration
+	}
+)
+
+// DefaultHedgeDelay is delay of every node without HedgeDelayFunc
+const DefaultHedgeDelay = 10 * time.Millisecond
+
+// SetHedgeDelay sets function used by Hedged to suggest delays,
+// nil restores DefaultHedgeDelay
+func (r *Ring) SetHedgeDelay(fn HedgeDelayFunc) {
+	r.update(func(s *Snapshot) bool {
+		s.hedge = fn
+		return false
+	})
+}
+
+// Hedged returns plan of hedged request for key to up to n nodes.
+// Nodes are ordered like GetN does, so blacklisted and unhealthy nodes
+// are skipped and graylisted ones are used last. Delay of backup is sum of
+// delays of preceding nodes, so every node has time to respond before the
+// next one is asked. ErrNoEligibleNodes is returned when Ring has no
+// active nodes, n <= 0 treated as 1.
+func (r *Ring) Hedged(key []byte, n int) (Hedge, error) {
+	return r.view().Hedged(key, n)
+}
+
+// Hedged is like Ring.Hedged
+func (s *Snapshot) Hedged(key []byte, n int) (Hedge, error) {
+	if n <= 0 {
+		n = 1
+	}
+
+	nodes := s.GetN(key, n)
+	if len(nodes) == 0 {
+		return Hedge{}, noEligibleNodes(s.Len())
+	}
+
+	h := Hedge{
+		Primary: nodes[0],
+		Backups: nodes[1:],
+		Delays:  make([]time.Duration, 0, len(nodes)-1),
+	}
+
+	var delay time.Duration
+	for _, node := range nodes[:len(nodes)-1] {
+		delay += s.hedgeDelay(node)
+		h.Delays = append(h.Delays, delay)
+	}
+	return h, nil
+}
+
+func (s *Snapshot) hedgeDelay(n Node) time.Duration {
+	if s.hedge == nil {
+		return DefaultHedgeDelay
+	}
+	return s.hedge(n)
+}
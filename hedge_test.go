@@ -0,0 +1,52 @@
+package hrw
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHedged(t *testing.T) {
+	var (
+		key = []byte("key")
+		r   = NewRing(Node{ID: "a"}, Node{ID: "b"}, Node{ID: "c"})
+		top = r.GetN(key, 3)
+	)
+
+	h, err := r.Hedged(key, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := Hedge{
+		Primary: top[0],
+		Backups: top[1:],
+		Delays:  []time.Duration{DefaultHedgeDelay, 2 * DefaultHedgeDelay},
+	}
+	if !reflect.DeepEqual(h, expect) {
+		t.Errorf("Was %#v, but expected %#v", h, expect)
+	}
+
+	r.SetHedgeDelay(func(n Node) time.Duration {
+		if n.ID == top[0].ID {
+			return time.Millisecond
+		}
+		return time.Second
+	})
+	r.Blacklist(time.Hour, top[1].ID)
+
+	h, _ = r.Hedged(key, 3)
+	expect = Hedge{
+		Primary: top[0],
+		Backups: top[2:],
+		Delays:  []time.Duration{time.Millisecond},
+	}
+	if !reflect.DeepEqual(h, expect) {
+		t.Errorf("Was %#v, but expected %#v", h, expect)
+	}
+
+	if _, err := NewRing().Hedged(key, 2); !errors.Is(err, ErrNoEligibleNodes) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrNoEligibleNodes)
+	}
+}
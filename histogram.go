@@ -0,0 +1,97 @@
+package hrw
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Histogram summarizes distribution of sample of keys among nodes,
+// every key is counted for it's most preferable node
+type Histogram struct {
+	Keys int
+	// Nodes maps node ID to share of keys
+	Nodes map[string]float64
+	// Domains maps domain name of requested topology level to share of keys
+	Domains map[string]float64
+	// Bounds are upper bounds of buckets of load ratio: share of keys of
+	// node divided by share expected from it's weight
+	Bounds []float64
+	// Counts are counts of active nodes in buckets, the last one counts
+	// nodes above the last bound
+	Counts []int
+	// MinRatio and MaxRatio are extreme load ratios of active nodes
+	MinRatio, MaxRatio float64
+}
+
+// DefaultBounds are buckets of load ratio used by default
+var DefaultBounds = []float64{0.5, 0.8, 0.9, 1.1, 1.2, 1.5, 2}
+
+// LoadHistogram returns distribution of keys among nodes and domains of
+// topology level, empty level skips domains. Nil bounds mean DefaultBounds.
+// ErrEmptyInput is returned for empty keys.
+func (r *Ring) LoadHistogram(keys [][]byte, level string, bounds []float64) (Histogram, error) {
+	return r.view().LoadHistogram(keys, level, bounds)
+}
+
+// LoadHistogram is like Ring.LoadHistogram
+func (s *Snapshot) LoadHistogram(keys [][]byte, level string, bounds []float64) (Histogram, error) {
+	if len(keys) == 0 {
+		return Histogram{}, fmt.Errorf("%w: no keys", ErrEmptyInput)
+	}
+
+	depth := -1
+	if level != "" {
+		var ok bool
+		if depth, ok = s.levelDepth(level); !ok {
+			return Histogram{}, unknownLevel(level)
+		}
+	}
+
+	if bounds == nil {
+		bounds = DefaultBounds
+	}
+
+	h := Histogram{
+		Keys:   len(keys),
+		Nodes:  make(map[string]float64),
+		Bounds: append([]float64(nil), bounds...),
+		Counts: make([]int, len(bounds)+1),
+	}
+	sort.Float64s(h.Bounds)
+
+	if depth >= 0 {
+		h.Domains = make(map[string]float64)
+	}
+
+	for _, key := range keys {
+		n, ok := s.Get(key)
+		if !ok {
+			continue
+		}
+
+		h.Nodes[n.ID] += 1 / float64(len(keys))
+		if depth >= 0 {
+			h.Domains[n.locate(depth)] += 1 / float64(len(keys))
+		}
+	}
+
+	expected := s.shares()
+	ids := make([]string, 0, len(expected))
+	for id := range expected {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for i, id := range ids {
+		ratio := h.Nodes[id] / expected[id]
+		if i == 0 || ratio < h.MinRatio {
+			h.MinRatio = ratio
+		}
+		if i == 0 || ratio > h.MaxRatio {
+			h.MaxRatio = ratio
+		}
+
+		h.Counts[sort.SearchFloat64s(h.Bounds, ratio)]++
+	}
+	return h, nil
+}
@@ -0,0 +1,55 @@
+package hrw
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestLoadHistogram(t *testing.T) {
+	nodes := topologyNodes()
+	nodes[0].Weight = 2
+
+	r := NewRing(nodes...)
+	r.SetTopology("zone", "rack")
+
+	keys := make([][]byte, 0, 10000)
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, []byte("key-"+strconv.Itoa(i)))
+	}
+
+	h, err := r.LoadHistogram(keys, "zone", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var total, zones float64
+	for _, share := range h.Nodes {
+		total += share
+	}
+	for _, share := range h.Domains {
+		zones += share
+	}
+
+	if math.Abs(total-1) > 1e-9 || math.Abs(zones-1) > 1e-9 || len(h.Domains) != 3 {
+		t.Errorf("Was %f of keys in nodes and %f in %d zones, but expected 1 in 3 zones", total, zones, len(h.Domains))
+	}
+
+	var count int
+	for _, c := range h.Counts {
+		count += c
+	}
+
+	if count != len(nodes) || len(h.Counts) != len(DefaultBounds)+1 {
+		t.Errorf("Was %#v, but expected %d nodes in %d buckets", h.Counts, len(nodes), len(DefaultBounds)+1)
+	}
+
+	if h.MinRatio < 0.8 || h.MaxRatio > 1.2 {
+		t.Errorf("Was ratios in [%f, %f], but expected weighted balance", h.MinRatio, h.MaxRatio)
+	}
+
+	if _, err := r.LoadHistogram(keys, "host", nil); !errors.Is(err, ErrUnknownLevel) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrUnknownLevel)
+	}
+}
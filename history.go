@@ -0,0 +1,94 @@
+package hrw
+
+// SetHistory sets count of previous versions retained by Ring,
+// see GetAtVersion. Zero, the default, retains none.
+func (r *Ring) SetHistory(n int) {
+	r.update(func(s *Snapshot) bool {
+		if n < 0 {
+			n = 0
+		}
+
+		s.keep = n
+		if len(s.history) > n {
+			s.history = append([]*Snapshot(nil), s.history[len(s.history)-n:]...)
+		}
+		return false
+	})
+}
+
+// Versions returns retained versions of Ring from the oldest one,
+// the last one is current version
+func (r *Ring) Versions() []uint64 {
+	return r.view().Versions()
+}
+
+// AtVersion returns Snapshot of Ring at version v if it's retained
+func (r *Ring) AtVersion(v uint64) (*Snapshot, bool) {
+	return r.view().AtVersion(v)
+}
+
+// GetAtVersion returns most preferable active node for key at version v,
+// so data written under previous membership can be located. False is
+// returned when version isn't retained or has no active nodes.
+func (r *Ring) GetAtVersion(key []byte, v uint64) (Node, bool) {
+	return r.view().GetAtVersion(key, v)
+}
+
+// GetNAtVersion returns up to n active nodes for key at version v,
+// nil is returned when version isn't retained
+func (r *Ring) GetNAtVersion(key []byte, v uint64, n int) []Node {
+	return r.view().GetNAtVersion(key, v, n)
+}
+
+// Versions is like Ring.Versions
+func (s *Snapshot) Versions() []uint64 {
+	result := make([]uint64, 0, len(s.history)+1)
+	for _, h := range s.history {
+		result = append(result, h.version)
+	}
+	return append(result, s.version)
+}
+
+// AtVersion is like Ring.AtVersion
+func (s *Snapshot) AtVersion(v uint64) (*Snapshot, bool) {
+	if v == s.version {
+		return s, true
+	}
+
+	for _, h := range s.history {
+		if h.version == v {
+			return h, true
+		}
+	}
+	return nil, false
+}
+
+// GetAtVersion is like Ring.GetAtVersion
+func (s *Snapshot) GetAtVersion(key []byte, v uint64) (Node, bool) {
+	return first(s.GetNAtVersion(key, v, 1))
+}
+
+// GetNAtVersion is like Ring.GetNAtVersion
+func (s *Snapshot) GetNAtVersion(key []byte, v uint64, n int) []Node {
+	if h, ok := s.AtVersion(v); ok {
+		return h.GetN(key, n)
+	}
+	return nil
+}
+
+// appendHistory returns history of snapshot following old one, retained
+// snapshots don't hold their own history, so memory is bounded by keep
+func appendHistory(old *Snapshot, keep int) []*Snapshot {
+	if keep <= 0 {
+		return nil
+	}
+
+	prev := *old
+	prev.history = nil
+
+	history := old.history
+	if len(history) >= keep {
+		history = history[len(history)-keep+1:]
+	}
+	return append(append(make([]*Snapshot, 0, len(history)+1), history...), &prev)
+}
@@ -0,0 +1,50 @@
+package hrw
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetAtVersion(t *testing.T) {
+	r := NewRing(testNodes(3)...)
+	r.SetHistory(2)
+
+	var (
+		v1    = r.Version()
+		prev  = r.GetN(testKey, 3)
+		owner = prev[0]
+	)
+
+	r.Remove(owner.ID)
+	r.Add(Node{ID: "node-3"})
+	r.Add(Node{ID: "node-4"})
+
+	if _, ok := r.GetAtVersion(testKey, v1); ok {
+		t.Errorf("Expected version %d not to be retained", v1)
+	}
+
+	expect := []uint64{v1 + 1, v1 + 2, v1 + 3}
+	if actual := r.Versions(); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	r = NewRing(testNodes(3)...)
+	r.SetHistory(2)
+	r.Remove(owner.ID)
+
+	if actual, ok := r.GetAtVersion(testKey, v1); !ok || actual.ID != owner.ID {
+		t.Errorf("Was %#v, but expected %#v", actual, owner)
+	}
+
+	if actual := r.GetNAtVersion(testKey, v1, 3); !reflect.DeepEqual(actual, prev) {
+		t.Errorf("Was %#v, but expected %#v", actual, prev)
+	}
+
+	if actual, _ := r.Get(testKey); actual.ID == owner.ID {
+		t.Errorf("Expected current version not to select removed node")
+	}
+
+	if h, _ := r.AtVersion(v1); h.history != nil {
+		t.Errorf("Expected retained snapshot not to hold history")
+	}
+}
@@ -0,0 +1,33 @@
+package hrw
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"sync"
+)
+
+// NewHMACHash returns HashFunc based on HMAC-SHA256 with secret.
+// Placements made with it can't be predicted or forced by clients
+// that don't know the secret.
+func NewHMACHash(secret []byte) HashFunc {
+	secret = append([]byte(nil), secret...)
+	pool := sync.Pool{New: func() interface{} {
+		return hmac.New(sha256.New, secret)
+	}}
+
+	return func(key []byte) uint64 {
+		var (
+			sum [sha256.Size]byte
+			mac = pool.Get().(hash.Hash)
+		)
+
+		mac.Reset()
+		_, _ = mac.Write(key)
+		mac.Sum(sum[:0])
+		pool.Put(mac)
+
+		return binary.BigEndian.Uint64(sum[:])
+	}
+}
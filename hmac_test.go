@@ -0,0 +1,46 @@
+package hrw
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+)
+
+func TestNewHMACHash(t *testing.T) {
+	secret := []byte("cluster-secret")
+	fn := NewHMACHash(secret)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(testKey)
+	expect := binary.BigEndian.Uint64(mac.Sum(nil))
+
+	for i := 0; i < 3; i++ {
+		if actual := fn(testKey); actual != expect {
+			t.Errorf("Was %d, but expected %d", actual, expect)
+		}
+	}
+
+	if NewHMACHash([]byte("other-secret"))(testKey) == expect {
+		t.Errorf("Expected different hashes for different secrets")
+	}
+}
+
+func TestRingSetHash(t *testing.T) {
+	r := NewRing(testNodes(10)...)
+	if actual, expect := r.Hash(testKey), Hash(testKey); actual != expect {
+		t.Errorf("Was %d, but expected %d", actual, expect)
+	}
+
+	fn := NewHMACHash([]byte("cluster-secret"))
+	r.SetHash(fn)
+	if actual, expect := r.Hash(testKey), fn(testKey); actual != expect {
+		t.Errorf("Was %d, but expected %d", actual, expect)
+	}
+
+	actual, _ := r.Get(testKey)
+	expect := r.view().pick(fn(testKey), 1)[0]
+	if actual.ID != expect.ID {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
@@ -4,152 +4,316 @@ package hrw
 
 import (
 	"encoding/binary"
-	"reflect"
-	"sort"
+	"fmt"
+	"hash"
 
-	"github.com/spaolacci/murmur3"
+	"github.com/im-kulikov/hrw/internal/murmur3"
 )
 
 type (
 	swapper func(i, j int)
 
+	// HashFunc returns hash of key
+	HashFunc func(key []byte) uint64
+
+	// WeightFunc mixes hashes of node and key into weight of node,
+	// nodes with lower weights are preferred. It replaces murmur3 finalizer
+	// used by default, e.g. to reproduce scoring of another system.
+	WeightFunc func(node, hash uint64) uint64
+
 	// Hasher interface used by SortSliceByValue
 	Hasher interface{ Hash() uint64 }
 
+	// hashed keeps indexes of nodes as int32, that halves memory traffic
+	// of sorting and applying rules to large slices, so at most
+	// math.MaxInt32 nodes are supported
 	hashed struct {
 		length int
-		sorted []uint64
+		sorted []int32
 		weight []uint64
+		// nodes break ties of weights when set, then indexes do
+		nodes []uint64
 	}
 )
 
 func weight(x uint64, y uint64) uint64 {
-	acc := x ^ y
 	// here used mmh3 64 bit finalizer
 	// https://github.com/aappleby/smhasher/blob/61a0530f28277f2e850bfc39600ce61d02b518de/src/MurmurHash3.cpp#L81
-	acc ^= acc >> 33
-	acc = acc * 0xff51afd7ed558ccd
-	acc ^= acc >> 33
-	acc = acc * 0xc4ceb9fe1a85ec53
-	acc ^= acc >> 33
-	return acc
+	return murmur3.Fmix64(x ^ y)
+}
+
+func (h hashed) Len() int      { return h.length }
+func (h hashed) Swap(i, j int) { h.sorted[i], h.sorted[j] = h.sorted[j], h.sorted[i] }
+
+func (h hashed) Less(i, j int) bool {
+	a, b := h.sorted[i], h.sorted[j]
+	switch {
+	case h.weight[a] != h.weight[b]:
+		return h.weight[a] < h.weight[b]
+	case h.nodes != nil && h.nodes[a] != h.nodes[b]:
+		return h.nodes[a] < h.nodes[b]
+	default:
+		return a < b
+	}
+}
+
+// order returns indexes of sorted nodes widened into buf
+func (h hashed) order(buf []uint64) []uint64 {
+	buf = reuse(buf, h.length)
+	for _, i := range h.sorted {
+		buf = append(buf, uint64(i))
+	}
+	return buf
 }
 
-func (h hashed) Len() int           { return h.length }
-func (h hashed) Less(i, j int) bool { return h.weight[h.sorted[i]] < h.weight[h.sorted[j]] }
-func (h hashed) Swap(i, j int)      { h.sorted[i], h.sorted[j] = h.sorted[j], h.sorted[i] }
+// ties reports whether sorted nodes have equal weights
+func (h hashed) ties() bool {
+	for i := 1; i < h.length; i++ {
+		if h.weight[h.sorted[i-1]] == h.weight[h.sorted[i]] {
+			return true
+		}
+	}
+	return false
+}
 
 // Hash uses murmur3 hash to return uint64
 func Hash(key []byte) uint64 {
 	return murmur3.Sum64(key)
 }
 
+// HashString returns Hash of s without converting it to []byte
+func HashString(s string) uint64 {
+	return murmur3.Sum64String(s)
+}
+
+// HashUint64 returns Hash of big-endian encoding of v without allocations
+func HashUint64(v uint64) uint64 {
+	return murmur3.Sum64Uint64(v)
+}
+
+// New64 returns hash.Hash64 which Sum64 equals Hash of all written data,
+// so large keys can be hashed without assembling them in one buffer
+func New64() hash.Hash64 {
+	return murmur3.New64()
+}
+
+// hash calls fn or falls back to Hash when fn is nil
+func (fn HashFunc) hash(key []byte) uint64 {
+	if fn == nil {
+		return Hash(key)
+	}
+	return fn(key)
+}
+
+// hashString is like hash, but avoids conversion of s when fn is nil
+func (fn HashFunc) hashString(s string) uint64 {
+	if fn == nil {
+		return HashString(s)
+	}
+	return fn([]byte(s))
+}
+
 // SortByWeight receive nodes and hash, and sort it by weight
 func SortByWeight(nodes []uint64, hash uint64) []uint64 {
-	var (
-		l = len(nodes)
-		h = hashed{
-			length: l,
-			sorted: make([]uint64, 0, l),
-			weight: make([]uint64, 0, l),
-		}
-	)
+	return SortByWeightFunc(nodes, hash, weight)
+}
 
-	for i, node := range nodes {
-		h.sorted = append(h.sorted, uint64(i))
-		h.weight = append(h.weight, weight(node, hash))
-	}
+// SortByWeightFunc is like SortByWeight, but weights are calculated by fn
+func SortByWeightFunc(nodes []uint64, hash uint64, fn WeightFunc) []uint64 {
+	return sortByWeightFunc(nodes, hash, fn).order(nil)
+}
 
-	sort.Sort(h)
-	return h.sorted
+// SortByWeightWithTies is like SortByWeight, but also reports whether some
+// nodes had equal weights. Ties are broken by node values and then by
+// indexes, so order doesn't depend on sort implementation.
+func SortByWeightWithTies(nodes []uint64, hash uint64) ([]uint64, bool) {
+	h := sortByWeightFunc(nodes, hash, weight)
+	return h.order(nil), h.ties()
 }
 
-// SortSliceByValue received []T and hash to sort by value-weight
+func sortByWeightFunc(nodes []uint64, hash uint64, fn WeightFunc) hashed {
+	return (*Sorter)(nil).sortByWeightFunc(nodes, hash, fn)
+}
+
+// SortSliceByValue received []T and hash to sort by value-weight,
+// unsupported values are left untouched (or panic in strict mode).
+// Nil elements of Hasher slices are placed last in original order.
+//
+// Elements of []int, []int64, []int16 and []int8 are hashed as 64-bit two's
+// complement, so equal values are placed equally on every platform
+// regardless of their type. Earlier versions hashed uint64(v) of []int
+// elements, which sign extends on every platform too, so orders of []int
+// are unchanged and there is no switch for the old behavior. Elements of
+// []int32 are hashed as 32-bit two's complement, that's kept for
+// compatibility, use V2 to hash all integers equally.
 func SortSliceByValue(slice interface{}, hash uint64) {
-	t := reflect.TypeOf(slice)
-	if t.Kind() != reflect.Slice {
-		return
+	checkStrict(TrySortSliceByValue(slice, hash))
+}
+
+// TrySortSliceByValue is like SortSliceByValue, but returns error for
+// unsupported values and *NilElementsError when slice had nil elements
+func TrySortSliceByValue(slice interface{}, hash uint64) error {
+	return (*Sorter)(nil).TrySortSliceByValue(slice, hash)
+}
+
+// TrySortSliceByValue is like package-level TrySortSliceByValue, but uses
+// algorithm and weight function of Sorter configuration
+func (s *Sorter) TrySortSliceByValue(slice interface{}, hash uint64) error {
+	cfg := s.config()
+	switch {
+	case cfg.Weight != nil && cfg.Algorithm == NSPCC:
+		return fmt.Errorf("%w: by %s", ErrUnsupportedWeightFunc, cfg.Algorithm)
+	case cfg.Algorithm == V2, cfg.Algorithm == V3:
+		return s.trySortSliceV2(slice, hash)
+	case cfg.Algorithm != V1:
+		return cfg.Algorithm.TrySortSliceByValue(slice, hash)
+	}
+
+	swap, length, ok := sliceSwapper(slice)
+	if !ok {
+		return notSlice(slice)
+	} else if length == 0 {
+		return nil
 	}
 
 	var (
-		val    = reflect.ValueOf(slice)
-		swap   = reflect.Swapper(slice)
-		length = val.Len()
-		rule   = make([]uint64, 0, length)
+		rule = s.ruleBuffer(length)
+		fn   = s.weightFunc()
 	)
 
-	if length == 0 {
-		return
-	}
-
 	switch slice := slice.(type) {
 	case []int:
-		var key = make([]byte, 16)
 		for i := 0; i < length; i++ {
-			binary.BigEndian.PutUint64(key, uint64(slice[i]))
-			rule = append(rule, weight(Hash(key), hash))
+			rule = append(rule, fn(hashIntV1(int64(slice[i])), hash))
+		}
+	case []int64:
+		for i := 0; i < length; i++ {
+			rule = append(rule, fn(hashIntV1(slice[i]), hash))
+		}
+	case []int16:
+		for i := 0; i < length; i++ {
+			rule = append(rule, fn(hashIntV1(int64(slice[i])), hash))
+		}
+	case []int8:
+		for i := 0; i < length; i++ {
+			rule = append(rule, fn(hashIntV1(int64(slice[i])), hash))
 		}
 	case []int32:
 		var key = make([]byte, 16)
 		for i := 0; i < length; i++ {
 			binary.BigEndian.PutUint32(key, uint32(slice[i]))
-			rule = append(rule, weight(Hash(key), hash))
+			rule = append(rule, fn(Hash(key), hash))
 		}
 	case []string:
 		for i := 0; i < length; i++ {
-			rule = append(rule, weight(hash,
-				Hash([]byte(slice[i]))))
+			rule = append(rule, fn(Hash([]byte(slice[i])), hash))
 		}
 	default:
-		if _, ok := val.Index(0).Interface().(Hasher); !ok {
-			return
+		at, err := sliceHashers(slice)
+		if err != nil {
+			return err
 		}
 
-		for i := 0; i < length; i++ {
-			h := val.Index(i).Interface().(Hasher)
-			rule = append(rule, weight(hash, h.Hash()))
-		}
+		var nils []int
+		rule, nils = hashersRule(at, length, rule, func(h uint64) uint64 {
+			return fn(h, hash)
+		})
+		return applyOrder(swap, length, s.sortByWeightFunc(rule, hash, fn).sorted, nils)
+	}
+
+	order := s.sortByWeightFunc(rule, hash, fn).sorted
+	sortByRuleInverseDone(swap, length, order, s.bitset(length))
+	return nil
+}
+
+// trySortSliceV2 sorts slice like V2 and V3 algorithms do
+func (s *Sorter) trySortSliceV2(slice interface{}, hash uint64) error {
+	swap, length, ok := sliceSwapper(slice)
+	if !ok {
+		return notSlice(slice)
+	} else if length == 0 {
+		return nil
 	}
 
-	rule = SortByWeight(rule, hash)
-	sortByRuleInverse(swap, uint64(length), rule)
+	rule, nils, err := prepareRuleV2(slice, length)
+	if err != nil {
+		return err
+	}
+	return applyOrder(swap, length, s.sortByWeightFunc(rule, hash, s.weightFunc()).sorted, nils)
+}
+
+// hashIntV1 hashes 64-bit two's complement of v padded to 16 bytes
+func hashIntV1(v int64) uint64 {
+	var key [16]byte
+	binary.BigEndian.PutUint64(key[:], uint64(v))
+	return Hash(key[:])
 }
 
-// SortSliceByIndex received []T and hash to sort by index-weight
+// SortSliceByIndex received []T and hash to sort by index-weight,
+// non-slice values are left untouched (or panic in strict mode)
 func SortSliceByIndex(slice interface{}, hash uint64) {
-	length := uint64(reflect.ValueOf(slice).Len())
-	swap := reflect.Swapper(slice)
-	rule := make([]uint64, 0, length)
-	for i := uint64(0); i < length; i++ {
+	checkStrict(TrySortSliceByIndex(slice, hash))
+}
+
+// TrySortSliceByIndex is like SortSliceByIndex,
+// but returns error for non-slice values
+func TrySortSliceByIndex(slice interface{}, hash uint64) error {
+	return (*Sorter)(nil).TrySortSliceByIndex(slice, hash)
+}
+
+// TrySortSliceByIndex is like package-level TrySortSliceByIndex, but uses
+// weight function of Sorter configuration
+func (s *Sorter) TrySortSliceByIndex(slice interface{}, hash uint64) error {
+	swap, length, ok := sliceSwapper(slice)
+	if !ok {
+		return notSlice(slice)
+	}
+
+	rule := s.ruleBuffer(length)
+	for i := uint64(0); i < uint64(length); i++ {
 		rule = append(rule, i)
 	}
-	rule = SortByWeight(rule, hash)
-	sortByRuleInverse(swap, length, rule)
+
+	fn := s.config().Weight
+	if fn == nil {
+		fn = weight
+	}
+	order := s.sortByWeightFunc(rule, hash, fn).sorted
+	sortByRuleInverseDone(swap, length, order, s.bitset(length))
+	return nil
 }
 
-func sortByRuleDirect(swap swapper, length uint64, rule []uint64) {
-	done := make([]bool, length)
-	for i := uint64(0); i < length; i++ {
-		if done[i] {
+func sortByRuleDirect(swap swapper, length int, rule []int32) {
+	done := newBitset(length)
+	for i := 0; i < length; i++ {
+		if done.has(uint64(i)) {
 			continue
 		}
-		for j := rule[i]; !done[rule[j]]; j = rule[j] {
-			swap(int(i), int(j))
-			done[j] = true
+		for j := rule[i]; !done.has(uint64(rule[j])); j = rule[j] {
+			swap(i, int(j))
+			done.set(uint64(j))
 		}
 	}
 }
 
-func sortByRuleInverse(swap swapper, length uint64, rule []uint64) {
-	done := make([]bool, length)
-	for i := uint64(0); i < length; i++ {
-		if done[i] {
+func sortByRuleInverse(swap swapper, length int, rule []int32) {
+	sortByRuleInverseDone(swap, length, rule, newBitset(length))
+}
+
+// sortByRuleInverseDone is like sortByRuleInverse, done must be empty
+func sortByRuleInverseDone(swap swapper, length int, rule []int32, done bitset) {
+	if debugChecks {
+		debugCheckRule(rule, length)
+	}
+
+	for i := 0; i < length; i++ {
+		if done.has(uint64(i)) {
 			continue
 		}
 
-		for j := i; !done[rule[j]]; j = rule[j] {
+		for j := int32(i); !done.has(uint64(rule[j])); j = rule[j] {
 			swap(int(j), int(rule[j]))
-			done[j] = true
+			done.set(uint64(j))
 		}
 	}
 }
@@ -0,0 +1,98 @@
+package hrw
+
+import (
+	"sort"
+
+	"github.com/im-kulikov/hrw/internal/murmur3"
+)
+
+type hashed32 struct {
+	sorted []uint32
+	weight []uint32
+}
+
+func (h hashed32) Len() int { return len(h.sorted) }
+func (h hashed32) Less(i, j int) bool {
+	wi, wj := h.weight[h.sorted[i]], h.weight[h.sorted[j]]
+	return wi < wj || (wi == wj && h.sorted[i] < h.sorted[j])
+}
+func (h hashed32) Swap(i, j int) { h.sorted[i], h.sorted[j] = h.sorted[j], h.sorted[i] }
+
+func weight32(x uint32, y uint32) uint32 {
+	return murmur3.Fmix32(x ^ y)
+}
+
+// Hash32 uses 32-bit murmur3 hash to return uint32.
+//
+// 32-bit scoring is intended for memory constrained 32-bit targets, it
+// uses half of memory of 64-bit one and no 64-bit multiplications.
+// Distribution of keys is close to 64-bit scoring, but weights of nodes
+// collide much more often: with n nodes probability of collision for a key
+// is about n*n/2^33 (~0.01% for 1000 nodes), colliding nodes are ordered
+// by their index. Orderings of 32 and 64-bit scoring are unrelated.
+func Hash32(key []byte) uint32 {
+	return murmur3.Sum32(key)
+}
+
+// SortByWeight32 receive nodes and 32-bit hash, and sort it by 32-bit weight
+func SortByWeight32(nodes []uint32, hash uint32) []uint32 {
+	h := hashed32{
+		sorted: make([]uint32, 0, len(nodes)),
+		weight: make([]uint32, 0, len(nodes)),
+	}
+
+	for i, node := range nodes {
+		h.sorted = append(h.sorted, uint32(i))
+		h.weight = append(h.weight, weight32(node, hash))
+	}
+
+	sort.Sort(h)
+	return h.sorted
+}
+
+// SortSliceByValue32 received []uint32 or []string and 32-bit hash
+// to sort by 32-bit value-weight, unsupported values are left untouched
+// (or panic in strict mode)
+func SortSliceByValue32(slice interface{}, hash uint32) {
+	checkStrict(TrySortSliceByValue32(slice, hash))
+}
+
+// TrySortSliceByValue32 is like SortSliceByValue32,
+// but returns error for unsupported values
+func TrySortSliceByValue32(slice interface{}, hash uint32) error {
+	swap, length, ok := sliceSwapper(slice)
+	if !ok {
+		return notSlice(slice)
+	} else if length == 0 {
+		return nil
+	}
+
+	rule := make([]uint32, 0, length)
+	switch slice := slice.(type) {
+	case []uint32:
+		rule = append(rule, slice...)
+	case []string:
+		for i := range slice {
+			rule = append(rule, Hash32([]byte(slice[i])))
+		}
+	default:
+		return unsupportedElement(slice)
+	}
+
+	sortByRuleInverse32(swap, SortByWeight32(rule, hash))
+	return nil
+}
+
+func sortByRuleInverse32(swap swapper, rule []uint32) {
+	done := newBitset(len(rule))
+	for i := range rule {
+		if done.has(uint64(i)) {
+			continue
+		}
+
+		for j := uint32(i); !done.has(uint64(rule[j])); j = rule[j] {
+			swap(int(j), int(rule[j]))
+			done.set(uint64(j))
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package hrw
+
+import (
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestSortByWeight32(t *testing.T) {
+	var (
+		nodes = []uint32{1, 2, 3, 4, 5}
+		hash  = Hash32(testKey)
+	)
+
+	actual := SortByWeight32(nodes, hash)
+	for i := 1; i < len(actual); i++ {
+		if weight32(nodes[actual[i-1]], hash) > weight32(nodes[actual[i]], hash) {
+			t.Fatalf("Order %v doesn't follow weights", actual)
+		}
+	}
+
+	t.Run("collisions ordered by index", func(t *testing.T) {
+		actual := SortByWeight32([]uint32{7, 7, 7}, hash)
+		if expect := []uint32{0, 1, 2}; !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+}
+
+func TestSortSliceByValue32(t *testing.T) {
+	var (
+		hash   = Hash32(testKey)
+		actual = []string{"a", "b", "c", "d", "e", "f"}
+		rule   = make([]uint32, 0, len(actual))
+	)
+
+	for _, s := range actual {
+		rule = append(rule, Hash32([]byte(s)))
+	}
+
+	expect := make([]string, 0, len(actual))
+	for _, i := range SortByWeight32(rule, hash) {
+		expect = append(expect, actual[i])
+	}
+
+	SortSliceByValue32(actual, hash)
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	t.Run("unknown type", func(t *testing.T) {
+		actual := []int{1, 2, 3}
+		SortSliceByValue32(actual, hash)
+		if expect := []int{1, 2, 3}; !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			slice  interface{}
+			expect error
+		}{
+			{slice: 10, expect: ErrNotSlice},
+			{slice: []int{1, 2, 3}, expect: ErrUnsupportedElement},
+			{slice: []int{}},
+			{slice: []string{"a", "b"}},
+		} {
+			if err := TrySortSliceByValue32(tc.slice, hash); !errors.Is(err, tc.expect) {
+				t.Errorf("Was %v, but expected %v", err, tc.expect)
+			}
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		SetStrict(true)
+		defer SetStrict(false)
+		defer func() {
+			if err, ok := recover().(error); !ok || !errors.Is(err, ErrUnsupportedElement) {
+				t.Errorf("Expected panic with %v, got %v", ErrUnsupportedElement, err)
+			}
+		}()
+		SortSliceByValue32([]int{1, 2, 3}, hash)
+	})
+}
+
+func TestUniformDistribution32(t *testing.T) {
+	const (
+		size    = 10
+		keys    = 100000
+		percent = 0.03
+	)
+
+	var (
+		nodes  = make([]string, size)
+		counts = make(map[string]int, size)
+		key    = make([]byte, 8)
+	)
+
+	for i := range nodes {
+		nodes[i] = strconv.Itoa(i)
+	}
+
+	for i := uint64(0); i < keys; i++ {
+		binary.BigEndian.PutUint64(key, i)
+		b := append([]string{}, nodes...)
+		SortSliceByValue32(b, Hash32(key))
+		counts[b[0]]++
+	}
+
+	mean := float64(keys) / float64(size)
+	delta := mean * percent
+	for node, count := range counts {
+		if d := mean - float64(count); d > delta || -d > delta {
+			t.Errorf(
+				"Node %s received %d keys, expected %.0f (+/- %.2f)",
+				node, count, mean, delta,
+			)
+		}
+	}
+}
@@ -48,6 +48,13 @@ func (h hashString) Hash() uint64 {
 	return Hash([]byte(h))
 }
 
+// requireReflect skips test of slices supported only with reflection
+func requireReflect(t *testing.T) {
+	if !withReflect {
+		t.Skip("requires reflection")
+	}
+}
+
 func TestSortSliceByIndex(t *testing.T) {
 	actual := []string{"a", "b", "c", "d", "e", "f"}
 	expect := []string{"e", "a", "c", "f", "d", "b"}
@@ -74,7 +81,7 @@ func TestSortByRule(t *testing.T) {
 		actual := []string{"a", "b", "c", "d", "e", "f"}
 		//                  4    2    0    5    3    1
 		expect := []string{"c", "f", "b", "e", "a", "d"}
-		rule := []uint64{4, 2, 0, 5, 3, 1}
+		rule := []int32{4, 2, 0, 5, 3, 1}
 
 		sortByRuleDirect(
 			func(i, j int) { actual[i], actual[j] = actual[j], actual[i] },
@@ -90,7 +97,7 @@ func TestSortByRule(t *testing.T) {
 		actual := []string{"a", "b", "c", "d", "e", "f"}
 		//                  4    2    0    5    3    1
 		expect := []string{"e", "c", "a", "f", "d", "b"}
-		rule := []uint64{4, 2, 0, 5, 3, 1}
+		rule := []int32{4, 2, 0, 5, 3, 1}
 
 		sortByRuleInverse(
 			func(i, j int) { actual[i], actual[j] = actual[j], actual[i] },
@@ -128,16 +135,6 @@ func TestSortSliceByValueFail(t *testing.T) {
 	})
 }
 
-func TestSortSliceByValueHasher(t *testing.T) {
-	actual := []hashString{"a", "b", "c", "d", "e", "f"}
-	expect := []hashString{"d", "b", "a", "f", "c", "e"}
-	hash := Hash(testKey)
-	SortSliceByValue(actual, hash)
-	if !reflect.DeepEqual(actual, expect) {
-		t.Errorf("Was %#v, but expected %#v", actual, expect)
-	}
-}
-
 func TestSortSliceByValueIntSlice(t *testing.T) {
 	actual := []int{0, 1, 2, 3, 4, 5}
 	expect := []int{2, 3, 1, 4, 0, 5}
@@ -158,6 +155,43 @@ func TestSortByWeight(t *testing.T) {
 	}
 }
 
+func TestHashString(t *testing.T) {
+	for _, key := range []string{"", "a", "localhost:60000/examples/object-key"} {
+		if actual, expect := HashString(key), Hash([]byte(key)); actual != expect {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	}
+
+	key := "localhost:60000/examples/object-key"
+	if allocs := testing.AllocsPerRun(100, func() { HashString(key) }); allocs != 0 {
+		t.Errorf("Was %v allocations, but expected 0", allocs)
+	}
+}
+
+func TestHashUint64(t *testing.T) {
+	for _, v := range []uint64{0, 1, 1 << 63} {
+		buf := make([]byte, 8)
+		binary.BigEndian.PutUint64(buf, v)
+		if actual, expect := HashUint64(v), Hash(buf); actual != expect {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { HashUint64(42) }); allocs != 0 {
+		t.Errorf("Was %v allocations, but expected 0", allocs)
+	}
+}
+
+func TestNew64(t *testing.T) {
+	h := New64()
+	_, _ = h.Write([]byte("/var/lib/data/"))
+	_, _ = h.Write([]byte("object-key"))
+
+	if actual, expect := h.Sum64(), Hash([]byte("/var/lib/data/object-key")); actual != expect {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
+
 func TestUniformDistribution(t *testing.T) {
 	const (
 		size    = 10
@@ -479,3 +513,29 @@ func benchmarkSortByValue(b *testing.B, n int, hash uint64) {
 		SortSliceByValue(servers, hash)
 	}
 }
+
+func TestSortByWeightWithTies(t *testing.T) {
+	hash := Hash(testKey)
+
+	if _, ties := SortByWeightWithTies([]uint64{1, 2, 3}, hash); ties {
+		t.Errorf("Expected no ties for distinct nodes")
+	}
+
+	actual, ties := SortByWeightWithTies([]uint64{7, 3, 7, 3}, hash)
+	if !ties {
+		t.Errorf("Expected ties for duplicated nodes")
+	}
+
+	// duplicates follow each other in order of indexes
+	for i := 0; i < len(actual); i += 2 {
+		if actual[i] > actual[i+1] {
+			t.Errorf("Was %#v, expected duplicates ordered by index", actual)
+		}
+	}
+
+	// constant weight function makes order depend on node values only
+	constant := func(uint64, uint64) uint64 { return 0 }
+	if actual, expect := SortByWeightFunc([]uint64{5, 1, 3}, hash, constant), []uint64{1, 2, 0}; !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
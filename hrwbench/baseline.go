@@ -0,0 +1,134 @@
+package hrwbench
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/im-kulikov/hrw"
+)
+
+type (
+	// Operation is a named operation to measure
+	Operation struct {
+		Name string
+		Run  func()
+	}
+
+	// Baseline maps names of operations to their recorded costs
+	Baseline map[string]Measurement
+
+	// Regression is an operation slower or allocating more than baseline
+	Regression struct {
+		Name     string
+		Baseline Measurement
+		Current  Measurement
+	}
+)
+
+// ErrRegression is matched by errors of Check
+var ErrRegression = errors.New("hrwbench: performance regression")
+
+// Operations returns main operations of hrw
+func Operations() []Operation {
+	var (
+		key    = []byte("/examples/object-key")
+		hash   = hrw.Hash(key)
+		nodes  = make([]uint64, 0, 100)
+		values = make([]string, 0, 100)
+		ring   = hrw.NewRing()
+	)
+
+	for i := 0; i < cap(nodes); i++ {
+		id := "node-" + strconv.Itoa(i)
+		nodes = append(nodes, hrw.Hash([]byte(id)))
+		values = append(values, id)
+		ring.Add(hrw.Node{ID: id})
+	}
+
+	return []Operation{
+		{Name: "Hash", Run: func() { hrw.Hash(key) }},
+		{Name: "SortByWeight/100", Run: func() { hrw.SortByWeight(nodes, hash) }},
+		{Name: "SortSliceByValue/100", Run: func() { hrw.SortSliceByValue(values, hash) }},
+		{Name: "Ring.Get/100", Run: func() { ring.Get(key) }},
+		{Name: "Ring.GetN/100", Run: func() { ring.GetN(key, 3) }},
+	}
+}
+
+// Record measures every operation for at least d
+func Record(ops []Operation, d time.Duration) Baseline {
+	b := make(Baseline, len(ops))
+	for _, op := range ops {
+		b[op.Name] = Measure(op.Run, d)
+	}
+	return b
+}
+
+// Compare returns operations of current which time or allocations per
+// operation exceed baseline by more than threshold share (e.g. 0.2),
+// operations missing in baseline are ignored. Regressions are ordered
+// by name.
+func (b Baseline) Compare(current Baseline, threshold float64) []Regression {
+	var result []Regression
+	for name, cur := range current {
+		base, ok := b[name]
+		if !ok {
+			continue
+		}
+
+		if exceeds(cur.NsPerOp, base.NsPerOp, threshold) || exceeds(cur.AllocsPerOp, base.AllocsPerOp, threshold) {
+			result = append(result, Regression{Name: name, Baseline: base, Current: cur})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Check measures ops and compares them with baseline, error lists
+// regressions exceeding threshold
+func (b Baseline) Check(ops []Operation, threshold float64, d time.Duration) error {
+	regs := b.Compare(Record(ops, d), threshold)
+	if len(regs) == 0 {
+		return nil
+	}
+
+	var details string
+	for _, r := range regs {
+		details += fmt.Sprintf("\n%s: %.1f ns/op (was %.1f), %.1f allocs/op (was %.1f)",
+			r.Name, r.Current.NsPerOp, r.Baseline.NsPerOp, r.Current.AllocsPerOp, r.Baseline.AllocsPerOp)
+	}
+	return fmt.Errorf("%w:%s", ErrRegression, details)
+}
+
+// WriteTo writes baseline as JSON object
+func (b Baseline) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// ReadBaseline reads baseline written by Baseline.WriteTo
+func ReadBaseline(r io.Reader) (Baseline, error) {
+	var b Baseline
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func exceeds(current, base, threshold float64) bool {
+	if base == 0 {
+		// allocations appeared where there were none
+		return current >= 1
+	}
+	return current > base*(1+threshold)
+}
@@ -0,0 +1,63 @@
+package hrwbench
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBaseline(t *testing.T) {
+	ops := Operations()
+	base := Record(ops, time.Millisecond)
+	if len(base) != len(ops) {
+		t.Fatalf("Was %d measurements, but expected %d", len(base), len(ops))
+	}
+
+	buf := new(bytes.Buffer)
+	if _, err := base.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := ReadBaseline(buf)
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(decoded, base) {
+		t.Errorf("Was %#v, but expected %#v", decoded, base)
+	}
+
+	// generous threshold keeps test stable on loaded machines
+	if err := base.Check(ops, 100, time.Millisecond); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base := Baseline{
+		"fast":   {NsPerOp: 100, AllocsPerOp: 1},
+		"allocs": {NsPerOp: 100},
+		"same":   {NsPerOp: 100, AllocsPerOp: 1},
+	}
+
+	current := Baseline{
+		"fast":    {NsPerOp: 130, AllocsPerOp: 1},
+		"allocs":  {NsPerOp: 100, AllocsPerOp: 1},
+		"same":    {NsPerOp: 110, AllocsPerOp: 1},
+		"unknown": {NsPerOp: 1000},
+	}
+
+	var names []string
+	for _, r := range base.Compare(current, 0.2) {
+		names = append(names, r.Name)
+	}
+
+	if expect := []string{"allocs", "fast"}; !reflect.DeepEqual(names, expect) {
+		t.Errorf("Was %#v, but expected %#v", names, expect)
+	}
+
+	slow := Baseline{"Hash": {NsPerOp: 0.001}}
+	if err := slow.Check(Operations(), 0.2, time.Millisecond); !errors.Is(err, ErrRegression) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrRegression)
+	}
+}
@@ -0,0 +1,145 @@
+// Package hrwbench compares hash backends and weight mixers of hrw across
+// node counts and key sizes and reports structured results.
+package hrwbench
+
+import (
+	"encoding/json"
+	"io"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/im-kulikov/hrw"
+)
+
+type (
+	// Backend is a combination of key hash and weight mixer
+	Backend struct {
+		Name string
+		Hash hrw.HashFunc
+		Mix  hrw.WeightFunc
+	}
+
+	// Config of benchmark, zero fields are replaced by defaults
+	Config struct {
+		Backends []Backend
+		Nodes    []int
+		KeySizes []int
+		// Duration of every measurement, 100ms by default
+		Duration time.Duration
+	}
+
+	// Measurement is cost of operation
+	Measurement struct {
+		Iterations  int     `json:"iterations"`
+		NsPerOp     float64 `json:"ns_per_op"`
+		AllocsPerOp float64 `json:"allocs_per_op"`
+		BytesPerOp  float64 `json:"bytes_per_op"`
+	}
+
+	// Result of one measurement: hashing of key and ordering of nodes
+	Result struct {
+		Backend string `json:"backend"`
+		Nodes   int    `json:"nodes"`
+		KeySize int    `json:"key_size"`
+		Measurement
+	}
+)
+
+// Backends returns backends shipped with hrw
+func Backends() []Backend {
+	return []Backend{
+		{Name: "murmur3", Hash: hrw.Hash, Mix: hrw.MixMurmur3},
+		{Name: "murmur3+splitmix64", Hash: hrw.Hash, Mix: hrw.MixSplitMix64},
+		{Name: "murmur3+premixed", Hash: hrw.Hash, Mix: hrw.MixPremixed},
+		{Name: "sha256", Hash: hrw.HashSHA256, Mix: hrw.MixMurmur3},
+	}
+}
+
+// Run measures every backend for every count of nodes and key size
+func Run(cfg Config) []Result {
+	if cfg.Backends == nil {
+		cfg.Backends = Backends()
+	}
+	if cfg.Nodes == nil {
+		cfg.Nodes = []int{10, 100, 1000}
+	}
+	if cfg.KeySizes == nil {
+		cfg.KeySizes = []int{16, 64, 1024}
+	}
+	if cfg.Duration <= 0 {
+		cfg.Duration = 100 * time.Millisecond
+	}
+
+	var results []Result
+	for _, b := range cfg.Backends {
+		for _, n := range cfg.Nodes {
+			for _, size := range cfg.KeySizes {
+				results = append(results, measure(b, n, size, cfg.Duration))
+			}
+		}
+	}
+	return results
+}
+
+// WriteJSON writes results as JSON array
+func WriteJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+func measure(b Backend, n, size int, d time.Duration) Result {
+	var (
+		key   = make([]byte, size)
+		nodes = make([]uint64, 0, n)
+	)
+
+	for i := range key {
+		key[i] = byte(i)
+	}
+
+	for i := 0; i < n; i++ {
+		nodes = append(nodes, b.Hash([]byte("node-"+strconv.Itoa(i))))
+	}
+
+	op := func() {
+		hrw.SortByWeightFunc(nodes, b.Hash(key), b.Mix)
+	}
+
+	return Result{Backend: b.Name, Nodes: n, KeySize: size, Measurement: Measure(op, d)}
+}
+
+// Measure runs op at least for d and returns it's average cost
+func Measure(op func(), d time.Duration) Measurement {
+	for iterations := 1; ; iterations *= 2 {
+		elapsed, allocs, bytes := loop(op, iterations)
+		if elapsed < d && iterations < 1<<30 {
+			continue
+		}
+
+		return Measurement{
+			Iterations:  iterations,
+			NsPerOp:     float64(elapsed.Nanoseconds()) / float64(iterations),
+			AllocsPerOp: float64(allocs) / float64(iterations),
+			BytesPerOp:  float64(bytes) / float64(iterations),
+		}
+	}
+}
+
+// loop runs op count times and returns elapsed time, count and size
+// of allocations
+func loop(op func(), count int) (time.Duration, uint64, uint64) {
+	var before, after runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for i := 0; i < count; i++ {
+		op()
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	return elapsed, after.Mallocs - before.Mallocs, after.TotalAlloc - before.TotalAlloc
+}
@@ -0,0 +1,33 @@
+package hrwbench
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	results := Run(Config{Nodes: []int{10}, KeySizes: []int{8, 32}, Duration: time.Millisecond})
+	if expect := len(Backends()) * 2; len(results) != expect {
+		t.Fatalf("Was %d results, but expected %d", len(results), expect)
+	}
+
+	for _, r := range results {
+		if r.Iterations == 0 || r.NsPerOp <= 0 || r.AllocsPerOp <= 0 {
+			t.Errorf("Was %#v, but expected positive measurements", r)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := WriteJSON(buf, results); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded []Result
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	} else if len(decoded) != len(results) || decoded[0] != results[0] {
+		t.Errorf("Was %#v, but expected %#v", decoded, results)
+	}
+}
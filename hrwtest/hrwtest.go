@@ -0,0 +1,115 @@
+// Package hrwtest provides deterministic fakes and assertions for tests
+// of code built on top of hrw, so tests don't depend on real hash values.
+package hrwtest
+
+import (
+	"testing"
+
+	"github.com/im-kulikov/hrw"
+)
+
+// Fake assigns fixed orderings of nodes to keys. Configure it by Order
+// before use, it isn't safe to change concurrently with selection.
+// Orderings hold for nodes of equal weights.
+type Fake struct {
+	values map[string]uint64
+	orders map[uint64]map[uint64]uint64
+}
+
+// registered values are small integers, others are real hashes
+// with the highest bit set, so they never collide
+const unregistered = 1 << 63
+
+// New creates Fake without orderings
+func New() *Fake {
+	return &Fake{
+		values: make(map[string]uint64),
+		orders: make(map[uint64]map[uint64]uint64),
+	}
+}
+
+// Order makes key prefer nodes with given IDs in given order, other nodes
+// follow them in order of their hashes
+func (f *Fake) Order(key string, ids ...string) *Fake {
+	ranks := make(map[uint64]uint64, len(ids))
+	for i, id := range ids {
+		ranks[f.register(id)] = uint64(i + 1)
+	}
+	f.orders[f.register(key)] = ranks
+	return f
+}
+
+// Install makes Ring use Fake to hash keys and weight nodes
+func (f *Fake) Install(r *hrw.Ring) {
+	r.SetHash(f.Hash)
+	r.SetWeightFunc(f.Weight)
+}
+
+// Hash is hrw.HashFunc of Fake
+func (f *Fake) Hash(key []byte) uint64 {
+	if v, ok := f.values[string(key)]; ok {
+		return v
+	}
+	return hrw.Hash(key) | unregistered
+}
+
+// Weight is hrw.WeightFunc of Fake, nodes ordered for key get the lowest
+// weights in order, other nodes are weighted by their hashes
+func (f *Fake) Weight(node, hash uint64) uint64 {
+	if rank, ok := f.orders[hash][node]; ok {
+		return rank << 40
+	}
+	return 1<<62 | node>>2
+}
+
+func (f *Fake) register(s string) uint64 {
+	v, ok := f.values[s]
+	if !ok {
+		v = uint64(len(f.values) + 1)
+		f.values[s] = v
+	}
+	return v
+}
+
+// AssertOrder checks that the most preferable nodes for key have given IDs
+func AssertOrder(t testing.TB, r *hrw.Ring, key []byte, ids ...string) {
+	t.Helper()
+
+	nodes := r.GetN(key, len(ids))
+	actual := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		actual = append(actual, n.ID)
+	}
+
+	if len(actual) != len(ids) {
+		t.Errorf("Was %#v, but expected %#v", actual, ids)
+		return
+	}
+
+	for i := range ids {
+		if actual[i] != ids[i] {
+			t.Errorf("Was %#v, but expected %#v", actual, ids)
+			return
+		}
+	}
+}
+
+// AssertOwner checks that the most preferable node for key has given ID
+func AssertOwner(t testing.TB, r *hrw.Ring, key []byte, id string) {
+	t.Helper()
+	AssertOrder(t, r, key, id)
+}
+
+// AssertMoved checks that owners of at most share of keys differ
+// between snapshots
+func AssertMoved(t testing.TB, before, after *hrw.Snapshot, keys [][]byte, share float64) {
+	t.Helper()
+
+	if len(keys) == 0 {
+		return
+	}
+
+	if moved := float64(len(hrw.Moves(before, after, keys))) / float64(len(keys)); moved > share {
+		t.Errorf("Was %.3f of keys moved, but expected at most %.3f", moved, share)
+	}
+}
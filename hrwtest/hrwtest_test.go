@@ -0,0 +1,47 @@
+package hrwtest
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/im-kulikov/hrw"
+)
+
+func TestFake(t *testing.T) {
+	r := hrw.NewRing(hrw.Node{ID: "a"}, hrw.Node{ID: "b"}, hrw.Node{ID: "c"})
+	New().
+		Order("key-1", "c", "a", "b").
+		Order("key-2", "b").
+		Install(r)
+
+	AssertOrder(t, r, []byte("key-1"), "c", "a", "b")
+	AssertOwner(t, r, []byte("key-2"), "b")
+
+	before := r.Snapshot()
+	r.Remove("c")
+	AssertOwner(t, r, []byte("key-1"), "a")
+
+	keys := make([][]byte, 0, 100)
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, []byte("key-"+strconv.Itoa(i)))
+	}
+	AssertMoved(t, before, r.Snapshot(), keys, 1)
+}
+
+func TestAssertOrder(t *testing.T) {
+	r := hrw.NewRing(hrw.Node{ID: "a"}, hrw.Node{ID: "b"})
+	New().Order("key", "a", "b").Install(r)
+
+	rec := &recorder{TB: t}
+	AssertOrder(rec, r, []byte("key"), "b", "a")
+	if !rec.failed {
+		t.Errorf("Expected assertion to fail")
+	}
+}
+
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Errorf(string, ...interface{}) { r.failed = true }
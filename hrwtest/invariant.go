@@ -0,0 +1,107 @@
+package hrwtest
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/im-kulikov/hrw"
+)
+
+// SortFunc orders nodes for hash and returns their indexes,
+// like hrw.SortByWeight does
+type SortFunc func(nodes []uint64, hash uint64) []uint64
+
+// ErrInvariant is matched by errors of invariant checkers
+var ErrInvariant = errors.New("hrwtest: invariant violated")
+
+// WeightSort returns SortFunc ordering nodes by weights of fn
+func WeightSort(fn hrw.WeightFunc) SortFunc {
+	return func(nodes []uint64, hash uint64) []uint64 {
+		return hrw.SortByWeightFunc(nodes, hash, fn)
+	}
+}
+
+// CheckPermutation checks that fn returns every index of nodes exactly once
+func CheckPermutation(fn SortFunc, nodes []uint64, hash uint64) error {
+	order := fn(nodes, hash)
+	if len(order) != len(nodes) {
+		return fmt.Errorf("%w: %d indexes for %d nodes", ErrInvariant, len(order), len(nodes))
+	}
+
+	seen := make([]bool, len(nodes))
+	for _, i := range order {
+		if i >= uint64(len(nodes)) || seen[i] {
+			return fmt.Errorf("%w: order %v isn't a permutation", ErrInvariant, order)
+		}
+		seen[i] = true
+	}
+	return nil
+}
+
+// CheckDeterminism checks that fn returns equal orders for equal inputs
+// and doesn't modify nodes
+func CheckDeterminism(fn SortFunc, nodes []uint64, hash uint64) error {
+	input := append([]uint64(nil), nodes...)
+	first := fn(input, hash)
+	if !equal(input, nodes) {
+		return fmt.Errorf("%w: nodes were modified", ErrInvariant)
+	}
+
+	if second := fn(input, hash); !equal(first, second) {
+		return fmt.Errorf("%w: orders %v and %v differ", ErrInvariant, first, second)
+	}
+	return nil
+}
+
+// CheckRemoval checks that removing node i doesn't change relative order
+// of other nodes, so only keys of removed node move
+func CheckRemoval(fn SortFunc, nodes []uint64, hash uint64, i int) error {
+	if i < 0 || i >= len(nodes) {
+		return fmt.Errorf("hrwtest: index %d out of range", i)
+	}
+
+	rest := make([]uint64, 0, len(nodes)-1)
+	rest = append(append(rest, nodes[:i]...), nodes[i+1:]...)
+
+	expect := make([]uint64, 0, len(rest))
+	for _, j := range fn(nodes, hash) {
+		switch {
+		case j < uint64(i):
+			expect = append(expect, j)
+		case j > uint64(i):
+			expect = append(expect, j-1)
+		}
+	}
+
+	if actual := fn(rest, hash); !equal(actual, expect) {
+		return fmt.Errorf("%w: removing node %d changed order to %v, expected %v",
+			ErrInvariant, i, actual, expect)
+	}
+	return nil
+}
+
+// CheckAll checks all invariants for nodes and hash, removal is checked
+// for the most preferable node
+func CheckAll(fn SortFunc, nodes []uint64, hash uint64) error {
+	if err := CheckPermutation(fn, nodes, hash); err != nil {
+		return err
+	} else if err = CheckDeterminism(fn, nodes, hash); err != nil {
+		return err
+	} else if len(nodes) == 0 {
+		return nil
+	}
+	return CheckRemoval(fn, nodes, hash, int(fn(nodes, hash)[0]))
+}
+
+func equal(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package hrwtest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/im-kulikov/hrw"
+)
+
+func TestCheckAll(t *testing.T) {
+	nodes := []uint64{1, 2, 3, 4, 5, 6, 7, 8}
+
+	for _, fn := range []SortFunc{hrw.SortByWeight, WeightSort(hrw.MixSplitMix64), hrw.V3.SortByWeight} {
+		for hash := uint64(0); hash < 100; hash++ {
+			if err := CheckAll(fn, nodes, hash); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := CheckAll(hrw.SortByWeight, nil, 0); err != nil {
+		t.Errorf("Was %#v, but expected nil", err)
+	}
+}
+
+func TestCheckViolations(t *testing.T) {
+	nodes := []uint64{1, 2, 3}
+
+	reversed := func(nodes []uint64, _ uint64) []uint64 {
+		order := make([]uint64, 0, len(nodes))
+		for i := len(nodes) - 1; i >= 0; i-- {
+			order = append(order, uint64(i))
+		}
+		// order depends on count of nodes
+		if len(nodes)%2 == 0 {
+			order[0], order[1] = order[1], order[0]
+		}
+		return order
+	}
+
+	if err := CheckRemoval(reversed, nodes, 0, 1); !errors.Is(err, ErrInvariant) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrInvariant)
+	}
+
+	duplicate := func(nodes []uint64, _ uint64) []uint64 { return make([]uint64, len(nodes)) }
+	if err := CheckPermutation(duplicate, nodes, 0); !errors.Is(err, ErrInvariant) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrInvariant)
+	}
+
+	counter := uint64(0)
+	unstable := func(nodes []uint64, _ uint64) []uint64 {
+		counter++
+		return []uint64{counter % 3, (counter + 1) % 3, (counter + 2) % 3}
+	}
+	if err := CheckDeterminism(unstable, nodes, 0); !errors.Is(err, ErrInvariant) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrInvariant)
+	}
+}
@@ -0,0 +1,126 @@
+package hrw
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type (
+	// Hysteresis keeps owners of keys selected by Ring stable against
+	// jitter of weights. Owner of key is replaced by the most preferable
+	// node only when score of challenger is lower by more than margin
+	// (fraction of owner score) for at least hold, or immediately when owner
+	// isn't selectable anymore or challenger is of preferable tier.
+	// It remembers every key asked, use Forget to drop unused ones.
+	Hysteresis struct {
+		ring   *Ring
+		margin float64
+		hold   time.Duration
+		now    func() time.Time
+		mu     sync.Mutex
+		keys   map[string]*tenure
+	}
+
+	tenure struct {
+		owner      string
+		challenger string
+		since      time.Time
+	}
+)
+
+// NewHysteresis creates Hysteresis over Ring
+func NewHysteresis(r *Ring, margin float64, hold time.Duration) *Hysteresis {
+	return &Hysteresis{
+		ring:   r,
+		margin: margin,
+		hold:   hold,
+		now:    time.Now,
+		keys:   make(map[string]*tenure),
+	}
+}
+
+// Get returns owner of key, false is returned when Ring has no
+// active nodes
+func (h *Hysteresis) Get(key []byte) (Node, bool) {
+	var (
+		s    = h.ring.view()
+		list = s.rank(s.Hash(key))
+	)
+
+	if len(list) == 0 {
+		return Node{}, false
+	}
+
+	s.penalize(list)
+	best := list[0]
+	for _, c := range list[1:] {
+		if c.less(best) {
+			best = c
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t, ok := h.keys[string(key)]
+	if !ok {
+		h.keys[string(key)] = &tenure{owner: s.nodes[best.index].ID}
+		return s.nodes[best.index].Node, true
+	}
+
+	owner := -1
+	for i, c := range list {
+		if s.nodes[c.index].ID == t.owner {
+			owner = i
+			break
+		}
+	}
+
+	switch {
+	case owner < 0, outranks(best, list[owner]):
+		// owner isn't selectable anymore or challenger is of preferable tier
+	case best.index == list[owner].index || !h.exceeds(best, list[owner]):
+		t.challenger = ""
+		return s.nodes[list[owner].index].Node, true
+	default:
+		now := h.now()
+		if id := s.nodes[best.index].ID; t.challenger != id {
+			t.challenger, t.since = id, now
+		}
+
+		if now.Sub(t.since) < h.hold {
+			return s.nodes[list[owner].index].Node, true
+		}
+	}
+
+	t.owner, t.challenger = s.nodes[best.index].ID, ""
+	return s.nodes[best.index].Node, true
+}
+
+// Forget drops owner of key, so it's selected again by the next Get
+func (h *Hysteresis) Forget(key []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.keys, string(key))
+}
+
+// Len returns count of remembered keys
+func (h *Hysteresis) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.keys)
+}
+
+// outranks reports whether challenger is preferable regardless of
+// scores, i.e. it's of lower tier or owner is selected last
+func outranks(challenger, owner candidate) bool {
+	return challenger.gray != owner.gray || challenger.tier != owner.tier
+}
+
+// exceeds reports whether advantage of challenger exceeds margin
+func (h *Hysteresis) exceeds(challenger, owner candidate) bool {
+	return owner.score-challenger.score > h.margin*math.Abs(owner.score)
+}
@@ -0,0 +1,67 @@
+package hrw
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHysteresis(t *testing.T) {
+	var (
+		key   []byte
+		owner Node
+		other = "b"
+		now   = time.Unix(0, 0)
+		r     = NewRing(Node{ID: "a"}, Node{ID: "b"})
+		h     = NewHysteresis(r, 0.9, time.Minute)
+	)
+
+	h.now = func() time.Time { return now }
+
+	// find key slightly preferring other node after it's weight is raised
+	for i := 0; key == nil; i++ {
+		k := []byte("key" + strconv.Itoa(i))
+		if n, _ := r.Get(k); n.ID == other {
+			continue
+		}
+
+		r.Add(Node{ID: other, Weight: 1.5})
+		if n, _ := r.Get(k); n.ID == other {
+			key = k
+		}
+		r.Add(Node{ID: other})
+	}
+
+	owner, _ = h.Get(key)
+	if owner.ID == other {
+		t.Fatalf("Was %#v, but expected %#v", owner.ID, "a")
+	}
+
+	// slight advantage doesn't move key
+	r.Add(Node{ID: other, Weight: 1.5})
+	if n, _ := h.Get(key); n.ID != owner.ID {
+		t.Errorf("Was %#v, but expected %#v", n.ID, owner.ID)
+	}
+
+	// large advantage moves key only after hold
+	r.Add(Node{ID: other, Weight: 1e6})
+	if n, _ := h.Get(key); n.ID != owner.ID {
+		t.Errorf("Was %#v, but expected %#v", n.ID, owner.ID)
+	}
+
+	now = now.Add(time.Minute)
+	if n, _ := h.Get(key); n.ID != other {
+		t.Errorf("Was %#v, but expected %#v", n.ID, other)
+	}
+
+	// owner leaving moves key immediately
+	r.Remove(other)
+	if n, _ := h.Get(key); n.ID != owner.ID {
+		t.Errorf("Was %#v, but expected %#v", n.ID, owner.ID)
+	}
+
+	h.Forget(key)
+	if h.Len() != 0 {
+		t.Errorf("Was %d, but expected %d", h.Len(), 0)
+	}
+}
@@ -0,0 +1,61 @@
+package hrw
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSortSliceByValueInterfaces(t *testing.T) {
+	hash := Hash(testKey)
+
+	t.Run("heterogeneous", func(t *testing.T) {
+		var (
+			expect = []Hasher{hashString("a"), hashString("b"), hashString("c"), hashString("d")}
+			actual = []interface{}{
+				hashString("a"), &hashNode{id: "b"}, hashString("c"), &hashNode{id: "d"},
+			}
+		)
+
+		SortSliceByValue(expect, hash)
+		if err := TrySortSliceByValue(actual, hash); err != nil {
+			t.Fatal(err)
+		}
+
+		for i := range expect {
+			if actual[i].(Hasher).Hash() != expect[i].Hash() {
+				t.Fatalf("Was %#v, but expected %#v", actual, expect)
+			}
+		}
+	})
+
+	t.Run("element without Hasher", func(t *testing.T) {
+		actual := []interface{}{hashString("a"), hashString("b"), 10}
+		err := TrySortSliceByValue(actual, hash)
+		if !errors.Is(err, ErrUnsupportedElement) || !strings.Contains(err.Error(), "element 2") {
+			t.Errorf("Was %v, but expected %v for element 2", err, ErrUnsupportedElement)
+		}
+
+		if actual[0] != hashString("a") || actual[1] != hashString("b") {
+			t.Errorf("Expected slice to be untouched, got %#v", actual)
+		}
+	})
+
+	t.Run("nil elements", func(t *testing.T) {
+		actual := []interface{}{nil, hashString("a"), (*hashNode)(nil), hashString("b")}
+		if err := TrySortSliceByValue(actual, hash); !errors.Is(err, ErrNilElement) {
+			t.Errorf("Was %v, but expected %v", err, ErrNilElement)
+		}
+
+		if actual[2] != nil || actual[3] != (*hashNode)(nil) {
+			t.Errorf("Expected nil elements to be placed last, got %#v", actual)
+		}
+	})
+
+	t.Run("interface slice", func(t *testing.T) {
+		actual := []Hasher{hashString("a"), &hashNode{id: "b"}}
+		if err := V2.TrySortSliceByValue(actual, hash); err != nil {
+			t.Error(err)
+		}
+	})
+}
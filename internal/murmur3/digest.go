@@ -0,0 +1,63 @@
+package murmur3
+
+import "encoding/binary"
+
+// Digest computes Sum64 of written data incrementally
+type Digest struct {
+	h1, h2 uint64
+	length uint64
+	buf    [16]byte
+	n      int
+}
+
+// New64 returns Digest which Sum64 equals Sum64 of all written data
+func New64() *Digest {
+	return new(Digest)
+}
+
+// Write adds data to Digest, it never returns an error
+func (d *Digest) Write(data []byte) (int, error) {
+	written := len(data)
+	d.length += uint64(written)
+
+	if d.n > 0 {
+		n := copy(d.buf[d.n:], data)
+		if d.n += n; d.n < len(d.buf) {
+			return written, nil
+		}
+
+		data = data[n:]
+		d.h1, d.h2 = block(d.h1, d.h2, binary.LittleEndian.Uint64(d.buf[:]), binary.LittleEndian.Uint64(d.buf[8:]))
+		d.n = 0
+	}
+
+	for ; len(data) >= 16; data = data[16:] {
+		d.h1, d.h2 = block(d.h1, d.h2, binary.LittleEndian.Uint64(data), binary.LittleEndian.Uint64(data[8:]))
+	}
+
+	d.n = copy(d.buf[:], data)
+	return written, nil
+}
+
+// Sum64 returns sum of written data, it doesn't change Digest
+func (d *Digest) Sum64() uint64 {
+	return tail(d.h1, d.h2, d.buf[:d.n], d.length)
+}
+
+// Sum appends big-endian encoding of Sum64 to b
+func (d *Digest) Sum(b []byte) []byte {
+	var tmp [8]byte
+	binary.BigEndian.PutUint64(tmp[:], d.Sum64())
+	return append(b, tmp[:]...)
+}
+
+// Reset resets Digest to initial state
+func (d *Digest) Reset() {
+	*d = Digest{}
+}
+
+// Size returns size of sum in bytes
+func (d *Digest) Size() int { return 8 }
+
+// BlockSize returns size of block in bytes
+func (d *Digest) BlockSize() int { return 16 }
@@ -0,0 +1,42 @@
+package murmur3
+
+import (
+	"hash"
+	"strings"
+	"testing"
+)
+
+var _ hash.Hash64 = (*Digest)(nil)
+
+func TestDigest(t *testing.T) {
+	data := []byte(strings.Repeat("0123456789", 10))
+
+	for _, step := range []int{1, 3, 7, 15, 16, 17, 33, len(data)} {
+		d := New64()
+		for i := 0; i < len(data); i += step {
+			end := i + step
+			if end > len(data) {
+				end = len(data)
+			}
+
+			if _, err := d.Write(data[i:end]); err != nil {
+				t.Fatal(err)
+			}
+
+			if actual, expect := d.Sum64(), Sum64(data[:end]); actual != expect {
+				t.Fatalf("Sum64 with step %d was %#x, but expected %#x", step, actual, expect)
+			}
+		}
+	}
+
+	d := New64()
+	_, _ = d.Write(data)
+	d.Reset()
+	if actual, expect := d.Sum64(), Sum64(nil); actual != expect {
+		t.Errorf("Sum64 after Reset was %#x, but expected %#x", actual, expect)
+	}
+
+	if actual := d.Sum([]byte{1}); len(actual) != 9 || actual[0] != 1 {
+		t.Errorf("Sum was %#v, but expected 9 bytes starting with 1", actual)
+	}
+}
@@ -0,0 +1,57 @@
+package murmur3
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	c1x32 = 0xcc9e2d51
+	c2x32 = 0x1b873593
+)
+
+// Sum32 returns x86 32-bit MurmurHash3 sum of data with zero seed
+func Sum32(data []byte) uint32 {
+	var (
+		h1     uint32
+		length = uint32(len(data))
+	)
+
+	for ; len(data) >= 4; data = data[4:] {
+		h1 ^= mixK32(binary.LittleEndian.Uint32(data))
+		h1 = bits.RotateLeft32(h1, 13)
+		h1 = h1*5 + 0xe6546b64
+	}
+
+	var k1 uint32
+	switch len(data) {
+	case 3:
+		k1 ^= uint32(data[2]) << 16
+		fallthrough
+	case 2:
+		k1 ^= uint32(data[1]) << 8
+		fallthrough
+	case 1:
+		k1 ^= uint32(data[0])
+		h1 ^= mixK32(k1)
+	}
+
+	h1 ^= length
+	return Fmix32(h1)
+}
+
+// Fmix32 is a 32-bit finalizer of MurmurHash3
+func Fmix32(h uint32) uint32 {
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
+
+func mixK32(k uint32) uint32 {
+	k *= c1x32
+	k = bits.RotateLeft32(k, 15)
+	return k * c2x32
+}
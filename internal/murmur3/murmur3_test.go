@@ -0,0 +1,76 @@
+package murmur3
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestSum64(t *testing.T) {
+	// vectors are produced by github.com/spaolacci/murmur3
+	cases := []struct {
+		data   string
+		expect uint64
+	}{
+		{"", 0x0000000000000000},
+		{"a", 0x85555565f6597889},
+		{"abc", 0xb4963f3f3fad7867},
+		{"hello, world", 0x342fac623a5ebc8e},
+		{strings.Repeat("x", 15), 0x1cfd62bac822c29a},
+		{strings.Repeat("x", 16), 0x68ccbbacd92543dd},
+		{strings.Repeat("0123456789", 5), 0x745884bb3a1039b8},
+		{"0xff51afd7ed558ccd", 0x498ae503303ca9e7},
+	}
+
+	for _, tc := range cases {
+		if actual := Sum64([]byte(tc.data)); actual != tc.expect {
+			t.Errorf("Sum64(%q) was %#x, but expected %#x", tc.data, actual, tc.expect)
+		}
+
+		if actual := Sum64String(tc.data); actual != tc.expect {
+			t.Errorf("Sum64String(%q) was %#x, but expected %#x", tc.data, actual, tc.expect)
+		}
+	}
+}
+
+func TestSum64Uint64(t *testing.T) {
+	for _, v := range []uint64{0, 1, 0xff51afd7ed558ccd, 1<<64 - 1} {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], v)
+		if actual, expect := Sum64Uint64(v), Sum64(buf[:]); actual != expect {
+			t.Errorf("Sum64Uint64(%#x) was %#x, but expected %#x", v, actual, expect)
+		}
+	}
+}
+
+func BenchmarkSum64(b *testing.B) {
+	data := []byte("localhost:60000/examples/object-key")
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		_ = Sum64(data)
+	}
+}
+
+func TestSum32(t *testing.T) {
+	// vectors are produced by github.com/spaolacci/murmur3
+	cases := []struct {
+		data   string
+		expect uint32
+	}{
+		{"", 0x00000000},
+		{"a", 0x3c2569b2},
+		{"abc", 0xb3dd93fa},
+		{"abcd", 0x43ed676a},
+		{"hello, world", 0x149bbb7f},
+		{strings.Repeat("x", 15), 0xd362376a},
+		{strings.Repeat("0123456789", 5), 0xbffabc05},
+	}
+
+	for _, tc := range cases {
+		if actual := Sum32([]byte(tc.data)); actual != tc.expect {
+			t.Errorf("Sum32(%q) was %#x, but expected %#x", tc.data, actual, tc.expect)
+		}
+	}
+}
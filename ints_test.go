@@ -0,0 +1,61 @@
+package hrw
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSortSliceByValueNegativeInts(t *testing.T) {
+	hash := Hash(testKey)
+
+	t.Run("frozen", func(t *testing.T) {
+		// []int must be hashed as 64-bit two's complement on every platform
+		actual := []int{-3, -2, -1, 0, 1, 2}
+		expect := []int{2, -1, 1, -2, 0, -3}
+		SortSliceByValue(actual, hash)
+		if !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+
+	t.Run("baseline", func(t *testing.T) {
+		// recorded before canonical hashing was defined, on 64 and 32-bit
+		// platforms, so []int needs no compatibility switch
+		if actual, expect := hashIntV1(-1), uint64(0xefa86f781580b321); actual != expect {
+			t.Errorf("Was %#x, but expected %#x", actual, expect)
+		}
+
+		actual := []int{-1, -2, 1, 2, -1 << 30, 3, -3, 0}
+		expect := []int{2, -1, 3, -1 << 30, 1, -2, 0, -3}
+		SortSliceByValue(actual, hash)
+		if !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+
+	t.Run("signed types agree", func(t *testing.T) {
+		var (
+			ints   = []int{-128, -3, -1, 0, 1, 127}
+			int64s = []int64{-128, -3, -1, 0, 1, 127}
+			int16s = []int16{-128, -3, -1, 0, 1, 127}
+			int8s  = []int8{-128, -3, -1, 0, 1, 127}
+		)
+
+		SortSliceByValue(ints, hash)
+		SortSliceByValue(int64s, hash)
+		SortSliceByValue(int16s, hash)
+		SortSliceByValue(int8s, hash)
+		for i := range ints {
+			if int64(ints[i]) != int64s[i] || int16(ints[i]) != int16s[i] || int8(ints[i]) != int8s[i] {
+				t.Fatalf("Different orders: %v, %v, %v, %v", ints, int64s, int16s, int8s)
+			}
+		}
+	})
+
+	t.Run("extremes", func(t *testing.T) {
+		if hashIntV1(math.MinInt64) == hashIntV1(math.MaxInt64) || hashIntV1(-1) == hashIntV1(1) {
+			t.Errorf("Expected different hashes for different values")
+		}
+	})
+}
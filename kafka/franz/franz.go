@@ -0,0 +1,48 @@
+// Package hrwfranz partitions records of github.com/twmb/franz-go clients
+// by HRW over their keys, see package kafka:
+//
+//	client, err := kgo.NewClient(hrwfranz.RecordPartitioner(), ...)
+//
+// It's separate module, so core module doesn't depend on franz-go.
+package hrwfranz
+
+import (
+	"github.com/im-kulikov/hrw/kafka"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Partitioner is kgo.Partitioner selecting partitions of keyed records
+// by HRW, records without key are spread randomly among writable partitions
+type Partitioner struct{}
+
+var _ kgo.Partitioner = Partitioner{}
+
+// RecordPartitioner returns option making client partition records by Partitioner
+func RecordPartitioner() kgo.Opt {
+	return kgo.RecordPartitioner(Partitioner{})
+}
+
+// ForTopic returns partitioner for topic
+func (Partitioner) ForTopic(topic string) kgo.TopicPartitioner {
+	return topicPartitioner{p: kafka.NewPartitioner(topic)}
+}
+
+type topicPartitioner struct {
+	p *kafka.Partitioner
+}
+
+// RequiresConsistency reports whether record must be placed to the same
+// partition even when it's unavailable, only keyed records must
+func (t topicPartitioner) RequiresConsistency(r *kgo.Record) bool {
+	return r.Key != nil
+}
+
+// Partition returns partition of record among n ones, client calls it
+// only for topics with partitions
+func (t topicPartitioner) Partition(r *kgo.Record, n int) int {
+	p, err := t.p.Partition(r.Key, int32(n))
+	if err != nil {
+		return 0
+	}
+	return int(p)
+}
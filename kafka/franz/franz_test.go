@@ -0,0 +1,36 @@
+package hrwfranz
+
+import (
+	"testing"
+
+	"github.com/im-kulikov/hrw/kafka"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestPartitioner(t *testing.T) {
+	p := Partitioner{}.ForTopic("topic")
+
+	keyed := &kgo.Record{Topic: "topic", Key: []byte("key")}
+	if !p.RequiresConsistency(keyed) {
+		t.Errorf("Expected keyed record to require consistency")
+	}
+
+	if actual, expect := p.Partition(keyed, 7), int(kafka.Partition(keyed.Key, 7)); actual != expect {
+		t.Errorf("Was %d, but expected %d", actual, expect)
+	}
+
+	unkeyed := &kgo.Record{Topic: "topic"}
+	if p.RequiresConsistency(unkeyed) {
+		t.Errorf("Expected record without key not to require consistency")
+	}
+
+	for i := 0; i < 100; i++ {
+		if actual := p.Partition(unkeyed, 3); actual < 0 || actual >= 3 {
+			t.Fatalf("Was %d, but expected partition in [0, 3)", actual)
+		}
+	}
+
+	if RecordPartitioner() == nil {
+		t.Errorf("Expected option")
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/im-kulikov/hrw/kafka/franz
+
+go 1.21
+
+require (
+	github.com/im-kulikov/hrw v0.0.0-00010101000000-000000000000
+	github.com/twmb/franz-go v1.17.1
+)
+
+replace github.com/im-kulikov/hrw => ../..
@@ -0,0 +1,60 @@
+// Package kafka partitions messages by HRW over their keys. Unlike modulo
+// hashing, adding partitions moves only keys of new partitions: going from
+// n to n+1 partitions moves about 1/(n+1) of keys.
+//
+// Package doesn't depend on Kafka clients, adapters are separate modules:
+// github.com/im-kulikov/hrw/kafka/sarama for sarama producers and
+// github.com/im-kulikov/hrw/kafka/franz for franz-go clients.
+package kafka
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/im-kulikov/hrw"
+)
+
+// ErrNoPartitions returned when topic has no partitions
+var ErrNoPartitions = errors.New("kafka: no partitions")
+
+// Partitioner selects partitions of keys by HRW, messages without key
+// are spread randomly. It's safe for concurrent use.
+type Partitioner struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewPartitioner creates Partitioner for topic, it has signature of
+// sarama.PartitionerConstructor except returned type
+func NewPartitioner(topic string) *Partitioner {
+	return &Partitioner{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// Partition returns partition of key among numPartitions, random one
+// for nil key
+func (p *Partitioner) Partition(key []byte, numPartitions int32) (int32, error) {
+	if numPartitions <= 0 {
+		return -1, ErrNoPartitions
+	} else if key != nil {
+		return Partition(key, numPartitions), nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.rand.Int31n(numPartitions), nil
+}
+
+// RequiresConsistency reports that keys must always map to the same
+// partition, like sarama.Partitioner does
+func (p *Partitioner) RequiresConsistency() bool {
+	return true
+}
+
+// Partition returns partition of key among numPartitions,
+// it panics if numPartitions <= 0
+func Partition(key []byte, numPartitions int32) int32 {
+	return int32(hrw.ShardOf(key, int(numPartitions)))
+}
@@ -0,0 +1,52 @@
+package kafka
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestPartition(t *testing.T) {
+	const keys = 10000
+
+	var moved, fresh int
+	for i := 0; i < keys; i++ {
+		key := []byte("key-" + strconv.Itoa(i))
+		before, after := Partition(key, 10), Partition(key, 11)
+		if before != after {
+			moved++
+			if after == 10 {
+				fresh++
+			}
+		}
+	}
+
+	if moved != fresh {
+		t.Errorf("Was %d moved keys, but expected only %d moved to new partition", moved, fresh)
+	}
+	if moved < keys/11/2 || moved > keys/11*2 {
+		t.Errorf("Was %d moved keys, but expected about %d", moved, keys/11)
+	}
+}
+
+func TestPartitioner(t *testing.T) {
+	p := NewPartitioner("topic")
+	if !p.RequiresConsistency() {
+		t.Errorf("Expected partitioner to require consistency")
+	}
+
+	key := []byte("key")
+	if actual, err := p.Partition(key, 7); err != nil || actual != Partition(key, 7) {
+		t.Errorf("Was %d (%v), but expected %d", actual, err, Partition(key, 7))
+	}
+
+	for i := 0; i < 100; i++ {
+		if actual, err := p.Partition(nil, 3); err != nil || actual < 0 || actual >= 3 {
+			t.Fatalf("Was %d (%v), but expected partition in [0, 3)", actual, err)
+		}
+	}
+
+	if _, err := p.Partition(key, 0); !errors.Is(err, ErrNoPartitions) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrNoPartitions)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/im-kulikov/hrw/kafka/sarama
+
+go 1.21
+
+require (
+	github.com/IBM/sarama v1.43.3
+	github.com/im-kulikov/hrw v0.0.0-00010101000000-000000000000
+)
+
+replace github.com/im-kulikov/hrw => ../..
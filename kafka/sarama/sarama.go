@@ -0,0 +1,43 @@
+// Package hrwsarama partitions messages of github.com/IBM/sarama producers
+// by HRW over their keys, see package kafka:
+//
+//	config.Producer.Partitioner = hrwsarama.NewPartitioner
+//
+// It's separate module, so core module doesn't depend on sarama.
+package hrwsarama
+
+import (
+	"github.com/IBM/sarama"
+	"github.com/im-kulikov/hrw/kafka"
+)
+
+// Partitioner is sarama.Partitioner selecting partitions of keyed messages
+// by HRW, messages without key are spread randomly
+type Partitioner struct {
+	p *kafka.Partitioner
+}
+
+var _ sarama.Partitioner = (*Partitioner)(nil)
+
+// NewPartitioner creates Partitioner for topic, it's sarama.PartitionerConstructor
+func NewPartitioner(topic string) sarama.Partitioner {
+	return &Partitioner{p: kafka.NewPartitioner(topic)}
+}
+
+// Partition returns partition of message key among numPartitions,
+// it fails when key can't be encoded
+func (p *Partitioner) Partition(m *sarama.ProducerMessage, numPartitions int32) (int32, error) {
+	var key []byte
+	if m.Key != nil {
+		var err error
+		if key, err = m.Key.Encode(); err != nil {
+			return -1, err
+		}
+	}
+	return p.p.Partition(key, numPartitions)
+}
+
+// RequiresConsistency reports that keys must always map to the same partition
+func (p *Partitioner) RequiresConsistency() bool {
+	return p.p.RequiresConsistency()
+}
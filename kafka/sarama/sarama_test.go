@@ -0,0 +1,40 @@
+package hrwsarama
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/im-kulikov/hrw/kafka"
+)
+
+type badEncoder struct{}
+
+func (badEncoder) Encode() ([]byte, error) { return nil, errors.New("encode") }
+func (badEncoder) Length() int             { return 0 }
+
+func TestPartitioner(t *testing.T) {
+	p := NewPartitioner("topic")
+	if !p.RequiresConsistency() {
+		t.Errorf("Expected partitioner to require consistency")
+	}
+
+	m := &sarama.ProducerMessage{Topic: "topic", Key: sarama.StringEncoder("key")}
+	if actual, err := p.Partition(m, 7); err != nil || actual != kafka.Partition([]byte("key"), 7) {
+		t.Errorf("Was %d (%v), but expected %d", actual, err, kafka.Partition([]byte("key"), 7))
+	}
+
+	for i := 0; i < 100; i++ {
+		if actual, err := p.Partition(&sarama.ProducerMessage{Topic: "topic"}, 3); err != nil || actual < 0 || actual >= 3 {
+			t.Fatalf("Was %d (%v), but expected partition in [0, 3)", actual, err)
+		}
+	}
+
+	if _, err := p.Partition(m, 0); !errors.Is(err, kafka.ErrNoPartitions) {
+		t.Errorf("Was %v, but expected %v", err, kafka.ErrNoPartitions)
+	}
+
+	if _, err := p.Partition(&sarama.ProducerMessage{Key: badEncoder{}}, 3); err == nil {
+		t.Errorf("Expected encoding error")
+	}
+}
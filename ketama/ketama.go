@@ -0,0 +1,177 @@
+// Package ketama implements continuum of libketama and compares it's
+// placement with HRW, so migration from ketama can be justified and staged
+// by expected cache misses.
+package ketama
+
+import (
+	"bufio"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/im-kulikov/hrw"
+)
+
+// pointsPerServer is count of points of average server like in libketama
+const pointsPerServer = 160
+
+type (
+	// Server is a weighted server of continuum
+	Server struct {
+		// Addr is address of server, e.g. "10.0.0.1:11211"
+		Addr string
+		// Weight is relative capacity of server, e.g. memory size
+		Weight int
+	}
+
+	// Continuum places keys on servers like libketama does
+	Continuum struct {
+		points []point
+	}
+
+	point struct {
+		value uint32
+		addr  string
+	}
+
+	// Diff is count of sampled keys per server
+	Diff struct {
+		// Ketama is count of keys ketama places on server
+		Ketama int
+		// HRW is count of keys HRW places on server
+		HRW int
+		// Kept is count of keys both place on server
+		Kept int
+	}
+
+	// Report compares placement of sampled keys by ketama and HRW
+	Report struct {
+		// Keys is count of sampled keys
+		Keys int
+		// Moved is count of keys placed on different servers
+		Moved int
+		// MissRate is expected share of cache misses right after cutover
+		// from warm ketama servers to HRW, it's Moved / Keys
+		MissRate float64
+		// Servers holds counts of keys of every server
+		Servers map[string]Diff
+	}
+)
+
+// New creates Continuum of servers
+func New(servers []Server) *Continuum {
+	var total int
+	for _, s := range servers {
+		total += s.Weight
+	}
+
+	c := new(Continuum)
+	for _, s := range servers {
+		share := float64(s.Weight) / float64(total)
+		count := int(math.Floor(share * pointsPerServer / 4 * float64(len(servers))))
+		for k := 0; k < count; k++ {
+			digest := md5.Sum([]byte(s.Addr + "-" + strconv.Itoa(k)))
+			for h := 0; h < 4; h++ {
+				c.points = append(c.points, point{value: littleEndian(digest[h*4:]), addr: s.Addr})
+			}
+		}
+	}
+
+	sort.SliceStable(c.points, func(i, j int) bool { return c.points[i].value < c.points[j].value })
+	return c
+}
+
+// Get returns address of server for key, empty for empty Continuum
+func (c *Continuum) Get(key []byte) string {
+	if len(c.points) == 0 {
+		return ""
+	}
+
+	digest := md5.Sum(key)
+	h := littleEndian(digest[:])
+	i := sort.Search(len(c.points), func(i int) bool { return c.points[i].value >= h })
+	if i == len(c.points) {
+		i = 0
+	}
+	return c.points[i].addr
+}
+
+// Compare places keys by ketama and by HRW Ring of the same servers
+// weighted by Weight and reports differences
+func Compare(servers []Server, keys [][]byte) Report {
+	var (
+		c     = New(servers)
+		ring  = hrw.NewRing()
+		nodes = make([]hrw.Node, 0, len(servers))
+		rep   = Report{Keys: len(keys), Servers: make(map[string]Diff, len(servers))}
+	)
+
+	for _, s := range servers {
+		nodes = append(nodes, hrw.Node{ID: s.Addr, Weight: float64(s.Weight)})
+		rep.Servers[s.Addr] = Diff{}
+	}
+	ring.Add(nodes...)
+
+	for _, key := range keys {
+		was := c.Get(key)
+		n, _ := ring.Get(key)
+
+		d := rep.Servers[was]
+		d.Ketama++
+		if n.ID == was {
+			d.Kept++
+		} else {
+			rep.Moved++
+		}
+		rep.Servers[was] = d
+
+		d = rep.Servers[n.ID]
+		d.HRW++
+		rep.Servers[n.ID] = d
+	}
+
+	if rep.Keys > 0 {
+		rep.MissRate = float64(rep.Moved) / float64(rep.Keys)
+	}
+	return rep
+}
+
+// ParseServers reads servers in libketama format: address and weight
+// separated by spaces per line. Empty lines and lines starting with '#'
+// are skipped.
+func ParseServers(r io.Reader) ([]Server, error) {
+	var (
+		result []Server
+		line   int
+		sc     = bufio.NewScanner(r)
+	)
+
+	for sc.Scan() {
+		line++
+
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("ketama: line %d: expected address and weight", line)
+		}
+
+		weight, err := strconv.Atoi(fields[1])
+		if err != nil || weight <= 0 {
+			return nil, fmt.Errorf("ketama: line %d: invalid weight %q", line, fields[1])
+		}
+		result = append(result, Server{Addr: fields[0], Weight: weight})
+	}
+	return result, sc.Err()
+}
+
+func littleEndian(b []byte) uint32 {
+	return uint32(b[3])<<24 | uint32(b[2])<<16 | uint32(b[1])<<8 | uint32(b[0])
+}
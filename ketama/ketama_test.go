@@ -0,0 +1,78 @@
+package ketama
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testServers() []Server {
+	return []Server{
+		{Addr: "10.0.0.1:11211", Weight: 100},
+		{Addr: "10.0.0.2:11211", Weight: 100},
+		{Addr: "10.0.0.3:11211", Weight: 200},
+	}
+}
+
+func testKeys(n int) [][]byte {
+	keys := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		keys = append(keys, []byte("key-"+strconv.Itoa(i)))
+	}
+	return keys
+}
+
+func TestContinuum(t *testing.T) {
+	c := New(testServers())
+	if len(c.points) != 480 {
+		t.Errorf("Was %d, but expected %d", len(c.points), 480)
+	}
+
+	counts := make(map[string]int)
+	for _, key := range testKeys(10000) {
+		counts[c.Get(key)]++
+	}
+
+	if share := float64(counts["10.0.0.3:11211"]) / 10000; math.Abs(share-0.5) > 0.1 {
+		t.Errorf("Was %v, but expected about %v", share, 0.5)
+	}
+
+	if addr := New(nil).Get([]byte("key")); addr != "" {
+		t.Errorf("Was %q, but expected empty", addr)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	rep := Compare(testServers(), testKeys(10000))
+	if rep.Keys != 10000 || rep.Moved == 0 || rep.Moved == rep.Keys {
+		t.Errorf("Was %d of %d moved, but expected some keys to move", rep.Moved, rep.Keys)
+	}
+
+	var ketama, hrw, kept int
+	for _, d := range rep.Servers {
+		ketama, hrw, kept = ketama+d.Ketama, hrw+d.HRW, kept+d.Kept
+	}
+
+	if ketama != rep.Keys || hrw != rep.Keys || kept != rep.Keys-rep.Moved {
+		t.Errorf("Was %d, %d, %d, but expected %d, %d, %d",
+			ketama, hrw, kept, rep.Keys, rep.Keys, rep.Keys-rep.Moved)
+	}
+
+	if expect := float64(rep.Moved) / float64(rep.Keys); rep.MissRate != expect {
+		t.Errorf("Was %v, but expected %v", rep.MissRate, expect)
+	}
+}
+
+func TestParseServers(t *testing.T) {
+	servers, err := ParseServers(strings.NewReader("# comment\n10.0.0.1:11211 100\n\n10.0.0.2:11211\t200\n"))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(servers) != 2 || servers[1].Weight != 200 {
+		t.Errorf("Was %#v, but expected 2 servers", servers)
+	}
+
+	if _, err := ParseServers(strings.NewReader("10.0.0.1:11211 x\n")); err == nil {
+		t.Errorf("Expected error for invalid weight")
+	}
+}
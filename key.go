@@ -0,0 +1,109 @@
+package hrw
+
+import "encoding/binary"
+
+// Key builds canonical encoding of composite key from multiple fields.
+// Every field is encoded with it's type and length, so encoding is
+// injective: different sequences of fields never produce equal keys.
+// Key implements Hasher, so slice of keys can be used by SortSliceByValue.
+type Key struct {
+	buf []byte
+}
+
+const (
+	keyBytes byte = iota + 1
+	keyString
+	keyUint
+	keyInt
+	keyBool
+)
+
+// NewKey creates empty Key
+func NewKey() *Key {
+	return &Key{buf: make([]byte, 0, 64)}
+}
+
+// AddBytes appends byte slice field
+func (k *Key) AddBytes(v []byte) *Key {
+	k.buf = append(k.buf, keyBytes)
+	k.buf = appendUvarint(k.buf, uint64(len(v)))
+	k.buf = append(k.buf, v...)
+	return k
+}
+
+// AddString appends string field
+func (k *Key) AddString(v string) *Key {
+	k.buf = append(k.buf, keyString)
+	k.buf = appendUvarint(k.buf, uint64(len(v)))
+	k.buf = append(k.buf, v...)
+	return k
+}
+
+// AddUint64 appends unsigned integer field
+func (k *Key) AddUint64(v uint64) *Key {
+	var tmp [8]byte
+	binary.BigEndian.PutUint64(tmp[:], v)
+	k.buf = append(k.buf, keyUint)
+	k.buf = append(k.buf, tmp[:]...)
+	return k
+}
+
+// AddInt64 appends integer field
+func (k *Key) AddInt64(v int64) *Key {
+	var tmp [8]byte
+	binary.BigEndian.PutUint64(tmp[:], uint64(v))
+	k.buf = append(k.buf, keyInt)
+	k.buf = append(k.buf, tmp[:]...)
+	return k
+}
+
+// AddBool appends boolean field
+func (k *Key) AddBool(v bool) *Key {
+	var b byte
+	if v {
+		b = 1
+	}
+	k.buf = append(k.buf, keyBool, b)
+	return k
+}
+
+// Bytes returns encoded key, it's valid until next change of Key
+func (k *Key) Bytes() []byte {
+	return k.buf
+}
+
+// Hash returns Hash of encoded key
+func (k *Key) Hash() uint64 {
+	return Hash(k.buf)
+}
+
+// Reset removes all fields, so Key can be reused
+func (k *Key) Reset() {
+	k.buf = k.buf[:0]
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	return append(buf, tmp[:binary.PutUvarint(tmp[:], v)]...)
+}
+
+// KeyOf returns Key of struct (or pointer to struct) built from fields
+// tagged `hrw:"key"` in declared order. Supported fields are strings,
+// byte slices and arrays, integers and booleans. Without reflection
+// (TinyGo or hrw_noreflect build tag) it always returns ErrNotStruct.
+func KeyOf(v interface{}) (*Key, error) {
+	k := NewKey()
+	if err := structKey(v, k); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// HashOf returns hash of Key of struct, see KeyOf
+func HashOf(v interface{}) (uint64, error) {
+	k, err := KeyOf(v)
+	if err != nil {
+		return 0, err
+	}
+	return k.Hash(), nil
+}
@@ -0,0 +1,112 @@
+package hrw
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	t.Run("injective", func(t *testing.T) {
+		cases := [][2]*Key{
+			{NewKey().AddString("ab").AddString("c"), NewKey().AddString("a").AddString("bc")},
+			{NewKey().AddString("a"), NewKey().AddBytes([]byte("a"))},
+			{NewKey().AddUint64(1), NewKey().AddInt64(1)},
+			{NewKey().AddString(""), NewKey()},
+			{NewKey().AddString("tenant-1").AddString("bucket"), NewKey().AddString("tenant-1bucket")},
+		}
+
+		for _, tc := range cases {
+			if bytes.Equal(tc[0].Bytes(), tc[1].Bytes()) {
+				t.Errorf("Expected different encodings, got %x", tc[0].Bytes())
+			}
+		}
+	})
+
+	t.Run("canonical", func(t *testing.T) {
+		actual := NewKey().AddString("ns").AddBytes([]byte{1}).AddUint64(2).AddInt64(-1).Bytes()
+		expect := []byte{
+			keyString, 2, 'n', 's',
+			keyBytes, 1, 1,
+			keyUint, 0, 0, 0, 0, 0, 0, 0, 2,
+			keyInt, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		}
+		if !bytes.Equal(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		k := NewKey().AddString("ns").AddUint64(7)
+		if actual, expect := k.Hash(), Hash(k.Bytes()); actual != expect {
+			t.Errorf("Was %d, but expected %d", actual, expect)
+		}
+
+		k.Reset()
+		if actual := len(k.Bytes()); actual != 0 {
+			t.Errorf("Was %d, but expected %d", actual, 0)
+		}
+	})
+
+	t.Run("sort slice by value", func(t *testing.T) {
+		requireReflect(t)
+
+		keys := []*Key{NewKey().AddString("a"), NewKey().AddString("b"), NewKey().AddString("c")}
+		if err := TrySortSliceByValue(keys, Hash(testKey)); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
+
+func TestKeyOf(t *testing.T) {
+	requireReflect(t)
+
+	type object struct {
+		Namespace string `hrw:"key"`
+		Bucket    string `hrw:"key"`
+		Size      int
+		ID        [4]byte `hrw:"key"`
+		Version   uint32  `hrw:"key"`
+		Deleted   bool    `hrw:"key"`
+		Offset    int8    `hrw:"key"`
+	}
+
+	v := object{Namespace: "ns", Bucket: "b", Size: 10, ID: [4]byte{1, 2, 3, 4}, Version: 7, Deleted: true, Offset: -1}
+	expect := NewKey().
+		AddString("ns").
+		AddString("b").
+		AddBytes([]byte{1, 2, 3, 4}).
+		AddUint64(7).
+		AddBool(true).
+		AddInt64(-1)
+
+	for _, value := range []interface{}{v, &v} {
+		actual, err := KeyOf(value)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if !bytes.Equal(actual.Bytes(), expect.Bytes()) {
+			t.Errorf("Was %#v, but expected %#v", actual.Bytes(), expect.Bytes())
+		}
+	}
+
+	if actual, _ := HashOf(v); actual != expect.Hash() {
+		t.Errorf("Was %d, but expected %d", actual, expect.Hash())
+	}
+
+	t.Run("not struct", func(t *testing.T) {
+		if _, err := KeyOf("string"); !errors.Is(err, ErrNotStruct) {
+			t.Errorf("Was %v, but expected %v", err, ErrNotStruct)
+		}
+	})
+
+	t.Run("unsupported field", func(t *testing.T) {
+		v := struct {
+			Tags []string `hrw:"key"`
+		}{}
+		if _, err := HashOf(v); !errors.Is(err, ErrUnsupportedField) {
+			t.Errorf("Was %v, but expected %v", err, ErrUnsupportedField)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package hrw
+
+import "fmt"
+
+// Transition is a change of node state made by Ring.Transition
+type Transition struct {
+	ID       string
+	From, To State
+}
+
+// transitions are allowed changes of node lifecycle states
+var transitions = map[State][]State{
+	StateJoining:  {StateActive, StateDown},
+	StateActive:   {StateDraining, StateGray, StateDown},
+	StateGray:     {StateActive, StateDraining, StateDown},
+	StateDraining: {StateActive, StateDown},
+	StateDown:     {StateJoining, StateActive},
+}
+
+// String returns name of state
+func (st State) String() string {
+	switch st {
+	case StateActive:
+		return "active"
+	case StateDown:
+		return "down"
+	case StateGray:
+		return "gray"
+	case StateJoining:
+		return "joining"
+	case StateDraining:
+		return "draining"
+	default:
+		return fmt.Sprintf("State(%d)", uint8(st))
+	}
+}
+
+// CanTransition reports whether node lifecycle allows change of state
+// from one to another: joining nodes become active, active ones are
+// drained and drained ones go down, down nodes join again. Any node may
+// go down and any selectable node may be activated again.
+func CanTransition(from, to State) bool {
+	for _, st := range transitions[from] {
+		if st == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition changes state of node following lifecycle, see CanTransition,
+// hooks registered by OnTransition are called after change is published
+func (r *Ring) Transition(id string, to State) error {
+	var (
+		err error
+		t   = Transition{ID: id, To: to}
+	)
+
+	r.update(func(s *Snapshot) bool {
+		i, ok := findMember(s.nodes, id)
+		if !ok {
+			err = fmt.Errorf("%w: %q", ErrUnknownNode, id)
+			return false
+		}
+
+		t.From = s.nodes[i].State
+		if !CanTransition(t.From, to) {
+			err = fmt.Errorf("%w: %s to %s of %q", ErrTransition, t.From, to, id)
+			return false
+		}
+
+		s.nodes[i].State = to
+		return true
+	})
+
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	hooks := r.hooks
+	r.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn(t)
+	}
+	return nil
+}
+
+// OnTransition registers hook called for every change made by Transition
+func (r *Ring) OnTransition(fn func(Transition)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.hooks = append(r.hooks[:len(r.hooks):len(r.hooks)], fn)
+}
+
+// selectable reports whether nodes in state are selected
+func (st State) selectable() bool {
+	return st == StateActive || st == StateGray || st == StateDraining
+}
+
+// last reports whether nodes in state follow all active ones
+func (st State) last() bool {
+	return st == StateGray || st == StateDraining
+}
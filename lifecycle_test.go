@@ -0,0 +1,56 @@
+package hrw
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTransition(t *testing.T) {
+	r := NewRing(Node{ID: "a", State: StateJoining}, Node{ID: "b"}, Node{ID: "c"})
+
+	var log []Transition
+	r.OnTransition(func(t Transition) { log = append(log, t) })
+
+	if nodes := nodeIDs(r.GetN(testKey, 3)); len(nodes) != 2 {
+		t.Errorf("Was %#v, but expected joining node not to be selected", nodes)
+	}
+
+	if err := r.Transition("a", StateActive); err != nil {
+		t.Fatal(err)
+	}
+
+	order := nodeIDs(r.GetN(testKey, 3))
+	if err := r.Transition(order[0], StateDraining); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := append(append([]string(nil), order[1:]...), order[0])
+	if actual := nodeIDs(r.GetN(testKey, 3)); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected draining node last %#v", actual, expect)
+	}
+
+	if err := r.Transition(order[0], StateJoining); !errors.Is(err, ErrTransition) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrTransition)
+	}
+
+	if err := r.Transition("unknown", StateDown); !errors.Is(err, ErrUnknownNode) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrUnknownNode)
+	}
+
+	hooks := []Transition{
+		{ID: "a", From: StateJoining, To: StateActive},
+		{ID: order[0], From: StateActive, To: StateDraining},
+	}
+	if !reflect.DeepEqual(log, hooks) {
+		t.Errorf("Was %#v, but expected %#v", log, hooks)
+	}
+}
+
+func TestStateString(t *testing.T) {
+	for st, expect := range map[State]string{StateDraining: "draining", State(42): "State(42)"} {
+		if actual := st.String(); actual != expect {
+			t.Errorf("Was %q, but expected %q", actual, expect)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package hrw
+
+type (
+	// LoadReporter reports current load of node, e.g. count of connections
+	// or queue depth, load-aware selection prefers nodes with lower load
+	LoadReporter interface {
+		Load(n Node) float64
+	}
+
+	// LoadFunc is an adapter to use function as LoadReporter
+	LoadFunc func(n Node) float64
+)
+
+// Load calls fn(n)
+func (fn LoadFunc) Load(n Node) float64 {
+	return fn(n)
+}
+
+// SetLoadReporter sets LoadReporter consulted by load-aware selection,
+// without it all nodes are treated as equally loaded
+func (r *Ring) SetLoadReporter(lr LoadReporter) {
+	r.update(func(s *Snapshot) bool {
+		s.load = lr
+		return false
+	})
+}
+
+// GetLeastLoaded takes two most preferable active nodes for key and returns
+// the less loaded one, ties are resolved in favor of the first node.
+// It keeps affinity of keys to nodes and shaves hotspots.
+func (r *Ring) GetLeastLoaded(key []byte, loadOf func(Node) float64) (Node, bool) {
+	return r.view().GetLeastLoaded(key, loadOf)
+}
+
+// GetLeastOfTopK takes k most preferable active nodes for key and returns
+// the least loaded one reported by LoadReporter, ties are resolved
+// in favor of more preferable node
+func (r *Ring) GetLeastOfTopK(key []byte, k int) (Node, bool) {
+	return r.view().GetLeastOfTopK(key, k)
+}
+
+// GetBoundedLoad returns most preferable active node for key which load
+// per unit of weight doesn't exceed factor times average one,
+// factor should be >= 1, otherwise most preferable node is returned
+// when all nodes are overloaded
+func (r *Ring) GetBoundedLoad(key []byte, factor float64) (Node, bool) {
+	return r.view().GetBoundedLoad(key, factor)
+}
+
+// GetLeastLoaded is like Ring.GetLeastLoaded
+func (s *Snapshot) GetLeastLoaded(key []byte, loadOf func(Node) float64) (Node, bool) {
+	return leastLoaded(s.GetN(key, 2), LoadFunc(loadOf))
+}
+
+// GetLeastOfTopK is like Ring.GetLeastOfTopK
+func (s *Snapshot) GetLeastOfTopK(key []byte, k int) (Node, bool) {
+	return leastLoaded(s.GetN(key, k), s.load)
+}
+
+// GetBoundedLoad is like Ring.GetBoundedLoad
+func (s *Snapshot) GetBoundedLoad(key []byte, factor float64) (Node, bool) {
+	nodes := s.GetN(key, len(s.nodes))
+	if s.load == nil || len(nodes) == 0 {
+		return first(nodes)
+	}
+
+	var (
+		loads       = make([]float64, len(nodes))
+		total, sumW float64
+	)
+
+	for i := range nodes {
+		loads[i] = s.load.Load(nodes[i])
+		total += loads[i]
+		sumW += s.cost.weight(nodes[i])
+	}
+
+	bound := factor * total / sumW
+	for i := range nodes {
+		if loads[i]/s.cost.weight(nodes[i]) <= bound {
+			return nodes[i], true
+		}
+	}
+	return first(nodes)
+}
+
+func leastLoaded(nodes []Node, lr LoadReporter) (Node, bool) {
+	if len(nodes) == 0 || lr == nil {
+		return first(nodes)
+	}
+
+	best, bestLoad := 0, lr.Load(nodes[0])
+	for i := 1; i < len(nodes); i++ {
+		if l := lr.Load(nodes[i]); l < bestLoad {
+			best, bestLoad = i, l
+		}
+	}
+	return nodes[best], true
+}
@@ -0,0 +1,98 @@
+package hrw
+
+import "testing"
+
+func TestRingGetLeastLoaded(t *testing.T) {
+	r := NewRing(testNodes(5)...)
+	top := r.GetN(testKey, 2)
+
+	t.Run("first is less loaded", func(t *testing.T) {
+		actual, _ := r.GetLeastLoaded(testKey, func(n Node) float64 {
+			if n.ID == top[0].ID {
+				return 1
+			}
+			return 2
+		})
+		if actual.ID != top[0].ID {
+			t.Errorf("Was %#v, but expected %#v", actual.ID, top[0].ID)
+		}
+	})
+
+	t.Run("second is less loaded", func(t *testing.T) {
+		actual, _ := r.GetLeastLoaded(testKey, func(n Node) float64 {
+			if n.ID == top[0].ID {
+				return 2
+			}
+			return 1
+		})
+		if actual.ID != top[1].ID {
+			t.Errorf("Was %#v, but expected %#v", actual.ID, top[1].ID)
+		}
+	})
+
+	t.Run("equal load", func(t *testing.T) {
+		actual, _ := r.GetLeastLoaded(testKey, func(Node) float64 { return 0 })
+		if actual.ID != top[0].ID {
+			t.Errorf("Was %#v, but expected %#v", actual.ID, top[0].ID)
+		}
+	})
+
+	t.Run("single node", func(t *testing.T) {
+		actual, ok := NewRing(Node{ID: "a"}).GetLeastLoaded(testKey, func(Node) float64 { return 0 })
+		if !ok || actual.ID != "a" {
+			t.Errorf("Was %#v, but expected %#v", actual.ID, "a")
+		}
+	})
+
+	t.Run("empty ring", func(t *testing.T) {
+		if _, ok := NewRing().GetLeastLoaded(testKey, func(Node) float64 { return 0 }); ok {
+			t.Errorf("Expected no node for empty ring")
+		}
+	})
+}
+
+func TestRingGetLeastOfTopK(t *testing.T) {
+	r := NewRing(testNodes(5)...)
+	top := r.GetN(testKey, 5)
+
+	if actual, _ := r.GetLeastOfTopK(testKey, 3); actual.ID != top[0].ID {
+		t.Errorf("Was %#v, but expected %#v", actual.ID, top[0].ID)
+	}
+
+	loads := map[string]float64{top[0].ID: 3, top[1].ID: 2, top[2].ID: 1}
+	r.SetLoadReporter(LoadFunc(func(n Node) float64 { return loads[n.ID] }))
+
+	if actual, _ := r.GetLeastOfTopK(testKey, 3); actual.ID != top[2].ID {
+		t.Errorf("Was %#v, but expected %#v", actual.ID, top[2].ID)
+	}
+
+	// nodes outside of top-k are not considered
+	if actual, _ := r.GetLeastOfTopK(testKey, 2); actual.ID != top[1].ID {
+		t.Errorf("Was %#v, but expected %#v", actual.ID, top[1].ID)
+	}
+}
+
+func TestRingGetBoundedLoad(t *testing.T) {
+	r := NewRing(testNodes(4)...)
+	top := r.GetN(testKey, 4)
+
+	if actual, _ := r.GetBoundedLoad(testKey, 1.25); actual.ID != top[0].ID {
+		t.Errorf("Was %#v, but expected %#v", actual.ID, top[0].ID)
+	}
+
+	// average load is 4, bound is 5
+	loads := map[string]float64{top[0].ID: 8, top[1].ID: 6, top[2].ID: 2, top[3].ID: 0}
+	r.SetLoadReporter(LoadFunc(func(n Node) float64 { return loads[n.ID] }))
+
+	if actual, _ := r.GetBoundedLoad(testKey, 1.25); actual.ID != top[2].ID {
+		t.Errorf("Was %#v, but expected %#v", actual.ID, top[2].ID)
+	}
+
+	if actual, _ := r.GetBoundedLoad(testKey, 2); actual.ID != top[0].ID {
+		t.Errorf("Was %#v, but expected %#v", actual.ID, top[0].ID)
+	}
+
+	if _, ok := NewRing().GetBoundedLoad(testKey, 1.25); ok {
+		t.Errorf("Expected no node for empty ring")
+	}
+}
@@ -0,0 +1,89 @@
+package hrw
+
+import "sort"
+
+// GetFromMap returns the most preferable for key entry of map with string
+// keys (map[string]T), ok is false for empty and unsupported maps (or panic
+// in strict mode). Result doesn't depend on map iteration order and is the
+// same as first element of SortSliceByValue applied to keys of map.
+func GetFromMap(m interface{}, key []byte) (string, interface{}, bool) {
+	var (
+		found bool
+		best  string
+		value func() interface{}
+		least uint64
+		hash  = Hash(key)
+	)
+
+	err := mapRange(m, func(k string, v func() interface{}) {
+		w := valueWeight(Hash([]byte(k)), hash)
+		if !found || w < least || (w == least && k < best) {
+			found, best, value, least = true, k, v, w
+		}
+	})
+
+	checkStrict(err)
+	if !found {
+		return "", nil, false
+	}
+	return best, value(), true
+}
+
+// RankMap returns up to n keys of map with string keys in order of
+// preference for key, n < 0 means all keys. Unsupported maps return nil
+// (or panic in strict mode).
+func RankMap(m interface{}, key []byte, n int) []string {
+	var (
+		hash    = Hash(key)
+		keys    []string
+		weights = make(map[string]uint64)
+	)
+
+	checkStrict(mapRange(m, func(k string, _ func() interface{}) {
+		keys = append(keys, k)
+		weights[k] = valueWeight(Hash([]byte(k)), hash)
+	}))
+
+	sort.Slice(keys, func(i, j int) bool {
+		wi, wj := weights[keys[i]], weights[keys[j]]
+		return wi < wj || (wi == wj && keys[i] < keys[j])
+	})
+
+	if n >= 0 && n < len(keys) {
+		keys = keys[:n]
+	}
+	return keys
+}
+
+// knownMapRange iterates over maps of known types without reflection
+func knownMapRange(m interface{}, fn func(key string, value func() interface{})) bool {
+	switch m := m.(type) {
+	case map[string]string:
+		for k, v := range m {
+			v := v
+			fn(k, func() interface{} { return v })
+		}
+	case map[string]interface{}:
+		for k, v := range m {
+			v := v
+			fn(k, func() interface{} { return v })
+		}
+	case map[string]Hasher:
+		for k, v := range m {
+			v := v
+			fn(k, func() interface{} { return v })
+		}
+	case map[string]struct{}:
+		for k := range m {
+			fn(k, func() interface{} { return struct{}{} })
+		}
+	case map[string]bool:
+		for k, v := range m {
+			v := v
+			fn(k, func() interface{} { return v })
+		}
+	default:
+		return false
+	}
+	return true
+}
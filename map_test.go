@@ -0,0 +1,95 @@
+package hrw
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type mapNode struct{ addr string }
+
+func TestGetFromMap(t *testing.T) {
+	var (
+		keys   = []string{"a", "b", "c", "d", "e", "f"}
+		expect = append([]string{}, keys...)
+		nodes  = make(map[string]*mapNode)
+	)
+
+	SortSliceByValue(expect, Hash(testKey))
+	for _, k := range keys {
+		nodes[k] = &mapNode{addr: k + ":8080"}
+	}
+
+	t.Run("any map", func(t *testing.T) {
+		requireReflect(t)
+
+		for i := 0; i < 10; i++ {
+			k, v, ok := GetFromMap(nodes, testKey)
+			if !ok || k != expect[0] || v.(*mapNode) != nodes[k] {
+				t.Fatalf("Was %q (%v), but expected %q", k, v, expect[0])
+			}
+		}
+	})
+
+	t.Run("known map", func(t *testing.T) {
+		m := map[string]string{"a": "1", "b": "2", "c": "3", "d": "4", "e": "5", "f": "6"}
+		if k, v, ok := GetFromMap(m, testKey); !ok || k != expect[0] || v != m[k] {
+			t.Errorf("Was %q (%v), but expected %q", k, v, expect[0])
+		}
+	})
+
+	t.Run("empty map", func(t *testing.T) {
+		if _, _, ok := GetFromMap(map[string]int{}, testKey); ok {
+			t.Errorf("Expected nothing for empty map")
+		}
+	})
+
+	t.Run("not a map", func(t *testing.T) {
+		if _, _, ok := GetFromMap(map[int]int{1: 1}, testKey); ok {
+			t.Errorf("Expected nothing for map with int keys")
+		}
+
+		SetStrict(true)
+		defer SetStrict(false)
+		defer func() {
+			if err, ok := recover().(error); !ok || !errors.Is(err, ErrNotMap) {
+				t.Errorf("Expected panic with ErrNotMap, got %v", err)
+			}
+		}()
+		GetFromMap([]string{"a"}, testKey)
+	})
+}
+
+func TestRankMap(t *testing.T) {
+	requireReflect(t)
+
+	var (
+		keys   = []string{"a", "b", "c", "d", "e", "f"}
+		expect = append([]string{}, keys...)
+		nodes  = make(map[string]int)
+	)
+
+	SortSliceByValue(expect, Hash(testKey))
+	for i, k := range keys {
+		nodes[k] = i
+	}
+
+	if actual := RankMap(nodes, testKey, -1); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	if actual := RankMap(nodes, testKey, 2); !reflect.DeepEqual(actual, expect[:2]) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect[:2])
+	}
+
+	if actual := RankMap(map[string]struct{}{}, testKey, 2); len(actual) != 0 {
+		t.Errorf("Was %#v, but expected empty result", actual)
+	}
+
+	all := RankMap(nodes, testKey, 10)
+	sort.Strings(all)
+	if !reflect.DeepEqual(all, keys) {
+		t.Errorf("Was %#v, but expected %#v", all, keys)
+	}
+}
@@ -0,0 +1,34 @@
+package hrw
+
+// Namespace is a view of Ring where placements are salted by namespace name,
+// so hot keys of different tenants are spread over different nodes.
+// Namespace reflects all membership changes of its Ring.
+type Namespace struct {
+	ring *Ring
+	name string
+}
+
+// Namespace returns view of Ring for given namespace
+func (r *Ring) Namespace(name string) Namespace {
+	return Namespace{ring: r, name: name}
+}
+
+// Name returns name of namespace
+func (ns Namespace) Name() string { return ns.name }
+
+// Hash returns hash of key inside namespace
+func (ns Namespace) Hash(key []byte) uint64 {
+	s := ns.ring.view()
+	return s.saltKey(s.Hash(key), []byte(ns.name))
+}
+
+// Get returns most preferable active node for key inside namespace
+func (ns Namespace) Get(key []byte) (Node, bool) {
+	return first(ns.GetN(key, 1))
+}
+
+// GetN returns up to n active nodes for key inside namespace
+func (ns Namespace) GetN(key []byte, n int) []Node {
+	s := ns.ring.view()
+	return s.pick(s.saltKey(s.Hash(key), []byte(ns.name)), n)
+}
@@ -0,0 +1,48 @@
+package hrw
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestRingNamespace(t *testing.T) {
+	const keys = 1000
+	var (
+		same int
+		r    = NewRing(testNodes(10)...)
+		a    = r.Namespace("tenant-1")
+		b    = r.Namespace("tenant-2")
+		key  = make([]byte, 8)
+	)
+
+	if a.Name() != "tenant-1" {
+		t.Errorf("Was %q, but expected %q", a.Name(), "tenant-1")
+	}
+
+	for i := uint64(0); i < keys; i++ {
+		binary.BigEndian.PutUint64(key, i)
+
+		x, _ := a.Get(key)
+		y, _ := b.Get(key)
+		if x.ID == y.ID {
+			same++
+		}
+
+		if z, _ := r.Namespace("tenant-1").Get(key); z.ID != x.ID {
+			t.Fatalf("Was %q, but expected %q", z.ID, x.ID)
+		}
+	}
+
+	// with 10 nodes about 10% of keys must be placed on the same node
+	if same > keys*2/10 {
+		t.Errorf("%d of %d keys placed on the same node for different tenants", same, keys)
+	}
+
+	t.Run("follows ring membership", func(t *testing.T) {
+		n, _ := a.Get(testKey)
+		r.Remove(n.ID)
+		if m, _ := a.Get(testKey); m.ID == n.ID {
+			t.Errorf("Removed node %q was selected", n.ID)
+		}
+	})
+}
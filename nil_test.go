@@ -0,0 +1,104 @@
+package hrw
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type hashNode struct{ id string }
+
+func (n *hashNode) Hash() uint64 { return Hash([]byte(n.id)) }
+
+func TestSortSliceByValueNil(t *testing.T) {
+	var (
+		hash = Hash(testKey)
+		a, b = &hashNode{id: "a"}, &hashNode{id: "b"}
+		c, d = &hashNode{id: "c"}, &hashNode{id: "d"}
+	)
+
+	ids := func(nodes []*hashNode) []string {
+		result := make([]string, 0, len(nodes))
+		for _, n := range nodes {
+			if n == nil {
+				result = append(result, "<nil>")
+				continue
+			}
+			result = append(result, n.id)
+		}
+		return result
+	}
+
+	t.Run("without nil", func(t *testing.T) {
+		requireReflect(t)
+
+		expect := []hashString{"a", "b", "c", "d"}
+		SortSliceByValue(expect, hash)
+
+		actual := []*hashNode{a, b, c, d}
+		if err := TrySortSliceByValue(actual, hash); err != nil {
+			t.Fatal(err)
+		}
+		for i := range expect {
+			if actual[i].id != string(expect[i]) {
+				t.Fatalf("Was %#v, but expected %#v", ids(actual), expect)
+			}
+		}
+	})
+
+	for _, alg := range []Algorithm{V1, NSPCC, V2} {
+		t.Run(alg.String(), func(t *testing.T) {
+			requireReflect(t)
+
+			full := []*hashNode{b, c, d}
+			alg.SortSliceByValue(full, hash)
+			expect := append(ids(full), "<nil>", "<nil>", "<nil>")
+
+			actual := []*hashNode{nil, b, nil, c, d, nil}
+			err := alg.TrySortSliceByValue(actual, hash)
+			if !reflect.DeepEqual(ids(actual), expect) {
+				t.Errorf("Was %#v, but expected %#v", ids(actual), expect)
+			}
+
+			var nilErr *NilElementsError
+			if !errors.As(err, &nilErr) || !errors.Is(err, ErrNilElement) {
+				t.Fatalf("Expected NilElementsError, got %v", err)
+			}
+			if expect := []int{0, 2, 5}; !reflect.DeepEqual(nilErr.Indexes, expect) {
+				t.Errorf("Was %#v, but expected %#v", nilErr.Indexes, expect)
+			}
+		})
+	}
+
+	t.Run("nil interfaces", func(t *testing.T) {
+		actual := []Hasher{nil, hashString("b"), hashString("a")}
+		SortSliceByValue(actual, hash)
+		if actual[2] != nil {
+			t.Errorf("Expected nil element to be placed last, got %#v", actual)
+		}
+	})
+
+	t.Run("all nil", func(t *testing.T) {
+		requireReflect(t)
+
+		actual := []*hashNode{nil, nil}
+		if err := TrySortSliceByValue(actual, hash); !errors.Is(err, ErrNilElement) {
+			t.Errorf("Was %v, but expected %v", err, ErrNilElement)
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		requireReflect(t)
+
+		SetStrict(true)
+		defer SetStrict(false)
+
+		// nil elements are sorted last and don't panic in strict mode
+		actual := []*hashNode{nil, a}
+		SortSliceByValue(actual, hash)
+
+		if expect := []string{"a", "<nil>"}; !reflect.DeepEqual(ids(actual), expect) {
+			t.Errorf("Was %#v, but expected %#v", ids(actual), expect)
+		}
+	})
+}
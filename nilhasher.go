@@ -0,0 +1,14 @@
+//go:build !tinygo
+// +build !tinygo
+
+package hrw
+
+import "unsafe"
+
+// isNilHasher reports whether h is nil or holds nil pointer, map, chan
+// or func. Values of these types are stored in data word of interface,
+// values of other types are referenced by it, so it's never nil for them.
+// Nil slices aren't detected.
+func isNilHasher(h Hasher) bool {
+	return h == nil || (*[2]unsafe.Pointer)(unsafe.Pointer(&h))[1] == nil
+}
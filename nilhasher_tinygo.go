@@ -0,0 +1,10 @@
+//go:build tinygo
+// +build tinygo
+
+package hrw
+
+// isNilHasher reports whether h is nil. TinyGo stores small values in data
+// word of interface, so typed nil pointers can't be told from zero values.
+func isNilHasher(h Hasher) bool {
+	return h == nil
+}
@@ -0,0 +1,32 @@
+//go:build tinygo || hrw_noreflect
+// +build tinygo hrw_noreflect
+
+package hrw
+
+// sliceSwapper returns swapper and length of slice, without reflection
+// only []int, []int8, []int16, []int32, []int64, []uint, []uint32,
+// []uint64, []string, []byte, []Hasher and []interface{} are supported
+func sliceSwapper(slice interface{}) (swapper, int, bool) {
+	return knownSwapper(slice)
+}
+
+// sliceHashers returns accessor of slice elements, without reflection
+// only []Hasher and []interface{} are supported
+func sliceHashers(slice interface{}) (func(i int) Hasher, error) {
+	return knownHashers(slice)
+}
+
+// mapRange iterates over map, without reflection only map[string]string,
+// map[string]interface{}, map[string]Hasher, map[string]struct{}
+// and map[string]bool are supported
+func mapRange(m interface{}, fn func(key string, value func() interface{})) error {
+	if !knownMapRange(m, fn) {
+		return notMap(m)
+	}
+	return nil
+}
+
+// structKey can't inspect structs without reflection
+func structKey(v interface{}, _ *Key) error {
+	return notStruct(v)
+}
@@ -0,0 +1,7 @@
+//go:build tinygo || hrw_noreflect
+// +build tinygo hrw_noreflect
+
+package hrw
+
+// withReflect reports whether slices of any type are supported
+const withReflect = false
@@ -0,0 +1,152 @@
+package hrw
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+type weighted struct {
+	hashed
+	normal []float64
+}
+
+func (h weighted) Less(i, j int) bool {
+	// nspcc-dev/hrw makes the least distance the most valuable
+	lhs := float64(^uint64(0)-h.weight[h.sorted[i]]) * h.normal[i]
+	rhs := float64(^uint64(0)-h.weight[h.sorted[j]]) * h.normal[j]
+	return lhs > rhs
+}
+
+func (h weighted) Swap(i, j int) {
+	h.normal[i], h.normal[j] = h.normal[j], h.normal[i]
+	h.hashed.Swap(i, j)
+}
+
+// SortByWeightNSPCC sorts nodes like SortByWeight of nspcc-dev/hrw fork.
+// Weights must be normalized to [0, 1] and have the same length as nodes,
+// otherwise nodes sorted like SortByWeight does.
+func SortByWeightNSPCC(nodes []uint64, weights []float64, hash uint64) []uint64 {
+	return sortByWeightNSPCC(nodes, weights, hash).order(nil)
+}
+
+func sortByWeightNSPCC(nodes []uint64, weights []float64, hash uint64) hashed {
+	var (
+		l = len(nodes)
+		h = hashed{
+			length: l,
+			sorted: make([]int32, 0, l),
+			weight: make([]uint64, 0, l),
+		}
+	)
+
+	for i, node := range nodes {
+		h.sorted = append(h.sorted, int32(i))
+		h.weight = append(h.weight, weight(node, hash))
+	}
+
+	if len(weights) == l && !sameWeights(weights) {
+		w := weighted{hashed: h, normal: make([]float64, l)}
+		copy(w.normal, weights)
+		sort.Sort(w)
+		return w.hashed
+	}
+
+	sort.Sort(h)
+	return h
+}
+
+// SortSliceByValueNSPCC sorts slice like SortSliceByValue of nspcc-dev/hrw fork
+func SortSliceByValueNSPCC(slice interface{}, hash uint64) {
+	SortSliceByWeightValueNSPCC(slice, nil, hash)
+}
+
+// SortSliceByWeightValueNSPCC sorts slice like SortSliceByWeightValue
+// of nspcc-dev/hrw fork, see SortByWeightNSPCC for weights requirements
+func SortSliceByWeightValueNSPCC(slice interface{}, weights []float64, hash uint64) {
+	checkStrict(sortSliceByWeightValueNSPCC(slice, weights, hash))
+}
+
+// TrySortSliceByWeightValueNSPCC is like SortSliceByWeightValueNSPCC, but
+// returns error for unsupported input, including non-nil weights of length
+// different from slice
+func TrySortSliceByWeightValueNSPCC(slice interface{}, weights []float64, hash uint64) error {
+	if _, length, ok := sliceSwapper(slice); ok && weights != nil && len(weights) != length {
+		return weightsMismatch(len(weights), length)
+	}
+	return sortSliceByWeightValueNSPCC(slice, weights, hash)
+}
+
+func sortSliceByWeightValueNSPCC(slice interface{}, weights []float64, hash uint64) error {
+	swap, length, ok := sliceSwapper(slice)
+	if !ok {
+		return notSlice(slice)
+	} else if length == 0 {
+		return nil
+	}
+
+	rule, nils, err := prepareRuleNSPCC(slice, length)
+	if err != nil {
+		return err
+	}
+
+	if len(nils) != 0 && len(weights) == length {
+		weights = withoutNils(weights, nils)
+	}
+
+	return applyOrder(swap, length, sortByWeightNSPCC(rule, weights, hash).sorted, nils)
+}
+
+// prepareRuleNSPCC returns hashes of values, unlike SortSliceByValue
+// fork doesn't mix them with key hash before sorting
+func prepareRuleNSPCC(slice interface{}, length int) ([]uint64, []int, error) {
+	rule := make([]uint64, 0, length)
+
+	switch slice := slice.(type) {
+	case []int:
+		var key = make([]byte, 16)
+		for i := 0; i < length; i++ {
+			binary.BigEndian.PutUint64(key, uint64(slice[i]))
+			rule = append(rule, Hash(key))
+		}
+	case []int32:
+		var key = make([]byte, 16)
+		for i := 0; i < length; i++ {
+			binary.BigEndian.PutUint32(key, uint32(slice[i]))
+			rule = append(rule, Hash(key))
+		}
+	case []string:
+		for i := 0; i < length; i++ {
+			rule = append(rule, Hash([]byte(slice[i])))
+		}
+	default:
+		at, err := sliceHashers(slice)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		rule, nils := hashersRule(at, length, rule, nil)
+		return rule, nils, nil
+	}
+	return rule, nil, nil
+}
+
+func withoutNils(weights []float64, nils []int) []float64 {
+	result := make([]float64, 0, len(weights)-len(nils))
+	for i, n := 0, 0; i < len(weights); i++ {
+		if n < len(nils) && nils[n] == i {
+			n++
+			continue
+		}
+		result = append(result, weights[i])
+	}
+	return result
+}
+
+func sameWeights(weights []float64) bool {
+	for i := 1; i < len(weights); i++ {
+		if weights[i] != weights[0] {
+			return false
+		}
+	}
+	return true
+}
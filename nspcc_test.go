@@ -0,0 +1,94 @@
+package hrw
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSortByWeightNSPCC(t *testing.T) {
+	var (
+		nodes = []uint64{1, 2, 3, 4, 5}
+		hash  = Hash(testKey)
+	)
+
+	t.Run("without weights", func(t *testing.T) {
+		actual := SortByWeightNSPCC(nodes, nil, hash)
+		expect := SortByWeight(nodes, hash)
+		if !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+
+	t.Run("weighted", func(t *testing.T) {
+		weights := []float64{0.1, 0.2, 0.3, 0.4, 1}
+		actual := SortByWeightNSPCC(nodes, weights, hash)
+
+		dist := func(i uint64) float64 {
+			return float64(^uint64(0)-weight(nodes[i], hash)) * weights[i]
+		}
+		if !sort.SliceIsSorted(actual, func(i, j int) bool {
+			return dist(actual[i]) > dist(actual[j])
+		}) {
+			t.Errorf("Order %v doesn't follow weighted distances", actual)
+		}
+	})
+}
+
+func TestSortSliceByValueNSPCC(t *testing.T) {
+	var (
+		hash    = Hash(testKey)
+		strings = []string{"a", "b", "c", "d", "e", "f"}
+		hashers = []Hasher{hashString("a"), hashString("b"), hashString("c"), hashString("d"), hashString("e"), hashString("f")}
+		rule    = make([]uint64, 0, len(strings))
+	)
+
+	for _, s := range strings {
+		rule = append(rule, Hash([]byte(s)))
+	}
+
+	expect := make([]string, 0, len(strings))
+	for _, i := range SortByWeight(rule, hash) {
+		expect = append(expect, strings[i])
+	}
+
+	SortSliceByValueNSPCC(strings, hash)
+	if !reflect.DeepEqual(strings, expect) {
+		t.Errorf("Was %#v, but expected %#v", strings, expect)
+	}
+
+	SortSliceByValueNSPCC(hashers, hash)
+	for i := range hashers {
+		if string(hashers[i].(hashString)) != expect[i] {
+			t.Errorf("Was %#v, but expected %#v", hashers, expect)
+			break
+		}
+	}
+
+	t.Run("unknown type", func(t *testing.T) {
+		actual := []byte{1, 2, 3}
+		SortSliceByValueNSPCC(actual, hash)
+		if expect := []byte{1, 2, 3}; !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+}
+
+func TestRingNSPCC(t *testing.T) {
+	var (
+		hash    = Hash(testKey)
+		r       = NewRing()
+		ids     = []string{"a", "b", "c", "d", "e", "f"}
+		weights = []float64{0.5, 1, 0.25, 1, 0.75, 0.5}
+	)
+
+	r.SetAlgorithm(NSPCC)
+	for i := range ids {
+		r.Add(Node{ID: ids[i], Weight: weights[i]})
+	}
+
+	SortSliceByWeightValueNSPCC(ids, weights, hash)
+	if actual := nodeIDs(r.GetN(testKey, len(ids))); !reflect.DeepEqual(actual, ids) {
+		t.Errorf("Was %#v, but expected %#v", actual, ids)
+	}
+}
@@ -0,0 +1,32 @@
+package hrw
+
+// PenaltyFunc returns penalty added to score of node before final ordering,
+// e.g. derived from measured latency. Lower score is more preferable,
+// penalties are in units of score. For V1 and V2 scores are exponentially
+// distributed with mean 1/weight, among n equal nodes of weight w gaps
+// between top positions are about 1/(n*w), so penalty of 1/(n*w) moves
+// node down by about one position there, while penalty of 1/w moves it
+// behind most of nodes.
+// Function should return the same value for the same node to keep ordering
+// deterministic.
+type PenaltyFunc func(n Node) float64
+
+// SetPenalty sets function used to bias ordering of nodes by Ring,
+// nil removes penalties. It's called for every active node on every
+// selection, so it should be cheap.
+func (r *Ring) SetPenalty(fn PenaltyFunc) {
+	r.update(func(s *Snapshot) bool {
+		s.penalty = fn
+		return true
+	})
+}
+
+func (s *Snapshot) penalize(list []candidate) {
+	if s.penalty == nil {
+		return
+	}
+
+	for i := range list {
+		list[i].score += s.penalty(s.nodes[list[i].index].Node)
+	}
+}
@@ -0,0 +1,43 @@
+package hrw
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRingSetPenalty(t *testing.T) {
+	r := NewRing(testNodes(5)...)
+	expect := nodeIDs(r.GetN(testKey, 5))
+
+	t.Run("zero penalty", func(t *testing.T) {
+		r.SetPenalty(func(Node) float64 { return 0 })
+		if actual := nodeIDs(r.GetN(testKey, 5)); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+
+	t.Run("penalize first", func(t *testing.T) {
+		r.SetPenalty(func(n Node) float64 {
+			if n.ID == expect[0] {
+				return 100
+			}
+			return 0
+		})
+
+		actual := nodeIDs(r.GetN(testKey, 5))
+		if !reflect.DeepEqual(actual[:4], expect[1:]) || actual[4] != expect[0] {
+			t.Errorf("Was %#v, but expected %#v moved to the end", actual, expect[0])
+		}
+
+		if again := nodeIDs(r.GetN(testKey, 5)); !reflect.DeepEqual(again, actual) {
+			t.Errorf("Was %#v, but expected %#v", again, actual)
+		}
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		r.SetPenalty(nil)
+		if actual := nodeIDs(r.GetN(testKey, 5)); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+}
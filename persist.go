@@ -0,0 +1,338 @@
+package hrw
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+)
+
+// Snapshot encoding is a magic followed by format version and a sequence
+// of records: uvarint tag, uvarint length and payload. Nodes are records
+// of nested field records. Readers skip records of unknown tags, so new
+// fields can be added without changing format version, it's incremented
+// only by incompatible changes.
+const (
+	persistMagic   = "HRWR"
+	persistVersion = 1
+)
+
+const (
+	recordVersion uint64 = iota + 1
+	recordAlgorithm
+	recordNode
+)
+
+const (
+	fieldID uint64 = iota + 1
+	fieldWeight
+	fieldState
+	fieldAttr
+	fieldPhysical
+	fieldTier
+	fieldGroup
+	fieldDatacenter
+	fieldLocation
+	fieldLabel
+	fieldSeq
+)
+
+// attributes and labels are records of name and value
+const (
+	pairName uint64 = iota + 1
+	pairValue
+)
+
+// Save writes membership of Ring (nodes, algorithm and version) in
+// versioned binary format, see Load
+func (r *Ring) Save(w io.Writer) error {
+	return r.view().Save(w)
+}
+
+// Save is like Ring.Save
+func (s *Snapshot) Save(w io.Writer) error {
+	buf := append([]byte(persistMagic), persistVersion)
+	buf = appendRecord(buf, recordVersion, appendUvarint(nil, s.version))
+	buf = appendRecord(buf, recordAlgorithm, appendUvarint(nil, uint64(s.alg)))
+	for i := range s.nodes {
+		buf = appendRecord(buf, recordNode, encodeMember(&s.nodes[i]))
+	}
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// Load replaces membership and algorithm of Ring by ones written by Save,
+// so process can restore last known membership on restart. Other
+// settings of Ring are kept. Version of Ring is restored unless current
+// one is greater, in this case it's incremented as usual. Nodes are
+// validated like ValidateNodes does, invalid ones fail with ErrFormat.
+func (r *Ring) Load(rd io.Reader) error {
+	data, err := ioutil.ReadAll(rd)
+	if err != nil {
+		return err
+	}
+
+	var (
+		version uint64
+		alg     Algorithm
+		nodes   []member
+		d       = decoder{buf: data}
+	)
+
+	if len(data) < len(persistMagic)+1 || string(data[:len(persistMagic)]) != persistMagic {
+		return fmt.Errorf("%w: bad magic", ErrFormat)
+	} else if v := data[len(persistMagic)]; v != persistVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrFormat, v)
+	}
+
+	d.buf = d.buf[len(persistMagic)+1:]
+	for tag, payload, ok := d.record(); ok; tag, payload, ok = d.record() {
+		switch tag {
+		case recordVersion:
+			if version, err = decodeUvarint(payload); err != nil {
+				return err
+			}
+		case recordAlgorithm:
+			v, err := decodeUvarint(payload)
+			if err != nil {
+				return err
+			} else if alg = Algorithm(v); uint64(alg) != v || !alg.known() {
+				return fmt.Errorf("%w: unknown algorithm %d", ErrFormat, v)
+			}
+		case recordNode:
+			m, err := decodeMember(payload)
+			if err != nil {
+				return err
+			}
+			nodes = append(nodes, m)
+		}
+	}
+
+	if d.err != nil {
+		return d.err
+	} else if err := validateMembers(nodes); err != nil {
+		return fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	r.update(func(s *Snapshot) bool {
+		for i := range nodes {
+			nodes[i].hash = s.hashFn.hashString(nodes[i].ID)
+		}
+
+		s.nodes, s.alg = nodes, alg
+		if version > s.version {
+			s.version = version - 1
+		}
+		return true
+	})
+	return nil
+}
+
+// validateMembers is like ValidateNodes for decoded members
+func validateMembers(nodes []member) error {
+	list := make([]Node, 0, len(nodes))
+	for i := range nodes {
+		list = append(list, nodes[i].Node)
+	}
+	return ValidateNodes(list)
+}
+
+func encodeMember(m *member) []byte {
+	var buf []byte
+	buf = appendRecord(buf, fieldID, []byte(m.ID))
+	buf = appendRecord(buf, fieldWeight, appendFloat(nil, m.Weight))
+	buf = appendRecord(buf, fieldState, appendUvarint(nil, uint64(m.State)))
+	buf = appendRecord(buf, fieldTier, appendVarint(nil, int64(m.Tier)))
+	buf = appendRecord(buf, fieldSeq, appendUvarint(nil, m.seq))
+	buf = appendStringRecord(buf, fieldPhysical, m.Physical)
+	buf = appendStringRecord(buf, fieldGroup, m.Group)
+	buf = appendStringRecord(buf, fieldDatacenter, m.Datacenter)
+
+	attrs := make([]string, 0, len(m.Attrs))
+	for k := range m.Attrs {
+		attrs = append(attrs, k)
+	}
+	sort.Strings(attrs)
+
+	for _, k := range attrs {
+		buf = appendRecord(buf, fieldAttr, encodePair(k, appendFloat(nil, m.Attrs[k])))
+	}
+
+	for _, l := range m.Location {
+		buf = appendRecord(buf, fieldLocation, []byte(l))
+	}
+
+	labels := make([]string, 0, len(m.Labels))
+	for k := range m.Labels {
+		labels = append(labels, k)
+	}
+	sort.Strings(labels)
+
+	for _, k := range labels {
+		buf = appendRecord(buf, fieldLabel, encodePair(k, []byte(m.Labels[k])))
+	}
+	return buf
+}
+
+func decodeMember(data []byte) (member, error) {
+	var (
+		m member
+		d = decoder{buf: data}
+	)
+
+	for tag, payload, ok := d.record(); ok; tag, payload, ok = d.record() {
+		var err error
+		switch tag {
+		case fieldID:
+			m.ID = string(payload)
+		case fieldWeight:
+			m.Weight, err = decodeFloat(payload)
+		case fieldState:
+			var v uint64
+			v, err = decodeUvarint(payload)
+			m.State = State(v)
+		case fieldTier:
+			var v int64
+			v, err = decodeVarint(payload)
+			m.Tier = int(v)
+		case fieldSeq:
+			m.seq, err = decodeUvarint(payload)
+		case fieldPhysical:
+			m.Physical = string(payload)
+		case fieldGroup:
+			m.Group = string(payload)
+		case fieldDatacenter:
+			m.Datacenter = string(payload)
+		case fieldLocation:
+			m.Location = append(m.Location, string(payload))
+		case fieldAttr:
+			name, value, err := decodePair(payload)
+			if err != nil {
+				return m, err
+			} else if m.Attrs == nil {
+				m.Attrs = make(map[string]float64)
+			}
+			if m.Attrs[name], err = decodeFloat(value); err != nil {
+				return m, err
+			}
+		case fieldLabel:
+			name, value, err := decodePair(payload)
+			if err != nil {
+				return m, err
+			} else if m.Labels == nil {
+				m.Labels = make(map[string]string)
+			}
+			m.Labels[name] = string(value)
+		}
+
+		if err != nil {
+			return m, fmt.Errorf("%w of field %d", err, tag)
+		}
+	}
+	return m, d.err
+}
+
+func encodePair(name string, value []byte) []byte {
+	return appendRecord(appendRecord(nil, pairName, []byte(name)), pairValue, value)
+}
+
+func decodePair(data []byte) (string, []byte, error) {
+	var (
+		name  string
+		value []byte
+		d     = decoder{buf: data}
+	)
+
+	for tag, payload, ok := d.record(); ok; tag, payload, ok = d.record() {
+		switch tag {
+		case pairName:
+			name = string(payload)
+		case pairValue:
+			value = payload
+		}
+	}
+	return name, value, d.err
+}
+
+type decoder struct {
+	buf []byte
+	err error
+}
+
+// record returns next record, false is returned at the end of data
+// or when record is malformed, in this case err is set
+func (d *decoder) record() (uint64, []byte, bool) {
+	if d.err != nil || len(d.buf) == 0 {
+		return 0, nil, false
+	}
+
+	tag, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = fmt.Errorf("%w: bad record tag", ErrFormat)
+		return 0, nil, false
+	}
+
+	size, m := binary.Uvarint(d.buf[n:])
+	if m <= 0 || size > uint64(len(d.buf)-n-m) {
+		d.err = fmt.Errorf("%w: bad length of record %d", ErrFormat, tag)
+		return 0, nil, false
+	}
+
+	payload := d.buf[n+m : n+m+int(size)]
+	d.buf = d.buf[n+m+int(size):]
+	return tag, payload, true
+}
+
+func appendRecord(buf []byte, tag uint64, payload []byte) []byte {
+	buf = appendUvarint(buf, tag)
+	buf = appendUvarint(buf, uint64(len(payload)))
+	return append(buf, payload...)
+}
+
+// appendStringRecord appends record for non-empty string
+func appendStringRecord(buf []byte, tag uint64, v string) []byte {
+	if v == "" {
+		return buf
+	}
+	return appendRecord(buf, tag, []byte(v))
+}
+
+func appendVarint(buf []byte, v int64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	return append(buf, tmp[:binary.PutVarint(tmp[:], v)]...)
+}
+
+func appendFloat(buf []byte, v float64) []byte {
+	var tmp [8]byte
+	binary.BigEndian.PutUint64(tmp[:], math.Float64bits(v))
+	return append(buf, tmp[:]...)
+}
+
+func decodeFloat(data []byte) (float64, error) {
+	if len(data) != 8 {
+		return 0, fmt.Errorf("%w: bad length %d of float", ErrFormat, len(data))
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(data)), nil
+}
+
+// decodeUvarint fails unless data is exactly one uvarint
+func decodeUvarint(data []byte) (uint64, error) {
+	v, n := binary.Uvarint(data)
+	if n <= 0 || n != len(data) {
+		return 0, fmt.Errorf("%w: bad uvarint", ErrFormat)
+	}
+	return v, nil
+}
+
+// decodeVarint is like decodeUvarint for signed values
+func decodeVarint(data []byte) (int64, error) {
+	v, n := binary.Varint(data)
+	if n <= 0 || n != len(data) {
+		return 0, fmt.Errorf("%w: bad varint", ErrFormat)
+	}
+	return v, nil
+}
@@ -0,0 +1,123 @@
+package hrw
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	nodes := []Node{
+		{ID: "a", Weight: 2, Attrs: map[string]float64{"disk": 10, "cpu": 4}},
+		{ID: "b", State: StateDown, Tier: -1, Group: "rack-1", Datacenter: "dc-a"},
+		{ID: "c", Physical: "p", Location: []string{"eu", "eu-1"}, Labels: map[string]string{"disk": "ssd"}},
+	}
+
+	src := NewRing(nodes...)
+	src.SetAlgorithm(V3)
+
+	buf := new(bytes.Buffer)
+	if err := src.Save(buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	t.Run("round trip", func(t *testing.T) {
+		dst := NewRing(testNodes(3)...)
+		if err := dst.Load(bytes.NewReader(data)); err != nil {
+			t.Fatal(err)
+		}
+
+		if actual := dst.Nodes(); !reflect.DeepEqual(actual, nodes) {
+			t.Errorf("Was %#v, but expected %#v", actual, nodes)
+		}
+
+		if actual, expect := dst.Version(), src.Version(); actual != expect {
+			t.Errorf("Was %d, but expected %d", actual, expect)
+		}
+
+		if actual, expect := dst.GetN(testKey, 3), src.GetN(testKey, 3); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+
+	t.Run("unknown records", func(t *testing.T) {
+		extended := appendRecord(append([]byte(nil), data...), 100, []byte("future"))
+
+		dst := NewRing()
+		if err := dst.Load(bytes.NewReader(extended)); err != nil {
+			t.Fatal(err)
+		}
+
+		if actual := dst.Nodes(); !reflect.DeepEqual(actual, nodes) {
+			t.Errorf("Was %#v, but expected %#v", actual, nodes)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, bad := range [][]byte{nil, []byte("HRWR\x02"), data[:len(data)-1]} {
+			if err := NewRing().Load(bytes.NewReader(bad)); !errors.Is(err, ErrFormat) {
+				t.Errorf("Was %#v, but expected %#v", err, ErrFormat)
+			}
+		}
+	})
+
+	t.Run("invalid nodes", func(t *testing.T) {
+		for _, members := range [][]member{
+			{{Node: Node{ID: "a"}}, {Node: Node{ID: "a"}}},
+			{{Node: Node{ID: "a"}}, {Node: Node{}}},
+			{{Node: Node{ID: "a", Weight: -1}}},
+			{{Node: Node{ID: "a", Weight: math.NaN()}}},
+			{{Node: Node{ID: "a", Weight: math.Inf(1)}}},
+		} {
+			bad := append([]byte(persistMagic), persistVersion)
+			for i := range members {
+				bad = appendRecord(bad, recordNode, encodeMember(&members[i]))
+			}
+
+			dst := NewRing(nodes...)
+			if err := dst.Load(bytes.NewReader(bad)); !errors.Is(err, ErrFormat) {
+				t.Errorf("Was %#v, but expected %#v", err, ErrFormat)
+			}
+
+			if actual := dst.Nodes(); !reflect.DeepEqual(actual, nodes) {
+				t.Errorf("Was %#v, but expected %#v", actual, nodes)
+			}
+		}
+	})
+
+	t.Run("corrupt records", func(t *testing.T) {
+		var (
+			id       = appendRecord(nil, fieldID, []byte("a"))
+			overflow = bytes.Repeat([]byte{0xff}, 11)
+		)
+
+		for name, records := range map[string][]byte{
+			"algorithm":          appendRecord(nil, recordAlgorithm, appendUvarint(nil, 100)),
+			"algorithm overflow": appendRecord(nil, recordAlgorithm, appendUvarint(nil, 256)),
+			"version overflow":   appendRecord(nil, recordVersion, overflow),
+			"version trailing":   appendRecord(nil, recordVersion, []byte{1, 2}),
+			"version empty":      appendRecord(nil, recordVersion, nil),
+			"weight length":      appendRecord(nil, recordNode, appendRecord(id, fieldWeight, []byte{1, 2, 3})),
+			"state truncated":    appendRecord(nil, recordNode, appendRecord(id, fieldState, []byte{0x80})),
+			"tier overflow":      appendRecord(nil, recordNode, appendRecord(id, fieldTier, overflow)),
+			"seq trailing":       appendRecord(nil, recordNode, appendRecord(id, fieldSeq, []byte{1, 2})),
+			"attr length":        appendRecord(nil, recordNode, appendRecord(id, fieldAttr, encodePair("disk", []byte{1}))),
+			"attr without value": appendRecord(nil, recordNode, appendRecord(id, fieldAttr, encodePair("disk", nil))),
+			"weight empty":       appendRecord(nil, recordNode, appendRecord(id, fieldWeight, nil)),
+		} {
+			bad := append(append([]byte(persistMagic), persistVersion), records...)
+
+			dst := NewRing(nodes...)
+			if err := dst.Load(bytes.NewReader(bad)); !errors.Is(err, ErrFormat) {
+				t.Errorf("%s: was %#v, but expected %#v", name, err, ErrFormat)
+			}
+
+			if actual := dst.Nodes(); !reflect.DeepEqual(actual, nodes) {
+				t.Errorf("%s: was %#v, but expected %#v", name, actual, nodes)
+			}
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package hrw
+
+import (
+	"bytes"
+	"sort"
+	"time"
+)
+
+// Pin explicitly maps key or prefix of keys to node
+type Pin struct {
+	Key    []byte
+	Prefix bool
+	NodeID string
+}
+
+// Pin maps key to node, Get and GetN return pinned node first while
+// it's active and fall back to HRW otherwise
+func (r *Ring) Pin(key []byte, id string) {
+	r.updatePins(func(s *Snapshot) {
+		s.pins[string(key)] = id
+	})
+}
+
+// PinPrefix maps keys with prefix to node, the longest matching prefix
+// is used, pins of exact keys take precedence over prefixes
+func (r *Ring) PinPrefix(prefix []byte, id string) {
+	r.updatePins(func(s *Snapshot) {
+		s.prefix = removePrefixPin(s.prefix, prefix)
+		s.prefix = append(s.prefix, Pin{Key: append([]byte(nil), prefix...), Prefix: true, NodeID: id})
+		sort.SliceStable(s.prefix, func(i, j int) bool { return len(s.prefix[i].Key) > len(s.prefix[j].Key) })
+	})
+}
+
+// Unpin removes pin of key
+func (r *Ring) Unpin(key []byte) {
+	r.updatePins(func(s *Snapshot) {
+		delete(s.pins, string(key))
+	})
+}
+
+// UnpinPrefix removes pin of prefix
+func (r *Ring) UnpinPrefix(prefix []byte) {
+	r.updatePins(func(s *Snapshot) {
+		s.prefix = removePrefixPin(s.prefix, prefix)
+	})
+}
+
+// Pins returns all pins, exact ones ordered by key and then prefixes
+// from the longest one
+func (r *Ring) Pins() []Pin {
+	return r.view().Pins()
+}
+
+// InvalidPins returns pins which nodes are missing or inactive,
+// they're ignored by selection until node is back
+func (r *Ring) InvalidPins() []Pin {
+	return r.view().InvalidPins()
+}
+
+// Pins is like Ring.Pins
+func (s *Snapshot) Pins() []Pin {
+	result := make([]Pin, 0, len(s.pins)+len(s.prefix))
+	for key, id := range s.pins {
+		result = append(result, Pin{Key: []byte(key), NodeID: id})
+	}
+	sort.Slice(result, func(i, j int) bool { return bytes.Compare(result[i].Key, result[j].Key) < 0 })
+	return append(result, s.prefix...)
+}
+
+// InvalidPins is like Ring.InvalidPins
+func (s *Snapshot) InvalidPins() []Pin {
+	var (
+		now    = s.clock()
+		result []Pin
+	)
+
+	for _, p := range s.Pins() {
+		if _, ok := s.activeMember(p.NodeID, now); !ok {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func (r *Ring) updatePins(fn func(s *Snapshot)) {
+	r.update(func(s *Snapshot) bool {
+		pins := make(map[string]string, len(s.pins)+1)
+		for key, id := range s.pins {
+			pins[key] = id
+		}
+
+		s.pins = pins
+		s.prefix = append([]Pin(nil), s.prefix...)
+		fn(s)
+		return true
+	})
+}
+
+func removePrefixPin(pins []Pin, prefix []byte) []Pin {
+	result := pins[:0]
+	for _, p := range pins {
+		if !bytes.Equal(p.Key, prefix) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// pinned returns ID of node pinned to key
+func (s *Snapshot) pinned(key []byte) (string, bool) {
+	if id, ok := s.pins[string(key)]; ok {
+		return id, true
+	}
+
+	for _, p := range s.prefix {
+		if bytes.HasPrefix(key, p.Key) {
+			return p.NodeID, true
+		}
+	}
+	return "", false
+}
+
+// activeMember returns selectable member with given ID
+func (s *Snapshot) activeMember(id string, now time.Time) (*member, bool) {
+	i, ok := findMember(s.nodes, id)
+	if !ok {
+		return nil, false
+	}
+
+	m := &s.nodes[i]
+	state := s.stateOf(m, now)
+	return m, state.selectable()
+}
+
+// pickPinned returns pinned node followed by nodes selected by HRW,
+// or only the latter when pinned node isn't active
+func (s *Snapshot) pickPinned(key []byte, id string, n int) []Node {
+	var now time.Time
+	if len(s.lists) > 0 {
+		now = s.clock()
+	}
+
+	m, ok := s.activeMember(id, now)
+	if !ok {
+		return s.pick(s.Hash(key), n)
+	}
+
+	var (
+		hash   = s.Hash(key)
+		list   = s.rank(hash)
+		result = append(make([]Node, 0, n), m.Node)
+	)
+
+	s.penalize(list)
+	for _, node := range topMembers(s.nodes, list, n) {
+		if len(result) == n {
+			break
+		} else if node.ID != id {
+			result = append(result, node)
+		}
+	}
+
+	if s.trace != nil {
+		s.emitTrace(hash, list, result)
+	}
+	return result
+}
@@ -0,0 +1,89 @@
+package hrw
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRingPin(t *testing.T) {
+	var (
+		r      = NewRing(testNodes(5)...)
+		expect = nodeIDs(r.GetN(testKey, 5))
+		last   = expect[4]
+	)
+
+	r.Pin(testKey, last)
+	pinned := append([]string{last}, expect[:2]...)
+	if actual := nodeIDs(r.GetN(testKey, 3)); !reflect.DeepEqual(actual, pinned) {
+		t.Errorf("Was %#v, but expected %#v", actual, pinned)
+	}
+
+	if n, _ := r.Get(testKey); n.ID != last {
+		t.Errorf("Was %#v, but expected %#v", n.ID, last)
+	}
+
+	r.Remove(last)
+	if actual, expect := nodeIDs(r.GetN(testKey, 3)), expect[:3]; !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	invalid := []Pin{{Key: testKey, NodeID: last}}
+	if actual := r.InvalidPins(); !reflect.DeepEqual(actual, invalid) {
+		t.Errorf("Was %#v, but expected %#v", actual, invalid)
+	}
+
+	r.Unpin(testKey)
+	if actual := r.Pins(); len(actual) != 0 {
+		t.Errorf("Expected no pins, got %#v", actual)
+	}
+}
+
+func TestRingPinPrefix(t *testing.T) {
+	r := NewRing(testNodes(5)...)
+	r.PinPrefix([]byte("user/"), "node-1")
+	r.PinPrefix([]byte("user/42/"), "node-2")
+	r.Pin([]byte("user/42/avatar"), "node-3")
+
+	cases := map[string]string{
+		"user/1":         "node-1",
+		"user/42/name":   "node-2",
+		"user/42/avatar": "node-3",
+	}
+
+	for key, expect := range cases {
+		if n, _ := r.Get([]byte(key)); n.ID != expect {
+			t.Errorf("Was %#v, but expected %#v", n.ID, expect)
+		}
+	}
+
+	r.PinPrefix([]byte("user/"), "node-4")
+	r.UnpinPrefix([]byte("user/42/"))
+
+	expect := []Pin{
+		{Key: []byte("user/42/avatar"), NodeID: "node-3"},
+		{Key: []byte("user/"), Prefix: true, NodeID: "node-4"},
+	}
+	if actual := r.Pins(); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	if n, _ := r.Get([]byte("user/42/name")); n.ID != "node-4" {
+		t.Errorf("Was %#v, but expected %#v", n.ID, "node-4")
+	}
+}
+
+func TestCachePinned(t *testing.T) {
+	var (
+		r = NewRing(testNodes(5)...)
+		c = NewCache(r, 10, 0)
+	)
+
+	r.Pin(testKey, "node-3")
+	if n, _ := c.Get(testKey); n.ID != "node-3" {
+		t.Errorf("Was %#v, but expected %#v", n.ID, "node-3")
+	}
+
+	if actual := c.Len(); actual != 0 {
+		t.Errorf("Was %d, but expected %d", actual, 0)
+	}
+}
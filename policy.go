@@ -0,0 +1,156 @@
+package hrw
+
+import "fmt"
+
+type (
+	// Filter reports whether node is eligible for Selector
+	Filter func(Node) bool
+
+	// Selector selects Count nodes matching filter and spread across
+	// topology levels, build it with Select:
+	//
+	//	Select(3).Where(Label("tier", "ssd")).SpreadBy("rack")
+	Selector struct {
+		Count  int
+		Filter Filter
+		Levels []string
+	}
+
+	// Policy is a list of selectors, nodes selected by them are distinct
+	// and follow in order of selectors
+	Policy []Selector
+)
+
+// Select returns Selector of n nodes
+func Select(n int) Selector {
+	return Selector{Count: n}
+}
+
+// Where returns copy of selector with filter, it's combined
+// with filter of selector if any
+func (sel Selector) Where(f Filter) Selector {
+	if sel.Filter != nil {
+		f = And(sel.Filter, f)
+	}
+	sel.Filter = f
+	return sel
+}
+
+// SpreadBy returns copy of selector spreading nodes across topology
+// levels like GetSpread does
+func (sel Selector) SpreadBy(levels ...string) Selector {
+	sel.Levels = append(append([]string(nil), sel.Levels...), levels...)
+	return sel
+}
+
+// Label matches nodes with label key equal to one of values
+func Label(key string, values ...string) Filter {
+	return func(n Node) bool {
+		v, ok := n.Labels[key]
+		for i := 0; ok && i < len(values); i++ {
+			if values[i] == v {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AttrAtLeast matches nodes with attribute name not lower than min
+func AttrAtLeast(name string, min float64) Filter {
+	return func(n Node) bool {
+		v, ok := n.Attrs[name]
+		return ok && v >= min
+	}
+}
+
+// InDatacenter matches nodes of given datacenters
+func InDatacenter(dcs ...string) Filter {
+	return func(n Node) bool {
+		for i := range dcs {
+			if dcs[i] == n.Datacenter {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// And matches nodes matched by all filters
+func And(filters ...Filter) Filter {
+	return func(n Node) bool {
+		for _, f := range filters {
+			if !f(n) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches nodes matched by any of filters
+func Or(filters ...Filter) Filter {
+	return func(n Node) bool {
+		for _, f := range filters {
+			if f(n) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches nodes not matched by f
+func Not(f Filter) Filter {
+	return func(n Node) bool { return !f(n) }
+}
+
+// GetPolicy returns nodes for key selected by policy. Nodes are ranked
+// once and every selector takes the most preferable of remaining ones.
+// Error is returned when selector can't select enough nodes, result
+// holds nodes selected so far, ErrNoEligibleNodes is returned when none
+// of nodes is selectable.
+func (r *Ring) GetPolicy(key []byte, p Policy) ([]Node, error) {
+	return r.view().GetPolicy(key, p)
+}
+
+// GetPolicy is like Ring.GetPolicy
+func (s *Snapshot) GetPolicy(key []byte, p Policy) ([]Node, error) {
+	list, err := s.eligible(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		result []Node
+		taken  = make(map[string]struct{})
+	)
+
+	for i, sel := range p {
+		depths, err := s.levelDepths(sel.Levels)
+		if err != nil {
+			return result, err
+		}
+
+		eligible := make([]Node, 0, len(list))
+		for _, n := range list {
+			if _, ok := taken[n.ID]; ok {
+				continue
+			} else if sel.Filter == nil || sel.Filter(n) {
+				eligible = append(eligible, n)
+			}
+		}
+
+		selected := spread(eligible, sel.Count, depths)
+		for _, n := range selected {
+			taken[n.ID] = struct{}{}
+		}
+
+		result = append(result, selected...)
+		if len(selected) < sel.Count {
+			return result, fmt.Errorf("%w: selector %d selected %d of %d nodes",
+				ErrPolicy, i, len(selected), sel.Count)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package hrw
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGetPolicy(t *testing.T) {
+	nodes := topologyNodes()
+	for i := range nodes {
+		nodes[i].Labels = map[string]string{"disk": "hdd"}
+		if i%2 == 0 {
+			nodes[i].Labels["disk"] = "ssd"
+		}
+	}
+
+	r := NewRing(nodes...)
+	r.SetTopology("zone", "rack")
+
+	t.Run("select", func(t *testing.T) {
+		p := Policy{
+			Select(3).Where(Label("disk", "ssd")).SpreadBy("zone"),
+			Select(2).Where(Not(Label("disk", "ssd"))),
+		}
+
+		actual, err := r.GetPolicy(testKey, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		zones := make(map[string]struct{})
+		for i, n := range actual {
+			disk := "hdd"
+			if i < 3 {
+				disk = "ssd"
+				zones[n.Location[0]] = struct{}{}
+			}
+
+			if n.Labels["disk"] != disk {
+				t.Errorf("Was %q, but expected %q", n.Labels["disk"], disk)
+			}
+		}
+
+		if len(actual) != 5 || len(zones) != 3 {
+			t.Errorf("Was %d nodes in %d zones, but expected 5 nodes and 3 zones", len(actual), len(zones))
+		}
+	})
+
+	t.Run("same as GetN", func(t *testing.T) {
+		actual, err := r.GetPolicy(testKey, Policy{Select(4)})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expect := r.GetN(testKey, 4); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", nodeIDs(actual), nodeIDs(expect))
+		}
+	})
+
+	t.Run("unsatisfied", func(t *testing.T) {
+		p := Policy{Select(7).Where(Or(Label("disk", "ssd"), AttrAtLeast("size", 1)))}
+		if actual, err := r.GetPolicy(testKey, p); !errors.Is(err, ErrPolicy) || len(actual) != 6 {
+			t.Errorf("Was %d nodes and %#v, but expected 6 nodes and %#v", len(actual), err, ErrPolicy)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package hrw
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ConnPool holds established connections keyed by node ID and returns
+// connections of nodes selected by Ring. Nodes without connection are
+// skipped down the preference list, so requests don't retry on the same
+// dead node. It's safe for concurrent use.
+type ConnPool struct {
+	ring  *Ring
+	mu    sync.RWMutex
+	conns map[string]interface{}
+}
+
+// NewConnPool creates empty pool of connections to nodes of r
+func NewConnPool(r *Ring) *ConnPool {
+	return &ConnPool{ring: r, conns: make(map[string]interface{})}
+}
+
+// Set stores connection of node, previous one is replaced
+func (p *ConnPool) Set(id string, conn interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.conns[id] = conn
+}
+
+// Delete removes connection of node, e.g. when it's broken, and returns
+// it so caller can close it
+func (p *ConnPool) Delete(id string) (interface{}, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	conn, ok := p.conns[id]
+	delete(p.conns, id)
+	return conn, ok
+}
+
+// Get returns connection of the most preferable node for key that has
+// connection and isn't excluded, exclude holds IDs of nodes already tried.
+// ErrNoEligibleNodes is returned when there's no such node.
+func (p *ConnPool) Get(key []byte, exclude ...string) (Node, interface{}, error) {
+	s := p.ring.view()
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, n := range s.GetN(key, s.Len()) {
+		if conn, ok := p.conns[n.ID]; ok && !contains(exclude, n.ID) {
+			return n, conn, nil
+		}
+	}
+	return Node{}, nil, fmt.Errorf("%w: no connected nodes for %q", ErrNoEligibleNodes, key)
+}
+
+func contains(list []string, id string) bool {
+	for _, v := range list {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
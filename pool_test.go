@@ -0,0 +1,40 @@
+package hrw
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConnPool(t *testing.T) {
+	var (
+		r     = NewRing(testNodes(4)...)
+		p     = NewConnPool(r)
+		order = r.GetN(testKey, 4)
+	)
+
+	for _, n := range order {
+		p.Set(n.ID, "conn-"+n.ID)
+	}
+
+	n, conn, err := p.Get(testKey)
+	if err != nil || n.ID != order[0].ID || conn != "conn-"+order[0].ID {
+		t.Errorf("Was %q, %v (%v), but expected %q", n.ID, conn, err, order[0].ID)
+	}
+
+	// unavailable connection falls back to the next node
+	if _, ok := p.Delete(order[0].ID); !ok {
+		t.Errorf("Expected connection of %q", order[0].ID)
+	}
+	if n, _, _ := p.Get(testKey); n.ID != order[1].ID {
+		t.Errorf("Was %q, but expected %q", n.ID, order[1].ID)
+	}
+
+	// tried nodes are skipped
+	if n, _, _ := p.Get(testKey, order[1].ID); n.ID != order[2].ID {
+		t.Errorf("Was %q, but expected %q", n.ID, order[2].ID)
+	}
+
+	if _, _, err := p.Get(testKey, order[1].ID, order[2].ID, order[3].ID); !errors.Is(err, ErrNoEligibleNodes) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrNoEligibleNodes)
+	}
+}
@@ -0,0 +1,137 @@
+package hrw
+
+import "math/bits"
+
+type (
+	// AvalancheReport describes how output bits of WeightFunc react on
+	// flips of input bits. For good mixer every output bit flips with
+	// probability 0.5 for every flipped input bit.
+	AvalancheReport struct {
+		Samples int
+		// NodeBias and HashBias are maximal deviations from 0.5 of
+		// probability of output bit flip, when bit of node or key hash flips
+		NodeBias, HashBias float64
+		// MeanBias is mean deviation over all pairs of input and output bits
+		MeanBias float64
+	}
+
+	// BiasReport describes correlations of placements made by WeightFunc
+	// for sequential nodes and keys, which are the most common inputs
+	BiasReport struct {
+		Samples, Nodes int
+		// PairBias is maximal deviation from 0.5 of probability that node
+		// outranks the next sequential node
+		PairBias float64
+		// SameWinner is probability that sequential keys select the same
+		// node, for uncorrelated placements it's close to 1 / Nodes
+		SameWinner float64
+	}
+)
+
+// MeasureAvalanche flips every input bit of fn over samples random inputs
+func MeasureAvalanche(fn WeightFunc, samples int) AvalancheReport {
+	var (
+		rep   = AvalancheReport{Samples: samples}
+		flips [2][64][64]int
+		state uint64
+	)
+
+	for i := 0; i < samples; i++ {
+		node, hash := nextRandom(&state), nextRandom(&state)
+		out := fn(node, hash)
+
+		for b := 0; b < 64; b++ {
+			countFlips(&flips[0][b], out^fn(node^1<<b, hash))
+			countFlips(&flips[1][b], out^fn(node, hash^1<<b))
+		}
+	}
+
+	if samples <= 0 {
+		return rep
+	}
+
+	var sum float64
+	for input := range flips {
+		for b := range flips[input] {
+			for _, c := range flips[input][b] {
+				d := float64(c)/float64(samples) - 0.5
+				if d < 0 {
+					d = -d
+				}
+
+				sum += d
+				if input == 0 && d > rep.NodeBias {
+					rep.NodeBias = d
+				} else if input == 1 && d > rep.HashBias {
+					rep.HashBias = d
+				}
+			}
+		}
+	}
+
+	rep.MeanBias = sum / (2 * 64 * 64)
+	return rep
+}
+
+// MeasureBias places samples sequential keys over nodes sequential nodes
+// (0, 1, 2, ...), it's what most users do with SortByWeight
+func MeasureBias(fn WeightFunc, nodes, samples int) BiasReport {
+	var (
+		rep     = BiasReport{Samples: samples, Nodes: nodes}
+		wins    = make([]int, nodes)
+		weights = make([]uint64, nodes)
+		prev    = -1
+		same    int
+	)
+
+	if nodes < 2 || samples <= 0 {
+		return rep
+	}
+
+	for key := 0; key < samples; key++ {
+		winner := 0
+		for n := range weights {
+			weights[n] = fn(uint64(n), uint64(key))
+			if weights[n] < weights[winner] {
+				winner = n
+			}
+		}
+
+		for n := 0; n+1 < nodes; n++ {
+			if weights[n] < weights[n+1] {
+				wins[n]++
+			}
+		}
+
+		if winner == prev {
+			same++
+		}
+		prev = winner
+	}
+
+	for n := 0; n+1 < nodes; n++ {
+		d := float64(wins[n])/float64(samples) - 0.5
+		if d < 0 {
+			d = -d
+		}
+
+		if d > rep.PairBias {
+			rep.PairBias = d
+		}
+	}
+
+	rep.SameWinner = float64(same) / float64(samples-1)
+	return rep
+}
+
+func countFlips(counts *[64]int, diff uint64) {
+	for ; diff != 0; diff &= diff - 1 {
+		counts[bits.TrailingZeros64(diff)]++
+	}
+}
+
+// nextRandom is splitmix64 generator
+func nextRandom(state *uint64) uint64 {
+	*state += 0x9e3779b97f4a7c15
+	return MixSplitMix64(*state, 0x9e3779b97f4a7c15)
+}
@@ -0,0 +1,39 @@
+package hrw
+
+import "testing"
+
+func TestMeasureAvalanche(t *testing.T) {
+	for name, fn := range map[string]WeightFunc{"murmur3": MixMurmur3, "splitmix64": MixSplitMix64} {
+		rep := MeasureAvalanche(fn, 10000)
+		if rep.NodeBias > 0.05 || rep.HashBias > 0.05 || rep.MeanBias > 0.01 {
+			t.Errorf("%s: unexpected bias %#v", name, rep)
+		}
+	}
+
+	xor := func(node, hash uint64) uint64 { return node ^ hash }
+	if rep := MeasureAvalanche(xor, 1000); rep.NodeBias < 0.4 || rep.HashBias < 0.4 {
+		t.Errorf("xor: expected bias to be detected, got %#v", rep)
+	}
+}
+
+func TestMeasureBias(t *testing.T) {
+	premixed := func(node, hash uint64) uint64 { return MixMurmur3(HashUint64(node), hash) }
+	rep := MeasureBias(premixed, 10, 20000)
+	if rep.PairBias > 0.02 {
+		t.Errorf("Was %#v, expected PairBias <= 0.02", rep)
+	}
+
+	if rep.SameWinner < 0.08 || rep.SameWinner > 0.12 {
+		t.Errorf("Was %#v, expected SameWinner close to 0.1", rep)
+	}
+
+	// xor of sequential nodes and keys correlates placements of adjacent keys
+	if rep := MeasureBias(MixMurmur3, 10, 20000); rep.SameWinner > 0.05 {
+		t.Errorf("murmur3: expected correlation to be detected, got %#v", rep)
+	}
+
+	add := func(node, hash uint64) uint64 { return node + hash }
+	if rep := MeasureBias(add, 10, 1000); rep.PairBias < 0.4 || rep.SameWinner < 0.9 {
+		t.Errorf("add: expected bias to be detected, got %#v", rep)
+	}
+}
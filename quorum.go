@@ -0,0 +1,87 @@
+package hrw
+
+import "fmt"
+
+type (
+	// Quorum describes Dynamo-style replication: every key is stored on N
+	// nodes, reads wait for R of them and writes wait for W of them
+	Quorum struct {
+		N, R, W int
+		// Skip reports whether node must be skipped, e.g. it's unreachable
+		// from caller, nil skips nothing. Down nodes are always skipped.
+		Skip func(Node) bool
+	}
+
+	// QuorumSet is a selection of nodes for key
+	QuorumSet struct {
+		// Preference is a list of first N active nodes for key
+		Preference []Node
+		// Replicas are N nodes to store key: reachable nodes of preference
+		// list followed by fallback ones standing in for skipped nodes
+		Replicas []Node
+		// Read is first R nodes of Replicas
+		Read []Node
+		// Write is first W nodes of Replicas
+		Write []Node
+		// Hints maps ID of fallback node to ID of skipped preference node
+		// it stands in for, so fallback can hand off data when it's back
+		Hints map[string]string
+	}
+)
+
+// Quorum returns nodes for key according to q, ErrNoEligibleNodes is
+// returned when none of nodes is selectable
+func (r *Ring) Quorum(key []byte, q Quorum) (QuorumSet, error) {
+	return r.view().Quorum(key, q)
+}
+
+// Quorum is like Ring.Quorum
+func (s *Snapshot) Quorum(key []byte, q Quorum) (QuorumSet, error) {
+	if q.N <= 0 || q.R <= 0 || q.W <= 0 || q.R > q.N || q.W > q.N {
+		return QuorumSet{}, invalidQuorum(q)
+	}
+
+	nodes, err := s.eligible(key)
+	if err != nil {
+		return QuorumSet{}, err
+	}
+
+	var (
+		set     QuorumSet
+		skipped []string
+	)
+
+	for i, n := range nodes {
+		if len(set.Replicas) == q.N {
+			break
+		}
+
+		if i < q.N {
+			set.Preference = append(set.Preference, n)
+		}
+
+		if q.Skip != nil && q.Skip(n) {
+			if i < q.N {
+				skipped = append(skipped, n.ID)
+			}
+			continue
+		}
+
+		if i >= q.N && len(skipped) > 0 {
+			if set.Hints == nil {
+				set.Hints = make(map[string]string, len(skipped))
+			}
+			set.Hints[n.ID], skipped = skipped[0], skipped[1:]
+		}
+		set.Replicas = append(set.Replicas, n)
+	}
+
+	if len(set.Replicas) < q.R || len(set.Replicas) < q.W {
+		return set, fmt.Errorf("%w: %d of %d replicas available for R=%d, W=%d",
+			ErrNoQuorum, len(set.Replicas), q.N, q.R, q.W)
+	}
+
+	set.Read = set.Replicas[:q.R:q.R]
+	set.Write = set.Replicas[:q.W:q.W]
+	return set, nil
+}
@@ -0,0 +1,62 @@
+package hrw
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestQuorum(t *testing.T) {
+	r := NewRing(testNodes(6)...)
+	order := nodeIDs(r.GetN(testKey, 6))
+
+	t.Run("all reachable", func(t *testing.T) {
+		set, err := r.Quorum(testKey, Quorum{N: 3, R: 2, W: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if actual := nodeIDs(set.Replicas); !reflect.DeepEqual(actual, order[:3]) {
+			t.Errorf("Was %#v, but expected %#v", actual, order[:3])
+		}
+		if actual := nodeIDs(set.Read); !reflect.DeepEqual(actual, order[:2]) {
+			t.Errorf("Was %#v, but expected %#v", actual, order[:2])
+		}
+		if set.Hints != nil {
+			t.Errorf("Was %#v, but expected no hints", set.Hints)
+		}
+	})
+
+	t.Run("skip with hints", func(t *testing.T) {
+		skip := func(n Node) bool { return n.ID == order[1] || n.ID == order[3] }
+		set, err := r.Quorum(testKey, Quorum{N: 3, R: 1, W: 3, Skip: skip})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if actual := nodeIDs(set.Preference); !reflect.DeepEqual(actual, order[:3]) {
+			t.Errorf("Was %#v, but expected %#v", actual, order[:3])
+		}
+
+		expect := []string{order[0], order[2], order[4]}
+		if actual := nodeIDs(set.Write); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+
+		hints := map[string]string{order[4]: order[1]}
+		if !reflect.DeepEqual(set.Hints, hints) {
+			t.Errorf("Was %#v, but expected %#v", set.Hints, hints)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := r.Quorum(testKey, Quorum{N: 3, R: 4, W: 1}); !errors.Is(err, ErrInvalidQuorum) {
+			t.Errorf("Was %#v, but expected %#v", err, ErrInvalidQuorum)
+		}
+
+		skip := func(n Node) bool { return n.ID != order[0] }
+		if _, err := r.Quorum(testKey, Quorum{N: 3, R: 1, W: 2, Skip: skip}); !errors.Is(err, ErrNoQuorum) {
+			t.Errorf("Was %#v, but expected %#v", err, ErrNoQuorum)
+		}
+	})
+}
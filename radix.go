@@ -0,0 +1,77 @@
+package hrw
+
+import "sort"
+
+// radixThreshold is count of nodes from which radix sort
+// outperforms comparison sort
+const radixThreshold = 256
+
+// sort orders h like sort.Sort(h) does, indexes in h.sorted
+// must be in ascending order. Buffer of radix sort is allocated
+// when it's shorter than h.
+func (h hashed) sort(buffer []int32) {
+	if h.length < radixThreshold {
+		sort.Sort(h)
+		return
+	}
+
+	if len(buffer) < h.length {
+		buffer = make([]int32, h.length)
+	}
+
+	radixSort(h.sorted, h.weight, buffer[:h.length])
+	if h.nodes == nil {
+		return
+	}
+
+	// radix sort is stable, so only ties of weights have to be ordered
+	// by nodes, indexes are ordered already
+	for i := 0; i < h.length; {
+		j := i + 1
+		for j < h.length && h.weight[h.sorted[j]] == h.weight[h.sorted[i]] {
+			j++
+		}
+
+		if run := h.sorted[i:j]; len(run) > 1 {
+			sort.SliceStable(run, func(a, b int) bool { return h.nodes[run[a]] < h.nodes[run[b]] })
+		}
+		i = j
+	}
+}
+
+// radixSort stably orders indexes by keys[index] using LSD radix sort
+// by bytes, passes over bytes equal for all keys are skipped.
+// Buffer must have length of indexes.
+func radixSort(indexes []int32, keys []uint64, buffer []int32) {
+	var (
+		src = indexes
+		dst = buffer
+	)
+
+	for shift := uint(0); shift < 64; shift += 8 {
+		var counts [256]int
+		for _, i := range src {
+			counts[byte(keys[i]>>shift)]++
+		}
+
+		if counts[byte(keys[src[0]]>>shift)] == len(src) {
+			continue
+		}
+
+		offset := 0
+		for b := range counts {
+			offset, counts[b] = offset+counts[b], offset
+		}
+
+		for _, i := range src {
+			b := byte(keys[i] >> shift)
+			dst[counts[b]] = i
+			counts[b]++
+		}
+		src, dst = dst, src
+	}
+
+	if &src[0] != &indexes[0] {
+		copy(indexes, src)
+	}
+}
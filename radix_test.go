@@ -0,0 +1,41 @@
+package hrw
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestRadixSort(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{radixThreshold, 1000, 5000} {
+		for _, mod := range []uint64{0, 16, 1 << 20} {
+			nodes := make([]uint64, n)
+			for i := range nodes {
+				nodes[i] = rnd.Uint64()
+			}
+
+			var (
+				hash   = rnd.Uint64()
+				fn     = MixMurmur3
+				expect = sortByWeightFunc(nodes, hash, fn)
+			)
+
+			if mod != 0 {
+				// few distinct weights produce ties
+				fn = func(node, hash uint64) uint64 { return weight(node, hash) % mod }
+				expect = sortByWeightFunc(nodes, hash, fn)
+			}
+
+			sort.Sort(expect)
+			if actual := SortByWeightFunc(nodes, hash, fn); !reflect.DeepEqual(actual, expect.order(nil)) {
+				t.Errorf("Radix sort of %d nodes mod %d differs from sort.Sort", n, mod)
+			}
+		}
+	}
+}
+
+func BenchmarkSortByWeight_10000(b *testing.B) {
+	_ = benchmarkSortByWeight(b, 10000, Hash(testKey))
+}
@@ -0,0 +1,106 @@
+package hrw
+
+import "time"
+
+type (
+	// Move is a movement of key between nodes
+	Move struct {
+		Key      []byte
+		From, To string
+		// Size of key data in bytes, used by Budget.Bytes
+		Size int64
+	}
+
+	// Budget limits movements per Interval, zero limits are unlimited
+	Budget struct {
+		Interval time.Duration
+		// Bytes is maximum total size of moves
+		Bytes int64
+		// Moves is maximum count of moves
+		Moves int
+		// PerNode is maximum count of moves from or to any node
+		PerNode int
+	}
+
+	// Batch is a set of moves to execute at Start from beginning of plan
+	Batch struct {
+		Start time.Duration
+		Moves []Move
+	}
+)
+
+// Moves returns movements of keys whose most preferable node differs
+// between from and to. Keys without node in one of snapshots have empty
+// From or To.
+func Moves(from, to *Snapshot, keys [][]byte) []Move {
+	var result []Move
+	for _, key := range keys {
+		a, _ := from.Get(key)
+		b, _ := to.Get(key)
+		if a.ID != b.ID {
+			result = append(result, Move{Key: key, From: a.ID, To: b.ID})
+		}
+	}
+	return result
+}
+
+// Plan schedules moves into batches respecting budget, every move is
+// placed into the earliest batch it fits in, so relative order of moves
+// is kept unless limits force otherwise. Move exceeding Budget.Bytes
+// alone gets a batch of its own.
+func Plan(moves []Move, b Budget) []Batch {
+	var (
+		batches []Batch
+		stats   []batchStats
+	)
+
+	for _, m := range moves {
+		i := len(batches)
+		for j := range stats {
+			if stats[j].fits(m, b) {
+				i = j
+				break
+			}
+		}
+
+		if i == len(batches) {
+			batches = append(batches, Batch{Start: time.Duration(i) * b.Interval})
+			stats = append(stats, batchStats{nodes: make(map[string]int)})
+		}
+
+		batches[i].Moves = append(batches[i].Moves, m)
+		stats[i].add(m)
+	}
+	return batches
+}
+
+type batchStats struct {
+	bytes int64
+	nodes map[string]int
+	count int
+}
+
+func (s *batchStats) fits(m Move, b Budget) bool {
+	switch {
+	case s.count == 0:
+		return true
+	case b.Moves > 0 && s.count >= b.Moves:
+		return false
+	case b.Bytes > 0 && s.bytes+m.Size > b.Bytes:
+		return false
+	case b.PerNode > 0 && (s.nodes[m.From] >= b.PerNode || s.nodes[m.To] >= b.PerNode):
+		return false
+	}
+	return true
+}
+
+func (s *batchStats) add(m Move) {
+	s.count++
+	s.bytes += m.Size
+	if m.From != "" {
+		s.nodes[m.From]++
+	}
+	if m.To != "" {
+		s.nodes[m.To]++
+	}
+}
@@ -0,0 +1,61 @@
+package hrw
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMoves(t *testing.T) {
+	var (
+		r    = NewRing(testNodes(5)...)
+		from = r.Snapshot()
+		keys = make([][]byte, 0, 1000)
+	)
+
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, []byte("key-"+strconv.Itoa(i)))
+	}
+
+	r.Remove("node-0")
+	moves := Moves(from, r.Snapshot(), keys)
+	if len(moves) == 0 {
+		t.Fatal("Expected some keys to move")
+	}
+
+	for _, m := range moves {
+		if m.From != "node-0" {
+			t.Errorf("Was %q, but expected %q", m.From, "node-0")
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	moves := []Move{
+		{From: "a", To: "b", Size: 5},
+		{From: "a", To: "c", Size: 5},
+		{From: "b", To: "c", Size: 20},
+		{From: "c", To: "d", Size: 1},
+	}
+
+	t.Run("unlimited", func(t *testing.T) {
+		expect := []Batch{{Moves: moves}}
+		if actual := Plan(moves, Budget{}); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		actual := Plan(moves, Budget{Interval: time.Second, Bytes: 10, PerNode: 1})
+		expect := []Batch{
+			{Start: 0, Moves: []Move{moves[0], moves[3]}},
+			{Start: time.Second, Moves: []Move{moves[1]}},
+			{Start: 2 * time.Second, Moves: []Move{moves[2]}},
+		}
+
+		if !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+}
@@ -0,0 +1,141 @@
+// Package rediscluster places hash slots of Redis Cluster on nodes by HRW,
+// so proxies don't reimplement slot placement. Every slot is owned by the
+// most preferable node for it, following nodes are it's replicas. Changes
+// of membership move only slots of changed nodes, see Diff.
+package rediscluster
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/im-kulikov/hrw"
+)
+
+// SlotCount is count of hash slots of Redis Cluster
+const SlotCount = 16384
+
+type (
+	// Assignment is a master of slot followed by it's replicas
+	Assignment struct {
+		Master   string
+		Replicas []string
+	}
+
+	// Table holds Assignment of every slot, index is slot
+	Table []Assignment
+
+	// SlotRange is contiguous range of slots with the same Assignment
+	SlotRange struct {
+		// Start is the first slot of range
+		Start uint16
+		// End is the last slot of range, inclusive
+		End uint16
+		Assignment
+	}
+
+	// Change is a slot which Assignment differs between tables
+	Change struct {
+		Slot uint16
+		From Assignment
+		To   Assignment
+	}
+)
+
+// Slot returns hash slot of key like Redis Cluster does: CRC16 of key or of
+// it's hash tag, the first non-empty substring between '{' and '}'
+func Slot(key []byte) uint16 {
+	if i := bytes.IndexByte(key, '{'); i >= 0 {
+		if j := bytes.IndexByte(key[i+1:], '}'); j > 0 {
+			key = key[i+1 : i+1+j]
+		}
+	}
+	return crc16(key) % SlotCount
+}
+
+// Map returns Table of slots placed on nodes of s, every slot has up to
+// replicas replicas. Slots have no master when s has no active nodes.
+func Map(s *hrw.Snapshot, replicas int) Table {
+	t := make(Table, SlotCount)
+
+	var key [2]byte
+	for slot := range t {
+		binary.BigEndian.PutUint16(key[:], uint16(slot))
+
+		nodes := s.GetN(key[:], replicas+1)
+		if len(nodes) == 0 {
+			continue
+		}
+
+		t[slot].Master = nodes[0].ID
+		for _, n := range nodes[1:] {
+			t[slot].Replicas = append(t[slot].Replicas, n.ID)
+		}
+	}
+	return t
+}
+
+// Get returns Assignment of key
+func (t Table) Get(key []byte) Assignment {
+	return t[Slot(key)]
+}
+
+// Ranges returns contiguous ranges of slots with the same Assignment,
+// e.g. to answer CLUSTER SLOTS
+func (t Table) Ranges() []SlotRange {
+	var result []SlotRange
+	for slot, a := range t {
+		if n := len(result); n > 0 && result[n-1].Assignment.equal(a) {
+			result[n-1].End = uint16(slot)
+			continue
+		}
+		result = append(result, SlotRange{Start: uint16(slot), End: uint16(slot), Assignment: a})
+	}
+	return result
+}
+
+// Diff returns slots which Assignment is changed by next in slot order,
+// it's the minimal reassignment from t to next
+func (t Table) Diff(next Table) []Change {
+	var result []Change
+	for slot := range t {
+		if !t[slot].equal(next[slot]) {
+			result = append(result, Change{Slot: uint16(slot), From: t[slot], To: next[slot]})
+		}
+	}
+	return result
+}
+
+// MasterMoved reports whether Change moves slot to another master,
+// otherwise only replicas are changed
+func (c Change) MasterMoved() bool {
+	return c.From.Master != c.To.Master
+}
+
+func (a Assignment) equal(b Assignment) bool {
+	if a.Master != b.Master || len(a.Replicas) != len(b.Replicas) {
+		return false
+	}
+
+	for i := range a.Replicas {
+		if a.Replicas[i] != b.Replicas[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// crc16 is CRC16-CCITT (XMODEM) used by Redis Cluster
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
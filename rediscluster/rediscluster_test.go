@@ -0,0 +1,86 @@
+package rediscluster
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/im-kulikov/hrw"
+)
+
+func testRing(n int) *hrw.Ring {
+	r := hrw.NewRing()
+	for i := 0; i < n; i++ {
+		r.Add(hrw.Node{ID: "node-" + strconv.Itoa(i)})
+	}
+	return r
+}
+
+func TestSlot(t *testing.T) {
+	if crc := crc16([]byte("123456789")); crc != 0x31c3 {
+		t.Errorf("Was %#x, but expected %#x", crc, 0x31c3)
+	}
+
+	cases := []struct {
+		key    string
+		expect uint16
+	}{
+		{key: "foo", expect: 12182},
+		{key: "{user1000}.following", expect: Slot([]byte("user1000"))},
+		{key: "{}.following", expect: crc16([]byte("{}.following")) % SlotCount},
+		{key: "foo{}{bar}", expect: crc16([]byte("foo{}{bar}")) % SlotCount},
+	}
+
+	for _, tc := range cases {
+		if actual := Slot([]byte(tc.key)); actual != tc.expect {
+			t.Errorf("%s: Was %d, but expected %d", tc.key, actual, tc.expect)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	r := testRing(4)
+	table := Map(r.Snapshot(), 1)
+
+	for slot, a := range table {
+		if a.Master == "" || len(a.Replicas) != 1 || a.Replicas[0] == a.Master {
+			t.Fatalf("Was %#v for slot %d, but expected master and replica", a, slot)
+		}
+	}
+
+	var covered int
+	for _, rng := range table.Ranges() {
+		covered += int(rng.End-rng.Start) + 1
+	}
+	if covered != SlotCount {
+		t.Errorf("Was %d, but expected %d", covered, SlotCount)
+	}
+
+	if a := table.Get([]byte("foo")); a.Master != table[12182].Master {
+		t.Errorf("Was %q, but expected %q", a.Master, table[12182].Master)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	r := testRing(4)
+	before := Map(r.Snapshot(), 0)
+
+	r.Remove("node-0")
+	changes := before.Diff(Map(r.Snapshot(), 0))
+
+	var owned int
+	for _, a := range before {
+		if a.Master == "node-0" {
+			owned++
+		}
+	}
+
+	if len(changes) != owned {
+		t.Errorf("Was %d, but expected %d", len(changes), owned)
+	}
+
+	for _, c := range changes {
+		if c.From.Master != "node-0" || !c.MasterMoved() {
+			t.Fatalf("Was %#v, but expected only slots of node-0 to move", c)
+		}
+	}
+}
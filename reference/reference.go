@@ -0,0 +1,163 @@
+// Package reference is a deliberately simple implementation of hrw
+// ranking without reflection and fast paths. It's slow, but short enough
+// to be checked by reading, so optimized code of hrw is differentially
+// tested against it and ports of hrw to other languages can be validated.
+package reference
+
+import (
+	"math"
+	"sort"
+)
+
+// Hash returns 64-bit MurmurHash3 of data: the first half of
+// x64 128-bit MurmurHash3 with zero seed
+func Hash(data []byte) uint64 {
+	const (
+		c1 = 0x87c37b91114253d5
+		c2 = 0x4cf5ad432745937f
+	)
+
+	var h1, h2 uint64
+	blocks := len(data) / 16
+	for i := 0; i < blocks; i++ {
+		k1 := littleEndian(data[i*16 : i*16+8])
+		k2 := littleEndian(data[i*16+8 : i*16+16])
+
+		h1 ^= rotl(k1*c1, 31) * c2
+		h1 = rotl(h1, 27) + h2
+		h1 = h1*5 + 0x52dce729
+
+		h2 ^= rotl(k2*c2, 33) * c1
+		h2 = rotl(h2, 31) + h1
+		h2 = h2*5 + 0x38495ab5
+	}
+
+	tail := data[blocks*16:]
+	if len(tail) > 8 {
+		h2 ^= rotl(littleEndian(tail[8:])*c2, 33) * c1
+	}
+	if len(tail) > 0 {
+		k1 := tail
+		if len(k1) > 8 {
+			k1 = k1[:8]
+		}
+		h1 ^= rotl(littleEndian(k1)*c1, 31) * c2
+	}
+
+	h1 ^= uint64(len(data))
+	h2 ^= uint64(len(data))
+	h1 += h2
+	h2 += h1
+	h1 = Fmix64(h1)
+	h2 = Fmix64(h2)
+	return h1 + h2
+}
+
+// Fmix64 is a 64-bit finalizer of MurmurHash3
+func Fmix64(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
+
+// Weight returns weight of node for key hash, the lowest weight wins
+func Weight(node, hash uint64) uint64 {
+	return Fmix64(node ^ hash)
+}
+
+// SortByWeight returns indexes of nodes ordered like hrw.SortByWeight:
+// by weight, then by node value and then by index
+func SortByWeight(nodes []uint64, hash uint64) []uint64 {
+	order := make([]int, len(nodes))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := nodes[order[i]], nodes[order[j]]
+		if Weight(a, hash) != Weight(b, hash) {
+			return Weight(a, hash) < Weight(b, hash)
+		}
+		return a < b
+	})
+
+	result := make([]uint64, len(order))
+	for i := range order {
+		result[i] = uint64(order[i])
+	}
+	return result
+}
+
+// SortStrings returns copy of values ordered like hrw.SortSliceByValue
+// orders []string: value is weighted twice, then ties are broken like
+// SortByWeight does
+func SortStrings(values []string, hash uint64) []string {
+	rule := make([]uint64, 0, len(values))
+	for _, v := range values {
+		rule = append(rule, Weight(hash, Hash([]byte(v))))
+	}
+
+	result := make([]string, 0, len(values))
+	for _, i := range SortByWeight(rule, hash) {
+		result = append(result, values[i])
+	}
+	return result
+}
+
+// Rank returns IDs of active nodes ordered for key like hrw.Ring with V1
+// algorithm does, weights[i] is weight of ids[i], values <= 0 are
+// treated as 1. IDs must be unique.
+func Rank(ids []string, weights []float64, key []byte) []string {
+	type node struct {
+		id    string
+		raw   uint64
+		score float64
+	}
+
+	hash := Hash(key)
+	nodes := make([]node, 0, len(ids))
+	for i, id := range ids {
+		w := weights[i]
+		if w <= 0 {
+			w = 1
+		}
+
+		// the same weight as SortStrings uses
+		raw := Weight(Weight(hash, Hash([]byte(id))), hash)
+		u := (float64(raw>>11) + 0.5) / (1 << 53)
+		nodes = append(nodes, node{id: id, raw: raw, score: -math.Log1p(-u) / w})
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		switch {
+		case a.score != b.score:
+			return a.score < b.score
+		case a.raw != b.raw:
+			return a.raw < b.raw
+		default:
+			return a.id < b.id
+		}
+	})
+
+	result := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		result = append(result, n.id)
+	}
+	return result
+}
+
+func littleEndian(b []byte) uint64 {
+	var v uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	return v
+}
+
+func rotl(v uint64, n uint) uint64 {
+	return v<<n | v>>(64-n)
+}
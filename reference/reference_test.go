@@ -0,0 +1,79 @@
+package reference_test
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/im-kulikov/hrw"
+	"github.com/im-kulikov/hrw/reference"
+)
+
+func TestHash(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for size := 0; size < 100; size++ {
+		data := make([]byte, size)
+		rnd.Read(data)
+
+		if actual, expect := reference.Hash(data), hrw.Hash(data); actual != expect {
+			t.Errorf("Was %d, but expected %d for %d bytes", actual, expect, size)
+		}
+	}
+}
+
+func TestSortByWeight(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		nodes := make([]uint64, rnd.Intn(20))
+		for j := range nodes {
+			// small values produce ties
+			nodes[j] = uint64(rnd.Intn(8))
+		}
+
+		hash := rnd.Uint64()
+		if actual, expect := reference.SortByWeight(nodes, hash), hrw.SortByWeight(nodes, hash); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	}
+}
+
+func TestSortStrings(t *testing.T) {
+	values := []string{"a", "b", "c", "d", "e", "f"}
+	hash := hrw.Hash([]byte("key"))
+
+	expect := append([]string(nil), values...)
+	hrw.SortSliceByValue(expect, hash)
+
+	if actual := reference.SortStrings(values, hash); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
+
+func TestRank(t *testing.T) {
+	var (
+		ids     = make([]string, 0, 10)
+		weights = make([]float64, 0, 10)
+		nodes   = make([]hrw.Node, 0, 10)
+	)
+
+	for i := 0; i < cap(ids); i++ {
+		id, w := "node-"+strconv.Itoa(i), float64(i%3)
+		ids, weights = append(ids, id), append(weights, w)
+		nodes = append(nodes, hrw.Node{ID: id, Weight: w})
+	}
+
+	r := hrw.NewRing(nodes...)
+	for i := 0; i < 100; i++ {
+		key := []byte("key-" + strconv.Itoa(i))
+
+		var expect []string
+		for _, n := range r.GetN(key, len(nodes)) {
+			expect = append(expect, n.ID)
+		}
+
+		if actual := reference.Rank(ids, weights, key); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	}
+}
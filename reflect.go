@@ -0,0 +1,127 @@
+//go:build !tinygo && !hrw_noreflect
+// +build !tinygo,!hrw_noreflect
+
+package hrw
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var hasherType = reflect.TypeOf((*Hasher)(nil)).Elem()
+
+// sliceSwapper returns swapper and length of any slice
+func sliceSwapper(slice interface{}) (swapper, int, bool) {
+	if swap, length, ok := knownSwapper(slice); ok {
+		return swap, length, true
+	}
+
+	val := reflect.ValueOf(slice)
+	if val.Kind() != reflect.Slice {
+		return nil, 0, false
+	}
+
+	return reflect.Swapper(slice), val.Len(), true
+}
+
+// sliceHashers returns accessor of elements of any slice, when it's elements
+// implement Hasher (for interface slices every non-nil element is checked),
+// accessor returns nil for nil elements
+func sliceHashers(slice interface{}) (func(i int) Hasher, error) {
+	val := reflect.ValueOf(slice)
+	if val.Kind() != reflect.Slice {
+		return nil, notSlice(slice)
+	}
+
+	at := func(i int) Hasher {
+		if v := val.Index(i); !isNil(v) {
+			return v.Interface().(Hasher)
+		}
+		return nil
+	}
+
+	switch elem := val.Type().Elem(); {
+	case elem.Implements(hasherType):
+		return at, nil
+	case elem.Kind() != reflect.Interface:
+		return nil, unsupportedElement(slice)
+	}
+
+	for i := 0; i < val.Len(); i++ {
+		if v := val.Index(i); !isNil(v) {
+			if _, ok := v.Interface().(Hasher); !ok {
+				return nil, unsupportedElementAt(slice, i, v.Interface())
+			}
+		}
+	}
+	return at, nil
+}
+
+// mapRange iterates over any map with string keys
+func mapRange(m interface{}, fn func(key string, value func() interface{})) error {
+	if knownMapRange(m, fn) {
+		return nil
+	}
+
+	val := reflect.ValueOf(m)
+	if val.Kind() != reflect.Map || val.Type().Key().Kind() != reflect.String {
+		return notMap(m)
+	}
+
+	for iter := val.MapRange(); iter.Next(); {
+		fn(iter.Key().String(), iter.Value().Interface)
+	}
+	return nil
+}
+
+// structKey appends fields of struct tagged `hrw:"key"` to k
+func structKey(v interface{}, k *Key) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Ptr && !val.IsNil() {
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return notStruct(v)
+	}
+
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).Tag.Get("hrw") != "key" {
+			continue
+		}
+
+		switch f := val.Field(i); f.Kind() {
+		case reflect.String:
+			k.AddString(f.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			k.AddInt64(f.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			k.AddUint64(f.Uint())
+		case reflect.Bool:
+			k.AddBool(f.Bool())
+		case reflect.Slice, reflect.Array:
+			if f.Type().Elem().Kind() != reflect.Uint8 {
+				return fmt.Errorf("%w: field %s of %T is %s", ErrUnsupportedField, typ.Field(i).Name, v, f.Type())
+			}
+
+			buf := make([]byte, f.Len())
+			reflect.Copy(reflect.ValueOf(buf), f)
+			k.AddBytes(buf)
+		default:
+			return fmt.Errorf("%w: field %s of %T is %s", ErrUnsupportedField, typ.Field(i).Name, v, f.Type())
+		}
+	}
+	return nil
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface:
+		return v.IsNil() || isNil(v.Elem())
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !tinygo && !hrw_noreflect
+// +build !tinygo,!hrw_noreflect
+
+package hrw
+
+import (
+	"reflect"
+	"testing"
+)
+
+// withReflect reports whether slices of any type are supported
+const withReflect = true
+
+func TestSortSliceByValueHasher(t *testing.T) {
+	actual := []hashString{"a", "b", "c", "d", "e", "f"}
+	expect := []hashString{"d", "b", "a", "f", "c", "e"}
+	hash := Hash(testKey)
+	SortSliceByValue(actual, hash)
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
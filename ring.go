@@ -0,0 +1,411 @@
+package hrw
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// State of the node inside Ring
+	State uint8
+
+	// Node is a member of Ring
+	Node struct {
+		// ID uniquely identifies node, it's hash used to calculate weights
+		ID string
+		// Weight is a relative capacity of node, values <= 0 treated as 1
+		Weight float64
+		// State of node, only active nodes are selected
+		State State
+		// Attrs are named numeric attributes of node (capacity, price, etc.)
+		// used by CostFunc to calculate weight
+		Attrs map[string]float64
+		// Physical is ID of physical node for virtual one, see VirtualNodes
+		Physical string
+		// Tier is priority of node, nodes of lower tier are selected first,
+		// so higher tiers receive keys only when lower ones are exhausted
+		Tier int
+		// Group is anti-affinity group of node (e.g. hypervisor), nodes of
+		// the same group are selected together only when it's unavoidable
+		Group string
+		// Datacenter of node, see GetReplicas
+		Datacenter string
+		// Location is path of node in topology from the top level,
+		// e.g. region, zone and rack, see SetTopology
+		Location []string
+		// Labels are named string attributes of node used by policies
+		Labels map[string]string
+	}
+
+	// Ring holds membership view and selects nodes for keys.
+	// It's safe for concurrent use: changes copy the view and publish it
+	// atomically, so readers never take a lock.
+	Ring struct {
+		mu         sync.Mutex
+		state      atomic.Value
+		tombstones map[string]uint64
+		subs       map[chan Event]struct{}
+		leases     map[string]*lease
+		expiry     ExpiryAction
+		now        func() time.Time
+		hooks      []func(Transition)
+		halfLife   time.Duration
+		ewma       map[string]ewma
+	}
+
+	member struct {
+		Node
+		hash uint64
+		seq  uint64
+	}
+
+	candidate struct {
+		index int
+		raw   uint64
+		score float64
+		tier  int
+		// gray candidates follow all others
+		gray bool
+	}
+)
+
+const (
+	// StateActive marks node as eligible for selection
+	StateActive State = iota
+	// StateDown marks node as excluded from selection
+	StateDown
+	// StateGray marks node as selected only after all active nodes
+	StateGray
+	// StateJoining marks node being prepared to serve, it isn't selected
+	StateJoining
+	// StateDraining marks node being emptied, it's selected only after
+	// all active nodes, so keys gradually leave it
+	StateDraining
+)
+
+// NewRing creates Ring with given nodes
+func NewRing(nodes ...Node) *Ring {
+	r := new(Ring)
+	r.Add(nodes...)
+	return r
+}
+
+// Add adds nodes into Ring, nodes with known ID are replaced
+func (r *Ring) Add(nodes ...Node) {
+	r.update(func(s *Snapshot) bool {
+		for _, n := range nodes {
+			s.nodes = upsertMember(s.nodes, n, s.hashFn)
+		}
+		return true
+	})
+}
+
+// Remove removes nodes with given IDs from Ring
+func (r *Ring) Remove(ids ...string) {
+	r.update(func(s *Snapshot) bool {
+		for _, id := range ids {
+			s.nodes = removeMember(s.nodes, id)
+			delete(r.leases, id)
+		}
+		return true
+	})
+}
+
+// Nodes returns copy of Ring members ordered by ID
+func (r *Ring) Nodes() []Node {
+	return r.view().Nodes()
+}
+
+// Len returns count of Ring members
+func (r *Ring) Len() int {
+	return r.view().Len()
+}
+
+// Get returns most preferable active node for key
+func (r *Ring) Get(key []byte) (Node, bool) {
+	return r.view().Get(key)
+}
+
+// GetN returns up to n active nodes for key in order of preference
+func (r *Ring) GetN(key []byte, n int) []Node {
+	return r.view().GetN(key, n)
+}
+
+// SetHash replaces function used by Ring to hash keys and node IDs,
+// Hash used by default
+func (r *Ring) SetHash(fn HashFunc) {
+	r.update(func(s *Snapshot) bool {
+		s.hashFn = fn
+		for i := range s.nodes {
+			s.nodes[i].hash = fn.hashString(s.nodes[i].ID)
+		}
+		return true
+	})
+}
+
+// Hash returns hash of key used by Ring to select nodes
+func (r *Ring) Hash(key []byte) uint64 {
+	return r.view().Hash(key)
+}
+
+func (r *Ring) pick(hash uint64, n int) []Node {
+	return r.view().pick(hash, n)
+}
+
+// Version returns counter of Ring changes, it's incremented by every
+// change of membership or settings affecting selection
+func (r *Ring) Version() uint64 {
+	return r.view().Version()
+}
+
+// Generation returns count of views published by Ring. Unlike Version
+// it's incremented by every change including ones not affecting selection,
+// so it helps to debug how often readers observe new views.
+func (r *Ring) Generation() uint64 {
+	return r.view().Generation()
+}
+
+// Snapshot returns immutable view of current membership and settings
+// of Ring, it isn't affected by following changes of Ring
+func (r *Ring) Snapshot() *Snapshot {
+	return r.view()
+}
+
+// SetAlgorithm sets algorithm used by Ring to order nodes, V1 by default
+func (r *Ring) SetAlgorithm(alg Algorithm) {
+	r.update(func(s *Snapshot) bool {
+		s.alg = alg
+		return true
+	})
+}
+
+// view returns current view of Ring
+func (r *Ring) view() *Snapshot {
+	if s, ok := r.state.Load().(*Snapshot); ok {
+		return s
+	}
+	return emptyState
+}
+
+// update applies fn to copy of current view and publishes it,
+// version is incremented when fn reports change affecting selection
+func (r *Ring) update(fn func(s *Snapshot) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.updateLocked(fn)
+}
+
+// updateLocked is like update, but r.mu must be held by caller
+func (r *Ring) updateLocked(fn func(s *Snapshot) bool) {
+	old := r.view()
+	s := *old
+	s.now = r.now
+	s.nodes = append(make([]member, 0, len(s.nodes)), s.nodes...)
+	s.gen++
+	if fn(&s) {
+		s.version++
+	}
+	if s.version != old.version {
+		s.history = appendHistory(old, s.keep)
+	}
+	r.state.Store(&s)
+
+	if len(r.subs) > 0 && s.version != old.version {
+		r.notify(diffSnapshots(old, &s))
+	}
+}
+
+func first(nodes []Node) (Node, bool) {
+	if len(nodes) == 0 {
+		return Node{}, false
+	}
+	return nodes[0], true
+}
+
+// Checksum returns hash of membership view (every field of every node) made
+// by Ring hash, peers with equal checksums and settings select equal nodes
+// for any key
+func (r *Ring) Checksum() uint64 {
+	return r.view().Checksum()
+}
+
+func (n Node) weight() float64 {
+	if n.Weight <= 0 {
+		return 1
+	}
+	return n.Weight
+}
+
+func newMember(n Node, fn HashFunc) member {
+	return member{Node: n, hash: fn.hashString(n.ID)}
+}
+
+func findMember(nodes []member, id string) (int, bool) {
+	i := sort.Search(len(nodes), func(i int) bool { return nodes[i].ID >= id })
+	return i, i < len(nodes) && nodes[i].ID == id
+}
+
+func upsertMember(nodes []member, n Node, fn HashFunc) []member {
+	i, ok := findMember(nodes, n.ID)
+	if ok {
+		seq := nodes[i].seq
+		nodes[i] = newMember(n, fn)
+		nodes[i].seq = seq
+		return nodes
+	}
+
+	nodes = append(nodes, member{})
+	copy(nodes[i+1:], nodes[i:])
+	nodes[i] = newMember(n, fn)
+	return nodes
+}
+
+func removeMember(nodes []member, id string) []member {
+	i, ok := findMember(nodes, id)
+	if !ok {
+		return nodes
+	}
+
+	copy(nodes[i:], nodes[i+1:])
+	nodes[len(nodes)-1] = member{}
+	return nodes[:len(nodes)-1]
+}
+
+func checksumMembers(nodes []member, fn HashFunc) uint64 {
+	buf := make([]byte, 0, 64)
+	for i := range nodes {
+		n := &nodes[i].Node
+		buf = appendChecksumString(buf, n.ID)
+		buf = appendChecksumUint(buf, math.Float64bits(n.weight()))
+		buf = append(buf, byte(n.State))
+		buf = appendChecksumUint(buf, uint64(int64(n.Tier)))
+		buf = appendChecksumString(buf, n.Group)
+		buf = appendChecksumString(buf, n.Physical)
+		buf = appendChecksumString(buf, n.Datacenter)
+
+		buf = appendChecksumUint(buf, uint64(len(n.Location)))
+		for _, l := range n.Location {
+			buf = appendChecksumString(buf, l)
+		}
+
+		names := make([]string, 0, len(n.Attrs))
+		for name := range n.Attrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		buf = appendChecksumUint(buf, uint64(len(names)))
+		for _, name := range names {
+			buf = appendChecksumString(buf, name)
+			buf = appendChecksumUint(buf, math.Float64bits(n.Attrs[name]))
+		}
+
+		names = names[:0]
+		for name := range n.Labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		buf = appendChecksumUint(buf, uint64(len(names)))
+		for _, name := range names {
+			buf = appendChecksumString(buf, name)
+			buf = appendChecksumString(buf, n.Labels[name])
+		}
+	}
+	return fn.hash(buf)
+}
+
+func appendChecksumUint(buf []byte, v uint64) []byte {
+	var tmp [8]byte
+	binary.BigEndian.PutUint64(tmp[:], v)
+	return append(buf, tmp[:]...)
+}
+
+// appendChecksumString appends length prefixed s
+func appendChecksumString(buf []byte, s string) []byte {
+	return append(appendChecksumUint(buf, uint64(len(s))), s...)
+}
+
+// valueWeight returns weight of value hash, the same as SortSliceByValue
+// uses, so Ring orders nodes like SortSliceByValue orders their IDs.
+func valueWeight(value, hash uint64) uint64 {
+	return weight(weight(hash, value), hash)
+}
+
+// score maps raw weight onto exponential distribution scaled by node weight,
+// so node becomes first with probability proportional to its weight.
+// For equal node weights order of scores is the same as order of raw weights.
+func score(raw uint64, nodeWeight float64) float64 {
+	u := (float64(raw>>11) + 0.5) / (1 << 53)
+	return -math.Log1p(-u) / nodeWeight
+}
+
+func (c candidate) less(o candidate) bool {
+	if c.gray != o.gray {
+		return o.gray
+	}
+	if c.tier != o.tier {
+		return c.tier < o.tier
+	}
+	if c.score != o.score {
+		return c.score < o.score
+	}
+	if c.raw != o.raw {
+		return c.raw < o.raw
+	}
+	return c.index < o.index
+}
+
+// topMembers returns up to n members of best candidates
+func topMembers(nodes []member, list []candidate, n int) []Node {
+	result, _ := topMembersReport(nodes, list, n)
+	return result
+}
+
+// topMembersReport returns up to n members of best candidates respecting
+// anti-affinity groups and reports relaxed constraints
+func topMembersReport(nodes []member, list []candidate, n int) ([]Node, Relaxation) {
+	sort.Slice(list, func(i, j int) bool { return list[i].less(list[j]) })
+
+	if n > len(list) {
+		n = len(list)
+	}
+
+	var (
+		rel    Relaxation
+		result = make([]Node, 0, n)
+		groups map[string]struct{}
+		rest   []int
+	)
+
+	for i, c := range list {
+		if len(result) == n {
+			break
+		}
+
+		if g := nodes[c.index].Group; g != "" {
+			if _, ok := groups[g]; ok {
+				rest = append(rest, i)
+				continue
+			} else if groups == nil {
+				groups = make(map[string]struct{}, n)
+			}
+			groups[g] = struct{}{}
+		}
+		result = append(result, nodes[c.index].Node)
+	}
+
+	for _, i := range rest {
+		if len(result) == n {
+			break
+		}
+
+		rel.Groups++
+		result = append(result, nodes[list[i].index].Node)
+	}
+	return result, rel
+}
@@ -0,0 +1,209 @@
+package hrw
+
+import (
+	"encoding/binary"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func testNodes(n int) []Node {
+	nodes := make([]Node, 0, n)
+	for i := 0; i < n; i++ {
+		nodes = append(nodes, Node{ID: "node-" + strconv.Itoa(i)})
+	}
+	return nodes
+}
+
+func nodeIDs(nodes []Node) []string {
+	ids := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		ids = append(ids, n.ID)
+	}
+	return ids
+}
+
+func TestRingGetN(t *testing.T) {
+	t.Run("same as SortSliceByValue", func(t *testing.T) {
+		actual := NewRing(
+			Node{ID: "a"}, Node{ID: "b"}, Node{ID: "c"},
+			Node{ID: "d"}, Node{ID: "e"}, Node{ID: "f"},
+		).GetN(testKey, 6)
+		expect := []string{"d", "b", "a", "f", "c", "e"}
+		if !reflect.DeepEqual(nodeIDs(actual), expect) {
+			t.Errorf("Was %#v, but expected %#v", nodeIDs(actual), expect)
+		}
+	})
+
+	t.Run("skip inactive nodes", func(t *testing.T) {
+		actual := NewRing(
+			Node{ID: "a"}, Node{ID: "b"}, Node{ID: "c"},
+			Node{ID: "d", State: StateDown}, Node{ID: "e"}, Node{ID: "f"},
+		).GetN(testKey, 3)
+		expect := []string{"b", "a", "f"}
+		if !reflect.DeepEqual(nodeIDs(actual), expect) {
+			t.Errorf("Was %#v, but expected %#v", nodeIDs(actual), expect)
+		}
+	})
+
+	t.Run("empty ring", func(t *testing.T) {
+		if _, ok := NewRing().Get(testKey); ok {
+			t.Errorf("Expected no node for empty ring")
+		}
+	})
+
+	t.Run("weighted", func(t *testing.T) {
+		const keys = 100000
+		var (
+			key    = make([]byte, 8)
+			counts = make(map[string]int)
+			r      = NewRing(Node{ID: "a", Weight: 1}, Node{ID: "b", Weight: 3})
+		)
+
+		for i := uint64(0); i < keys; i++ {
+			binary.BigEndian.PutUint64(key, i)
+			n, _ := r.Get(key)
+			counts[n.ID]++
+		}
+
+		if share := float64(counts["b"]) / keys; share < 0.74 || share > 0.76 {
+			t.Errorf("Node b received %.3f of keys, expected 0.75", share)
+		}
+	})
+}
+
+func TestRingAddRemove(t *testing.T) {
+	r := NewRing(testNodes(3)...)
+	r.Add(Node{ID: "node-1", Weight: 2})
+	r.Remove("node-0", "unknown")
+
+	expect := []Node{{ID: "node-1", Weight: 2}, {ID: "node-2"}}
+	if actual := r.Nodes(); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
+
+func TestRingChecksum(t *testing.T) {
+	nodes := testNodes(10)
+	expect := NewRing(nodes...).Checksum()
+
+	reversed := make([]Node, 0, len(nodes))
+	for i := len(nodes) - 1; i >= 0; i-- {
+		reversed = append(reversed, nodes[i])
+	}
+
+	if actual := NewRing(reversed...).Checksum(); actual != expect {
+		t.Errorf("Was %d, but expected %d", actual, expect)
+	}
+
+	t.Run("weight changed", func(t *testing.T) {
+		r := NewRing(nodes...)
+		r.Add(Node{ID: "node-1", Weight: 2})
+		if r.Checksum() == expect {
+			t.Errorf("Expected checksum to change")
+		}
+	})
+
+	t.Run("state changed", func(t *testing.T) {
+		r := NewRing(nodes...)
+		r.Add(Node{ID: "node-1", State: StateDown})
+		if r.Checksum() == expect {
+			t.Errorf("Expected checksum to change")
+		}
+	})
+
+	t.Run("other fields changed", func(t *testing.T) {
+		for _, n := range []Node{
+			{ID: "node-1", Tier: 1},
+			{ID: "node-1", Group: "rack"},
+			{ID: "node-1", Attrs: map[string]float64{"capacity": 1}},
+			{ID: "node-1", Physical: "host"},
+			{ID: "node-1", Datacenter: "dc"},
+			{ID: "node-1", Location: []string{"eu"}},
+			{ID: "node-1", Location: []string{"eu", ""}},
+			{ID: "node-1", Labels: map[string]string{"disk": "ssd"}},
+			{ID: "node-1", Labels: map[string]string{"disk": ""}},
+		} {
+			r := NewRing(nodes...)
+			r.Add(n)
+			if r.Checksum() == expect {
+				t.Errorf("Expected checksum to change for %#v", n)
+			}
+		}
+	})
+
+	t.Run("default weight", func(t *testing.T) {
+		r := NewRing(nodes...)
+		r.Add(Node{ID: "node-1", Weight: 1})
+		if actual := r.Checksum(); actual != expect {
+			t.Errorf("Was %d, but expected %d", actual, expect)
+		}
+	})
+}
+
+func TestRingVersion(t *testing.T) {
+	r := NewRing(testNodes(3)...)
+	version := r.Version()
+
+	r.GetN(testKey, 3)
+	if actual := r.Version(); actual != version {
+		t.Errorf("Was %d, but expected %d", actual, version)
+	}
+
+	r.Remove("node-0")
+	if r.Version() == version {
+		t.Errorf("Expected version to change")
+	}
+
+	version = r.Version()
+	if r.Apply(Delta{Seq: 0, Op: DeltaWeight, Node: Node{ID: "node-1"}}); r.Version() != version {
+		t.Errorf("Expected version not to change by stale delta")
+	}
+}
+
+func TestRingConcurrent(t *testing.T) {
+	var (
+		r    = NewRing(testNodes(10)...)
+		done = make(chan struct{})
+	)
+
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			r.Add(Node{ID: "extra-" + strconv.Itoa(i%10)})
+			r.Remove("extra-" + strconv.Itoa((i+5)%10))
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		if nodes := r.GetN(testKey, 3); len(nodes) != 3 {
+			t.Fatalf("Was %d nodes, but expected 3", len(nodes))
+		}
+	}
+}
+
+func TestRingReadsDontBlock(t *testing.T) {
+	r := NewRing(testNodes(3)...)
+	gen := r.Generation()
+
+	// writer holding the lock doesn't block readers
+	r.mu.Lock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.GetN(testKey, 3)
+	}()
+	<-done
+	r.mu.Unlock()
+
+	r.SetLoadReporter(nil)
+	if actual := r.Generation(); actual != gen+1 {
+		t.Errorf("Was %d, but expected %d", actual, gen+1)
+	}
+}
@@ -0,0 +1,41 @@
+package hrw
+
+import "sort"
+
+// SampleK deterministically samples up to k indexes of nodes without
+// replacement, every node is selected with probability proportional to
+// it's weight. Every node gets one score for hash, so samples for the same
+// hash are nested: SampleK(..., k) is a prefix of SampleK(..., k+1).
+// Nodes with weights <= 0 are never sampled. When weights have length
+// different from nodes all nodes are weighted equally.
+func SampleK(nodes []uint64, weights []float64, hash uint64, k int) []uint64 {
+	if k <= 0 {
+		return []uint64{}
+	}
+
+	list := make([]candidate, 0, len(nodes))
+	for i, node := range nodes {
+		w := 1.0
+		if len(weights) == len(nodes) {
+			w = weights[i]
+		}
+
+		if w <= 0 {
+			continue
+		}
+
+		raw := weight(node, hash)
+		list = append(list, candidate{index: i, raw: raw, score: score(raw, w)})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].less(list[j]) })
+	if k > len(list) {
+		k = len(list)
+	}
+
+	result := make([]uint64, 0, k)
+	for _, c := range list[:k] {
+		result = append(result, uint64(c.index))
+	}
+	return result
+}
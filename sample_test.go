@@ -0,0 +1,47 @@
+package hrw
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSampleK(t *testing.T) {
+	var (
+		nodes   = []uint64{10, 20, 30, 40}
+		weights = []float64{1, 2, 3, 0}
+		hash    = Hash(testKey)
+	)
+
+	t.Run("nested", func(t *testing.T) {
+		all := SampleK(nodes, weights, hash, 10)
+		if len(all) != 3 {
+			t.Fatalf("Was %#v, expected 3 nodes with positive weights", all)
+		}
+
+		for k := 0; k <= 3; k++ {
+			if actual := SampleK(nodes, weights, hash, k); !reflect.DeepEqual(actual, all[:k]) {
+				t.Errorf("Was %#v, but expected %#v", actual, all[:k])
+			}
+		}
+	})
+
+	t.Run("unweighted", func(t *testing.T) {
+		if actual, expect := SampleK(nodes, nil, hash, 4), SortByWeight(nodes, hash); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+
+	t.Run("proportional", func(t *testing.T) {
+		const keys = 60000
+		counts := make([]int, len(nodes))
+		for i := uint64(0); i < keys; i++ {
+			counts[SampleK(nodes, weights, HashUint64(i), 1)[0]]++
+		}
+
+		for i, expect := range []float64{1.0 / 6, 2.0 / 6, 3.0 / 6, 0} {
+			if share := float64(counts[i]) / keys; share < expect-0.01 || share > expect+0.01 {
+				t.Errorf("Node %d received %.3f of keys, expected %.3f", i, share, expect)
+			}
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package hrw
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// Sessions keeps affinity of sessions to nodes. The first node of
+	// session is selected by Ring, later lookups return it while it stays
+	// selectable and session is used at least once per ttl, so small
+	// changes of Ring don't move sessions. Hooks registered by OnInvalidate
+	// are called for sessions dropped because their node isn't selectable
+	// anymore or by Invalidate and InvalidateNode.
+	Sessions struct {
+		ring  *Ring
+		ttl   time.Duration
+		now   func() time.Time
+		mu    sync.Mutex
+		items map[string]session
+		hooks []func(key []byte, id string)
+	}
+
+	session struct {
+		id      string
+		expires time.Time
+	}
+)
+
+// NewSessions creates Sessions over Ring
+func NewSessions(r *Ring, ttl time.Duration) *Sessions {
+	return &Sessions{
+		ring:  r,
+		ttl:   ttl,
+		now:   time.Now,
+		items: make(map[string]session),
+	}
+}
+
+// Get returns node of session key, false is returned when Ring has no
+// active nodes
+func (c *Sessions) Get(key []byte) (Node, bool) {
+	var (
+		s   = c.ring.view()
+		now = c.now()
+	)
+
+	c.mu.Lock()
+	item, ok := c.items[string(key)]
+	live := ok && now.Before(item.expires)
+	if live {
+		if m, ok := s.activeMember(item.id, s.clock()); ok {
+			c.items[string(key)] = session{id: item.id, expires: now.Add(c.ttl)}
+			c.mu.Unlock()
+			return m.Node, true
+		}
+	}
+
+	n, found := s.Get(key)
+	if found {
+		c.items[string(key)] = session{id: n.ID, expires: now.Add(c.ttl)}
+	} else {
+		delete(c.items, string(key))
+	}
+
+	hooks := c.hooks
+	c.mu.Unlock()
+
+	if live {
+		for _, fn := range hooks {
+			fn(key, item.id)
+		}
+	}
+	return n, found
+}
+
+// Invalidate drops session key, so it's selected again by the next Get
+func (c *Sessions) Invalidate(key []byte) {
+	c.mu.Lock()
+	item, ok := c.items[string(key)]
+	delete(c.items, string(key))
+	hooks := c.hooks
+	c.mu.Unlock()
+
+	if ok {
+		for _, fn := range hooks {
+			fn(key, item.id)
+		}
+	}
+}
+
+// InvalidateNode drops sessions of node, e.g. before it's maintenance,
+// and returns their count
+func (c *Sessions) InvalidateNode(id string) int {
+	var dropped [][]byte
+
+	c.mu.Lock()
+	for key, item := range c.items {
+		if item.id == id {
+			dropped = append(dropped, []byte(key))
+			delete(c.items, key)
+		}
+	}
+	hooks := c.hooks
+	c.mu.Unlock()
+
+	for _, key := range dropped {
+		for _, fn := range hooks {
+			fn(key, id)
+		}
+	}
+	return len(dropped)
+}
+
+// OnInvalidate registers hook called for every invalidated session
+// with ID of it's node
+func (c *Sessions) OnInvalidate(fn func(key []byte, id string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.hooks = append(c.hooks[:len(c.hooks):len(c.hooks)], fn)
+}
+
+// Purge drops expired sessions and returns their count
+func (c *Sessions) Purge() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var (
+		count int
+		now   = c.now()
+	)
+
+	for key, item := range c.items {
+		if !now.Before(item.expires) {
+			delete(c.items, key)
+			count++
+		}
+	}
+	return count
+}
+
+// Len returns count of sessions, including expired ones not purged yet
+func (c *Sessions) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.items)
+}
@@ -0,0 +1,60 @@
+package hrw
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSessions(t *testing.T) {
+	var (
+		key         = []byte("session")
+		now         = time.Unix(0, 0)
+		r           = NewRing(Node{ID: "a"}, Node{ID: "b"})
+		c           = NewSessions(r, time.Minute)
+		invalidated []string
+	)
+
+	c.now = func() time.Time { return now }
+	c.OnInvalidate(func(k []byte, id string) {
+		invalidated = append(invalidated, string(k)+"@"+id)
+	})
+
+	first, ok := c.Get(key)
+	if !ok {
+		t.Fatal("Expected node to be selected")
+	}
+
+	// new preferable node doesn't move session
+	for i := 0; ; i++ {
+		id := "n" + strconv.Itoa(i)
+		r.Add(Node{ID: id, Weight: 1e6})
+		if n, _ := r.Get(key); n.ID == id {
+			break
+		}
+	}
+
+	now = now.Add(30 * time.Second)
+	if n, _ := c.Get(key); n.ID != first.ID {
+		t.Errorf("Was %#v, but expected %#v", n.ID, first.ID)
+	}
+
+	// node leaving moves session and calls hooks
+	r.Remove(first.ID)
+	if n, _ := c.Get(key); n.ID == first.ID {
+		t.Errorf("Expected session to move from %q", first.ID)
+	} else if len(invalidated) != 1 || invalidated[0] != "session@"+first.ID {
+		t.Errorf("Was %#v, but expected one invalidation of %q", invalidated, first.ID)
+	}
+
+	second, _ := c.Get(key)
+	if count := c.InvalidateNode(second.ID); count != 1 {
+		t.Errorf("Was %d, but expected %d", count, 1)
+	}
+
+	c.Get(key)
+	now = now.Add(time.Minute)
+	if count := c.Purge(); count != 1 || c.Len() != 0 {
+		t.Errorf("Was %d (%d left), but expected %d", count, c.Len(), 1)
+	}
+}
@@ -0,0 +1,33 @@
+package hrw
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// HashSHA256 returns first 64 bits of SHA-256 of key. Together with
+// MixSHA256 it may be used by Ring in deployments restricted to
+// FIPS-validated primitives:
+//
+//	r.SetHash(hrw.HashSHA256)
+//	r.SetWeightFunc(hrw.MixSHA256)
+//
+// Then Ring hashes keys, node IDs, salts of epochs and namespaces and
+// Checksum by SHA-256 and mixes them only by MixSHA256. Murmur3 is
+// still used by package-level functions (Hash, SortByWeight,
+// SortSliceByValue, EpochHash), by LookupTable and SkeletonTree,
+// which don't follow WeightFunc of Ring, and by Ring with HashSHA256 alone,
+// which mixes hashes by murmur3 finalizer.
+func HashSHA256(key []byte) uint64 {
+	sum := sha256.Sum256(key)
+	return binary.BigEndian.Uint64(sum[:])
+}
+
+// MixSHA256 is WeightFunc returning first 64 bits of SHA-256 of big endian
+// node and key hashes, see HashSHA256
+func MixSHA256(node, hash uint64) uint64 {
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], node)
+	binary.BigEndian.PutUint64(buf[8:], hash)
+	return HashSHA256(buf[:])
+}
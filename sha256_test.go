@@ -0,0 +1,94 @@
+package hrw
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestHashSHA256(t *testing.T) {
+	// first 8 bytes of sha256("abc")
+	expect := uint64(0xba7816bf8f01cfea)
+	if actual := HashSHA256([]byte("abc")); actual != expect {
+		t.Errorf("Was %x, but expected %x", actual, expect)
+	}
+}
+
+func TestRingSetHashNodes(t *testing.T) {
+	r := NewRing(testNodes(10)...)
+	r.SetHash(HashSHA256)
+
+	nodes := r.view().nodes
+	for i := range nodes {
+		if actual, expect := nodes[i].hash, HashSHA256([]byte(nodes[i].ID)); actual != expect {
+			t.Errorf("Was %d, but expected %d", actual, expect)
+		}
+	}
+
+	r.Add(Node{ID: "new-node"})
+	nodes = r.view().nodes
+	if n, _ := findMember(nodes, "new-node"); nodes[n].hash != HashSHA256([]byte("new-node")) {
+		t.Errorf("Expected new node to be hashed with Ring hash")
+	}
+}
+
+func TestSHA256Salts(t *testing.T) {
+	var (
+		calls int
+		fn    = func(key []byte) uint64 {
+			calls++
+			return HashSHA256(key)
+		}
+		r = NewRing(testNodes(10)...)
+	)
+
+	r.SetHash(fn)
+	r.SetWeightFunc(MixSHA256)
+
+	expect := MixSHA256(HashSHA256(testKey), HashSHA256([]byte("tenant")))
+	if actual := r.Namespace("tenant").Hash(testKey); actual != expect {
+		t.Errorf("Was %d, but expected %d", actual, expect)
+	}
+
+	calls = 0
+	r.GetAtEpoch(testKey, 1)
+	r.Checksum()
+	if calls != 3 {
+		t.Errorf("Was %d calls of Ring hash, but expected %d", calls, 3)
+	}
+
+	if actual := MixSHA256(1, 2); actual == MixSHA256(2, 1) {
+		t.Errorf("Expected MixSHA256 to depend on order of arguments")
+	}
+}
+
+func TestHashSHA256Distribution(t *testing.T) {
+	const (
+		size    = 10
+		keys    = 100000
+		percent = 0.03
+	)
+
+	var (
+		r      = NewRing(testNodes(size)...)
+		counts = make(map[string]int, size)
+		key    = make([]byte, 8)
+	)
+
+	r.SetHash(HashSHA256)
+	for i := uint64(0); i < keys; i++ {
+		binary.BigEndian.PutUint64(key, i)
+		n, _ := r.Get(key)
+		counts[n.ID]++
+	}
+
+	mean := float64(keys) / float64(size)
+	delta := mean * percent
+	for node, count := range counts {
+		if d := mean - float64(count); d > delta || -d > delta {
+			t.Errorf(
+				"Node %s received %d keys, expected %.0f (+/- %.2f)",
+				node, count, mean, delta,
+			)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package hrw
+
+// ShardOf returns stable shard number in [0, nShards) for key. Shards are
+// scored like SortSliceByIndex scores indexes, so when shards are added or
+// removed only keys of changed shards are moved. It panics if nShards <= 0.
+func ShardOf(key []byte, nShards int) int {
+	return ShardOfHash(Hash(key), nShards)
+}
+
+// ShardOfHash is like ShardOf, but receives hash of key
+func ShardOfHash(hash uint64, nShards int) int {
+	if nShards <= 0 {
+		panic("hrw: ShardOf called with non-positive shards count")
+	}
+
+	var (
+		shard int
+		least = weight(0, hash)
+	)
+
+	for i := 1; i < nShards; i++ {
+		if w := weight(uint64(i), hash); w < least {
+			shard, least = i, w
+		}
+	}
+	return shard
+}
@@ -0,0 +1,52 @@
+package hrw
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestShardOf(t *testing.T) {
+	const keys = 10000
+	key := make([]byte, 8)
+
+	for n := 1; n < 20; n++ {
+		shards := make([]int, n)
+		for i := range shards {
+			shards[i] = i
+		}
+
+		SortSliceByIndex(shards, Hash(testKey))
+		if actual := ShardOf(testKey, n); actual != shards[0] {
+			t.Errorf("Was %d, but expected %d", actual, shards[0])
+		}
+	}
+
+	t.Run("minimal movement", func(t *testing.T) {
+		var moved int
+		for i := uint64(0); i < keys; i++ {
+			binary.BigEndian.PutUint64(key, i)
+
+			a, b := ShardOf(key, 10), ShardOf(key, 11)
+			if a != b {
+				moved++
+				if b != 10 {
+					t.Fatalf("Key moved from shard %d to existing shard %d", a, b)
+				}
+			}
+		}
+
+		// about 1/11 of keys must be moved to the new shard
+		if moved < keys/15 || moved > keys/8 {
+			t.Errorf("%d of %d keys moved", moved, keys)
+		}
+	})
+
+	t.Run("non-positive shards", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected panic")
+			}
+		}()
+		ShardOf(testKey, 0)
+	})
+}
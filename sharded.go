@@ -0,0 +1,147 @@
+package hrw
+
+import "sort"
+
+// ShardedRing is a Ring partitioned by node IDs into independently
+// updated shards, so change of one node copies only it's shard instead of
+// whole membership. Use it when membership changes very often. Selection
+// merges best nodes of every shard and orders nodes like Ring does, but
+// views of shards are loaded independently, so concurrent changes of
+// different shards may be observed partially. Anti-affinity groups, pins,
+// lists and penalties aren't supported.
+type ShardedRing struct {
+	shards []*Ring
+}
+
+type shardedCandidate struct {
+	candidate
+	node *member
+}
+
+// NewShardedRing creates ShardedRing of given count of shards with nodes,
+// it panics if shards <= 0
+func NewShardedRing(shards int, nodes ...Node) *ShardedRing {
+	if shards <= 0 {
+		panic("hrw: NewShardedRing called with non-positive shards count")
+	}
+
+	r := &ShardedRing{shards: make([]*Ring, 0, shards)}
+	for i := 0; i < shards; i++ {
+		r.shards = append(r.shards, NewRing())
+	}
+	r.Add(nodes...)
+	return r
+}
+
+// Add adds nodes, nodes with known ID are replaced
+func (r *ShardedRing) Add(nodes ...Node) {
+	groups := make(map[int][]Node)
+	for _, n := range nodes {
+		i := r.shardOf(n.ID)
+		groups[i] = append(groups[i], n)
+	}
+
+	for i, group := range groups {
+		r.shards[i].Add(group...)
+	}
+}
+
+// Remove removes nodes with given IDs
+func (r *ShardedRing) Remove(ids ...string) {
+	groups := make(map[int][]string)
+	for _, id := range ids {
+		i := r.shardOf(id)
+		groups[i] = append(groups[i], id)
+	}
+
+	for i, group := range groups {
+		r.shards[i].Remove(group...)
+	}
+}
+
+// Len returns count of members
+func (r *ShardedRing) Len() int {
+	var n int
+	for _, s := range r.shards {
+		n += s.Len()
+	}
+	return n
+}
+
+// Nodes returns copy of members ordered by ID
+func (r *ShardedRing) Nodes() []Node {
+	var nodes []Node
+	for _, s := range r.shards {
+		nodes = append(nodes, s.Nodes()...)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+// Version returns counter of changes, it's sum of versions of shards
+func (r *ShardedRing) Version() uint64 {
+	var v uint64
+	for _, s := range r.shards {
+		v += s.Version()
+	}
+	return v
+}
+
+// Get returns most preferable active node for key
+func (r *ShardedRing) Get(key []byte) (Node, bool) {
+	return first(r.GetN(key, 1))
+}
+
+// GetN returns up to n active nodes for key in order of preference
+func (r *ShardedRing) GetN(key []byte, n int) []Node {
+	if n <= 0 {
+		return nil
+	}
+
+	var (
+		hash = Hash(key)
+		list []shardedCandidate
+	)
+
+	for _, shard := range r.shards {
+		s := shard.view()
+		best := s.rank(hash)
+		sort.Slice(best, func(i, j int) bool { return best[i].less(best[j]) })
+		if len(best) > n {
+			best = best[:n]
+		}
+
+		for _, c := range best {
+			list = append(list, shardedCandidate{candidate: c, node: &s.nodes[c.index]})
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		// indexes of different shards aren't comparable, IDs are
+		a, b := list[i].candidate, list[j].candidate
+		a.index, b.index = 0, 0
+		switch {
+		case a.less(b):
+			return true
+		case b.less(a):
+			return false
+		default:
+			return list[i].node.ID < list[j].node.ID
+		}
+	})
+
+	if len(list) > n {
+		list = list[:n]
+	}
+
+	result := make([]Node, 0, len(list))
+	for _, c := range list {
+		result = append(result, c.node.Node)
+	}
+	return result
+}
+
+func (r *ShardedRing) shardOf(id string) int {
+	return ShardOfHash(HashString(id), len(r.shards))
+}
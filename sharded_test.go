@@ -0,0 +1,66 @@
+package hrw
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestShardedRing(t *testing.T) {
+	nodes := testNodes(50)
+	nodes[3].Weight, nodes[7].State = 3, StateDown
+
+	var (
+		ring    = NewRing(nodes...)
+		sharded = NewShardedRing(8, nodes...)
+	)
+
+	if actual := sharded.Nodes(); !reflect.DeepEqual(actual, ring.Nodes()) || sharded.Len() != 50 {
+		t.Errorf("Was %#v, but expected %#v", actual, ring.Nodes())
+	}
+
+	check := func() {
+		for i := 0; i < 100; i++ {
+			key := []byte("key-" + strconv.Itoa(i))
+			if actual, expect := sharded.GetN(key, 5), ring.GetN(key, 5); !reflect.DeepEqual(actual, expect) {
+				t.Fatalf("Was %#v, but expected %#v", nodeIDs(actual), nodeIDs(expect))
+			}
+		}
+	}
+	check()
+
+	version := sharded.Version()
+	ring.Remove("node-1", "node-2")
+	sharded.Remove("node-1", "node-2")
+	check()
+
+	if sharded.Version() == version {
+		t.Errorf("Expected version to change")
+	}
+
+	if _, ok := NewShardedRing(4).Get(testKey); ok {
+		t.Errorf("Expected no node for empty ring")
+	}
+}
+
+func TestShardedRingIsolation(t *testing.T) {
+	r := NewShardedRing(4, testNodes(100)...)
+
+	before := make([]*Snapshot, 0, len(r.shards))
+	for _, s := range r.shards {
+		before = append(before, s.Snapshot())
+	}
+
+	r.Add(Node{ID: "extra"})
+
+	var changed int
+	for i, s := range r.shards {
+		if s.Snapshot() != before[i] {
+			changed++
+		}
+	}
+
+	if changed != 1 {
+		t.Errorf("Was %d shards changed, but expected 1", changed)
+	}
+}
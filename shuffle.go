@@ -0,0 +1,24 @@
+package hrw
+
+// Shuffle returns deterministic permutation of [0, n) for hash. It's order
+// of indexes SortSliceByIndex applies: after SortSliceByIndex(s, hash)
+// s[i] holds element which index was Shuffle(len(s), hash)[i]. Permutation
+// never changes for the same n and hash, and relative order of indexes
+// is kept when n grows, so Shuffle(n, hash) equals Shuffle(n+1, hash)
+// without index n.
+func Shuffle(n int, hash uint64) []int {
+	if n <= 0 {
+		return []int{}
+	}
+
+	rule := make([]uint64, 0, n)
+	for i := uint64(0); i < uint64(n); i++ {
+		rule = append(rule, i)
+	}
+
+	result := make([]int, 0, n)
+	for _, i := range SortByWeight(rule, hash) {
+		result = append(result, int(i))
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package hrw
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestShuffle(t *testing.T) {
+	hash := Hash(testKey)
+
+	t.Run("frozen", func(t *testing.T) {
+		if actual, expect := Shuffle(6, hash), []int{4, 0, 2, 5, 3, 1}; !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+
+	t.Run("same as SortSliceByIndex", func(t *testing.T) {
+		slice := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		SortSliceByIndex(slice, hash)
+		if actual := Shuffle(len(slice), hash); !reflect.DeepEqual(actual, slice) {
+			t.Errorf("Was %#v, but expected %#v", actual, slice)
+		}
+	})
+
+	t.Run("permutation", func(t *testing.T) {
+		actual := Shuffle(100, hash)
+		sort.Ints(actual)
+		for i := range actual {
+			if actual[i] != i {
+				t.Fatalf("Was %#v, expected permutation of [0, 100)", actual)
+			}
+		}
+	})
+
+	t.Run("growing", func(t *testing.T) {
+		expect := Shuffle(10, hash)
+		var actual []int
+		for _, i := range Shuffle(11, hash) {
+			if i != 10 {
+				actual = append(actual, i)
+			}
+		}
+
+		if !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if actual := Shuffle(0, hash); len(actual) != 0 {
+			t.Errorf("Was %#v, but expected empty permutation", actual)
+		}
+	})
+}
@@ -0,0 +1,214 @@
+package hrw
+
+import (
+	"math"
+	"sort"
+)
+
+// SkeletonTree selects nodes in O(fanout * log(n)) using hierarchical
+// (skeleton-based) rendezvous hashing. Active nodes are leaves of virtual
+// tree, every inner vertex groups nodes which hashes share prefix, so
+// every level takes log2(fanout) more bits of hash, and weights as sum of
+// them. Get descends from root choosing child by HRW, so node is selected
+// with probability proportional to it's weight. Vertices are identified by
+// prefixes, so join or leave of node changes only weights along it's path
+// and moves keys only into or out of it's ancestors, about depth / n of
+// them, while flat HRW moves 1 / n.
+// Tree is immutable, rebuild it when membership changes.
+type SkeletonTree struct {
+	alg      Algorithm
+	hashFn   HashFunc
+	cost     CostFunc
+	bits     uint
+	leaves   []member
+	weights  []float64
+	vertices []skeletonVertex
+}
+
+// skeletonVertex is vertex of SkeletonTree, it's children are
+// vertices[first:last] or leaves[first:last] for terminal one
+type skeletonVertex struct {
+	hash        uint64
+	weight      float64
+	first, last int
+	terminal    bool
+}
+
+// NewSkeletonTree creates tree over active nodes of the lowest tier, fanout is rounded down
+// to power of two, values < 2 treated as 2
+func NewSkeletonTree(fanout int, nodes ...Node) *SkeletonTree {
+	return newSkeletonTree(fanout, V1, nil, nil, nodes)
+}
+
+// SkeletonTree returns tree over active nodes of Ring, it uses hash,
+// algorithm and cost function of Ring
+func (r *Ring) SkeletonTree(fanout int) *SkeletonTree {
+	s := r.view()
+	return newSkeletonTree(fanout, s.alg, s.hashFn, s.cost, s.Nodes())
+}
+
+func newSkeletonTree(fanout int, alg Algorithm, fn HashFunc, cost CostFunc, nodes []Node) *SkeletonTree {
+	t := &SkeletonTree{alg: alg, hashFn: fn, cost: cost, bits: 1}
+	for fanout>>(t.bits+1) > 0 {
+		t.bits++
+	}
+
+	t.leaves = make([]member, 0, len(nodes))
+	for _, n := range nodes {
+		if n.State == StateActive {
+			t.leaves = append(t.leaves, newMember(n, fn))
+		}
+	}
+
+	// the last of nodes with the same ID wins, like in Ring.Add
+	sort.SliceStable(t.leaves, func(i, j int) bool { return t.leaves[i].ID < t.leaves[j].ID })
+	uniq := t.leaves[:0]
+	for i := range t.leaves {
+		if i+1 < len(t.leaves) && t.leaves[i+1].ID == t.leaves[i].ID {
+			continue
+		}
+		uniq = append(uniq, t.leaves[i])
+	}
+	t.leaves = uniq
+
+	// only nodes of the lowest tier are selected, like Ring does
+	if len(t.leaves) > 0 {
+		tier := t.leaves[0].Tier
+		for i := range t.leaves {
+			if t.leaves[i].Tier < tier {
+				tier = t.leaves[i].Tier
+			}
+		}
+
+		lowest := t.leaves[:0]
+		for i := range t.leaves {
+			if t.leaves[i].Tier == tier {
+				lowest = append(lowest, t.leaves[i])
+			}
+		}
+		t.leaves = lowest
+	}
+
+	if len(t.leaves) == 0 {
+		return t
+	}
+
+	sort.Slice(t.leaves, func(i, j int) bool {
+		a, b := t.leaves[i], t.leaves[j]
+		return a.hash < b.hash || a.hash == b.hash && a.ID < b.ID
+	})
+
+	t.weights = make([]float64, 0, len(t.leaves))
+	for i := range t.leaves {
+		t.weights = append(t.weights, cost.weight(t.leaves[i].Node))
+	}
+
+	t.vertices = append(t.vertices, skeletonVertex{weight: t.sum(0, len(t.leaves)), last: len(t.leaves)})
+	t.build(0, 0)
+	return t
+}
+
+// build splits leaves of vertex v at depth into children by next bits
+// of their hashes, vertex with single leaf or exhausted bits is terminal
+func (t *SkeletonTree) build(v int, depth uint) {
+	lo, hi := t.vertices[v].first, t.vertices[v].last
+	if hi-lo == 1 || depth*t.bits >= 64 {
+		t.vertices[v].terminal = true
+		return
+	}
+
+	first := len(t.vertices)
+	for i := lo; i < hi; {
+		prefix := t.prefix(t.leaves[i].hash, depth+1)
+
+		j := i + 1
+		for j < hi && t.prefix(t.leaves[j].hash, depth+1) == prefix {
+			j++
+		}
+
+		t.vertices = append(t.vertices, skeletonVertex{
+			hash:   saltHash(prefix, uint64(depth+1)),
+			weight: t.sum(i, j),
+			first:  i,
+			last:   j,
+		})
+		i = j
+	}
+
+	last := len(t.vertices)
+	t.vertices[v].first, t.vertices[v].last = first, last
+	for c := first; c < last; c++ {
+		t.build(c, depth+1)
+	}
+}
+
+// prefix returns the highest bits of hash identifying vertex at depth
+func (t *SkeletonTree) prefix(hash uint64, depth uint) uint64 {
+	if bits := depth * t.bits; bits < 64 {
+		return hash &^ (math.MaxUint64 >> bits)
+	}
+	return hash
+}
+
+// sum returns weight of leaves[from:to]
+func (t *SkeletonTree) sum(from, to int) float64 {
+	var total float64
+	for _, w := range t.weights[from:to] {
+		total += w
+	}
+	return total
+}
+
+// Len returns count of nodes in tree
+func (t *SkeletonTree) Len() int {
+	return len(t.leaves)
+}
+
+// Get returns node selected for key
+func (t *SkeletonTree) Get(key []byte) (Node, bool) {
+	return t.GetByHash(t.hashFn.hash(key))
+}
+
+// GetByHash returns node selected for hash of key
+func (t *SkeletonTree) GetByHash(hash uint64) (Node, bool) {
+	if len(t.leaves) == 0 {
+		return Node{}, false
+	}
+
+	var (
+		v     int
+		depth uint64
+	)
+
+	for ; !t.vertices[v].terminal; depth++ {
+		var (
+			vertex = t.vertices[v]
+			salted = saltHash(hash, depth)
+			best   candidate
+		)
+
+		for j := vertex.first; j < vertex.last; j++ {
+			c := t.alg.candidate(t.vertices[j].hash, salted, t.vertices[j].weight)
+			if j == vertex.first || c.less(best) {
+				v, best = j, c
+			}
+		}
+	}
+
+	var (
+		vertex = t.vertices[v]
+		index  = vertex.first
+		salted = saltHash(hash, depth)
+		best   candidate
+	)
+
+	// leaves of terminal vertex share all bits of hash
+	for j := vertex.first; j < vertex.last && vertex.last-vertex.first > 1; j++ {
+		c := t.alg.candidate(t.leaves[j].hash, salted, t.weights[j])
+		c.index = j
+		if j == vertex.first || c.less(best) {
+			index, best = j, c
+		}
+	}
+	return t.leaves[index].Node, true
+}
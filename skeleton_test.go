@@ -0,0 +1,135 @@
+package hrw
+
+import (
+	"encoding/binary"
+	"strconv"
+	"testing"
+)
+
+func TestSkeletonTree(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		if _, ok := NewSkeletonTree(4).Get(testKey); ok {
+			t.Errorf("Expected no node for empty tree")
+		}
+	})
+
+	t.Run("single node", func(t *testing.T) {
+		n, ok := NewSkeletonTree(4, Node{ID: "a"}).Get(testKey)
+		if !ok || n.ID != "a" {
+			t.Errorf("Was %#v, but expected %#v", n.ID, "a")
+		}
+	})
+
+	t.Run("skip inactive and duplicates", func(t *testing.T) {
+		tree := NewSkeletonTree(2,
+			Node{ID: "a"}, Node{ID: "b", State: StateDown},
+			Node{ID: "c"}, Node{ID: "a", Weight: 2},
+		)
+		if actual := tree.Len(); actual != 2 {
+			t.Errorf("Was %#v, but expected %#v", actual, 2)
+		}
+	})
+
+	t.Run("lowest tier", func(t *testing.T) {
+		tree := NewSkeletonTree(2, Node{ID: "a", Tier: 1}, Node{ID: "b"}, Node{ID: "c", Tier: 1})
+		if n, _ := tree.Get(testKey); tree.Len() != 1 || n.ID != "b" {
+			t.Errorf("Was %#v (%d nodes), but expected %#v", n.ID, tree.Len(), "b")
+		}
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		nodes := testNodes(100)
+		expect, _ := NewSkeletonTree(8, nodes...).Get(testKey)
+
+		reversed := make([]Node, 0, len(nodes))
+		for i := len(nodes) - 1; i >= 0; i-- {
+			reversed = append(reversed, nodes[i])
+		}
+
+		if actual, _ := NewSkeletonTree(8, reversed...).Get(testKey); actual.ID != expect.ID {
+			t.Errorf("Was %#v, but expected %#v", actual.ID, expect.ID)
+		}
+	})
+
+	t.Run("distribution", func(t *testing.T) {
+		const (
+			size = 50
+			keys = 200000
+		)
+
+		var (
+			nodes  = testNodes(size)
+			counts = make(map[string]int, size)
+			key    = make([]byte, 8)
+		)
+
+		// the last node is twice heavier
+		nodes[size-1].Weight = 2
+		tree := NewRing(nodes...).SkeletonTree(3)
+
+		for i := uint64(0); i < keys; i++ {
+			binary.BigEndian.PutUint64(key, i)
+			n, _ := tree.Get(key)
+			counts[n.ID]++
+		}
+
+		mean := float64(keys) / (size + 1)
+		for i := 0; i < size; i++ {
+			id := "node-" + strconv.Itoa(i)
+			expect := mean
+			if i == size-1 {
+				expect *= 2
+			}
+
+			if d := float64(counts[id]) / expect; d < 0.9 || d > 1.1 {
+				t.Errorf("Node %s received %d keys, expected %.0f", id, counts[id], expect)
+			}
+		}
+	})
+}
+
+func TestSkeletonTreeMovement(t *testing.T) {
+	const (
+		size = 1000
+		keys = 10000
+	)
+
+	var (
+		nodes  = testNodes(size + 1)
+		before = NewSkeletonTree(8, nodes[:size]...)
+		after  = NewSkeletonTree(8, nodes...)
+		key    = make([]byte, 8)
+		moved  int
+		path   = after.prefix(after.hashFn.hashString(nodes[size].ID), 1)
+	)
+
+	for i := uint64(0); i < keys; i++ {
+		binary.BigEndian.PutUint64(key, i)
+		a, _ := before.Get(key)
+		b, _ := after.Get(key)
+		if a.ID == b.ID {
+			continue
+		}
+
+		// keys move only into subtree of new node
+		moved++
+		if after.prefix(after.hashFn.hashString(b.ID), 1) != path {
+			t.Errorf("Key %d moved from %q to %q out of new node subtree", i, a.ID, b.ID)
+		}
+	}
+
+	// flat HRW moves about 1/n of keys, tree moves about depth/n of them
+	if limit := 6 * keys / size; moved > limit {
+		t.Errorf("Was %d keys moved, but expected at most %d", moved, limit)
+	}
+}
+
+func BenchmarkSkeletonTree(b *testing.B) {
+	tree := NewSkeletonTree(16, testNodes(100000)...)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = tree.Get(testKey)
+	}
+}
@@ -0,0 +1,113 @@
+package hrw
+
+// knownSwapper returns swapper and length of slice of known type
+// without reflection
+func knownSwapper(slice interface{}) (swapper, int, bool) {
+	switch s := slice.(type) {
+	case []int:
+		return func(i, j int) { s[i], s[j] = s[j], s[i] }, len(s), true
+	case []int32:
+		return func(i, j int) { s[i], s[j] = s[j], s[i] }, len(s), true
+	case []int64:
+		return func(i, j int) { s[i], s[j] = s[j], s[i] }, len(s), true
+	case []int16:
+		return func(i, j int) { s[i], s[j] = s[j], s[i] }, len(s), true
+	case []int8:
+		return func(i, j int) { s[i], s[j] = s[j], s[i] }, len(s), true
+	case []uint:
+		return func(i, j int) { s[i], s[j] = s[j], s[i] }, len(s), true
+	case []uint32:
+		return func(i, j int) { s[i], s[j] = s[j], s[i] }, len(s), true
+	case []uint64:
+		return func(i, j int) { s[i], s[j] = s[j], s[i] }, len(s), true
+	case []string:
+		return func(i, j int) { s[i], s[j] = s[j], s[i] }, len(s), true
+	case []byte:
+		return func(i, j int) { s[i], s[j] = s[j], s[i] }, len(s), true
+	case []Hasher:
+		return func(i, j int) { s[i], s[j] = s[j], s[i] }, len(s), true
+	case []interface{}:
+		return func(i, j int) { s[i], s[j] = s[j], s[i] }, len(s), true
+	default:
+		return nil, 0, false
+	}
+}
+
+// hashersRule appends hashes of non-nil elements to rule, optionally mixed
+// by fn, and returns indexes of nil elements
+func hashersRule(at func(i int) Hasher, length int, rule []uint64, fn func(uint64) uint64) ([]uint64, []int) {
+	var nils []int
+	for i := 0; i < length; i++ {
+		h := at(i)
+		if h == nil {
+			nils = append(nils, i)
+			continue
+		}
+
+		if fn != nil {
+			rule = append(rule, fn(h.Hash()))
+		} else {
+			rule = append(rule, h.Hash())
+		}
+	}
+	return rule, nils
+}
+
+// applyOrder sorts slice by order of it's non-nil elements
+// and places nil elements last
+func applyOrder(swap swapper, length int, order []int32, nils []int) error {
+	if len(nils) == 0 {
+		sortByRuleInverse(swap, length, order)
+		return nil
+	}
+
+	index := make([]int32, 0, length-len(nils))
+	for i, n := 0, 0; i < length; i++ {
+		if n < len(nils) && nils[n] == i {
+			n++
+			continue
+		}
+		index = append(index, int32(i))
+	}
+
+	rule := make([]int32, 0, length)
+	for _, k := range order {
+		rule = append(rule, index[k])
+	}
+	for _, i := range nils {
+		rule = append(rule, int32(i))
+	}
+
+	sortByRuleInverse(swap, length, rule)
+	return &NilElementsError{Indexes: nils}
+}
+
+// knownHashers returns accessor of []Hasher and []interface{} elements
+// without reflection, accessor returns nil for nil elements, see isNilHasher
+func knownHashers(slice interface{}) (func(i int) Hasher, error) {
+	switch s := slice.(type) {
+	case []Hasher:
+		return func(i int) Hasher { return knownHasher(s[i]) }, nil
+	case []interface{}:
+		for i := range s {
+			if _, ok := s[i].(Hasher); !ok && s[i] != nil {
+				return nil, unsupportedElementAt(slice, i, s[i])
+			}
+		}
+
+		return func(i int) Hasher {
+			h, _ := s[i].(Hasher)
+			return knownHasher(h)
+		}, nil
+	default:
+		return nil, unsupportedElement(slice)
+	}
+}
+
+// knownHasher returns h or nil when h holds nil
+func knownHasher(h Hasher) Hasher {
+	if isNilHasher(h) {
+		return nil
+	}
+	return h
+}
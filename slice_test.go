@@ -0,0 +1,90 @@
+package hrw
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestKnownSwapper(t *testing.T) {
+	cases := []interface{}{
+		[]int{1, 2, 3},
+		[]int32{1, 2, 3},
+		[]int64{1, 2, 3},
+		[]uint{1, 2, 3},
+		[]uint32{1, 2, 3},
+		[]uint64{1, 2, 3},
+		[]string{"1", "2", "3"},
+		[]byte{1, 2, 3},
+		[]Hasher{hashString("1"), hashString("2"), hashString("3")},
+		[]interface{}{1, "2", 3},
+	}
+
+	for _, slice := range cases {
+		swap, length, ok := knownSwapper(slice)
+		if !ok || length != 3 {
+			t.Errorf("Expected %T to be supported", slice)
+			continue
+		}
+
+		expect := reflect.ValueOf(slice).Index(0).Interface()
+		swap(0, 2)
+		if actual := reflect.ValueOf(slice).Index(2).Interface(); actual != expect {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	}
+
+	if _, _, ok := knownSwapper([]hashString{"a"}); ok {
+		t.Errorf("Expected []hashString to be unknown")
+	}
+}
+
+type (
+	hashMap     map[string]uint64
+	panicHasher struct{}
+)
+
+func (m hashMap) Hash() uint64 { return m["hash"] }
+
+func (panicHasher) Hash() uint64 { panic("hash") }
+
+func TestKnownHashers(t *testing.T) {
+	at, err := knownHashers([]Hasher{hashString("a")})
+	if err != nil || at(0).Hash() != Hash([]byte("a")) {
+		t.Errorf("Expected []Hasher to be supported")
+	}
+
+	at, err = knownHashers([]interface{}{nil, hashString("a")})
+	if err != nil || at(0) != nil || at(1).Hash() != Hash([]byte("a")) {
+		t.Errorf("Expected []interface{} of Hasher to be supported")
+	}
+
+	at, err = knownHashers([]Hasher{(*hashNode)(nil), &hashNode{id: "a"}})
+	if err != nil || at(0) != nil || at(1).Hash() != Hash([]byte("a")) {
+		t.Errorf("Expected nil pointer to be treated as nil element")
+	}
+
+	at, err = knownHashers([]Hasher{hashMap(nil), hashString("")})
+	if err != nil || at(0) != nil || at(1) == nil {
+		t.Errorf("Expected only nil map to be treated as nil element")
+	}
+
+	if _, err := knownHashers([]interface{}{hashString("a"), 1}); !errors.Is(err, ErrUnsupportedElement) {
+		t.Errorf("Was %v, but expected %v", err, ErrUnsupportedElement)
+	}
+
+	if _, err := knownHashers([]int{1}); !errors.Is(err, ErrUnsupportedElement) {
+		t.Errorf("Was %v, but expected %v", err, ErrUnsupportedElement)
+	}
+}
+
+func TestSortSliceByValueHashPanic(t *testing.T) {
+	// panics of Hash aren't recovered with or without reflection
+	defer func() {
+		if actual := recover(); actual != "hash" {
+			t.Errorf("Was %#v, but expected %#v", actual, "hash")
+		}
+	}()
+
+	_ = TrySortSliceByValue([]Hasher{hashString("a"), panicHasher{}}, Hash(testKey))
+}
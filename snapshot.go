@@ -0,0 +1,132 @@
+package hrw
+
+import "time"
+
+// Snapshot is immutable view of Ring membership and settings, it selects
+// nodes the same way Ring did at the moment Snapshot was taken. Use it
+// to pin consistent view for operations over multiple keys.
+type Snapshot struct {
+	nodes   []member
+	hashFn  HashFunc
+	alg     Algorithm
+	load    LoadReporter
+	penalty PenaltyFunc
+	cost    CostFunc
+	weight  WeightFunc
+	trace   TraceFunc
+	capa    CapacityPolicy
+	hedge   HedgeDelayFunc
+	version uint64
+	gen     uint64
+	lists   map[string]listing
+	pins    map[string]string
+	prefix  []Pin
+	levels  []string
+	keep    int
+	history []*Snapshot
+	now     func() time.Time
+}
+
+var emptyState = new(Snapshot)
+
+// Nodes returns copy of members ordered by ID
+func (s *Snapshot) Nodes() []Node {
+	result := make([]Node, 0, len(s.nodes))
+	for i := range s.nodes {
+		result = append(result, s.nodes[i].Node)
+	}
+	return result
+}
+
+// Len returns count of members
+func (s *Snapshot) Len() int {
+	return len(s.nodes)
+}
+
+// Version returns version of Ring Snapshot was taken at
+func (s *Snapshot) Version() uint64 {
+	return s.version
+}
+
+// Generation returns generation of Ring Snapshot was taken at
+func (s *Snapshot) Generation() uint64 {
+	return s.gen
+}
+
+// Checksum returns hash of membership view, see Ring.Checksum
+func (s *Snapshot) Checksum() uint64 {
+	return checksumMembers(s.nodes, s.hashFn)
+}
+
+// Hash returns hash of key used to select nodes
+func (s *Snapshot) Hash(key []byte) uint64 {
+	return s.hashFn.hash(key)
+}
+
+// Get returns most preferable active node for key
+func (s *Snapshot) Get(key []byte) (Node, bool) {
+	return first(s.GetN(key, 1))
+}
+
+// GetN returns up to n active nodes for key in order of preference
+func (s *Snapshot) GetN(key []byte, n int) []Node {
+	if id, ok := s.pinned(key); ok && n > 0 {
+		return s.pickPinned(key, id, n)
+	}
+	return s.pick(s.Hash(key), n)
+}
+
+// eligible returns all selectable nodes for key in order of preference,
+// error is returned when there are none
+func (s *Snapshot) eligible(key []byte) ([]Node, error) {
+	list := s.GetN(key, s.Len())
+	if len(list) == 0 {
+		return nil, noEligibleNodes(s.Len())
+	}
+	return list, nil
+}
+
+func (s *Snapshot) pick(hash uint64, n int) []Node {
+	if n <= 0 {
+		return nil
+	}
+
+	list := s.rank(hash)
+	s.penalize(list)
+	result := topMembers(s.nodes, list, n)
+	if s.trace != nil {
+		s.emitTrace(hash, list, result)
+	}
+	return result
+}
+
+// rank returns candidates of active and gray members for hash
+func (s *Snapshot) rank(hash uint64) []candidate {
+	var (
+		now  time.Time
+		list = make([]candidate, 0, len(s.nodes))
+	)
+
+	if len(s.lists) > 0 {
+		now = s.clock()
+	}
+
+	for i := range s.nodes {
+		state := s.stateOf(&s.nodes[i], now)
+		if !state.selectable() {
+			continue
+		}
+
+		c := s.candidate(s.nodes[i].hash, hash, s.cost.weight(s.nodes[i].Node))
+		c.index, c.tier, c.gray = i, s.nodes[i].Tier, state.last()
+		list = append(list, c)
+	}
+	return list
+}
+
+func (s *Snapshot) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
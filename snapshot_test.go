@@ -0,0 +1,51 @@
+package hrw
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRingSnapshot(t *testing.T) {
+	var (
+		r        = NewRing(testNodes(10)...)
+		snapshot = r.Snapshot()
+		expect   = nodeIDs(r.GetN(testKey, 3))
+		version  = r.Version()
+		checksum = r.Checksum()
+	)
+
+	r.Remove(expect[0])
+	r.Add(Node{ID: "new-node"})
+
+	if actual := nodeIDs(snapshot.GetN(testKey, 3)); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	if actual := snapshot.Version(); actual != version {
+		t.Errorf("Was %d, but expected %d", actual, version)
+	}
+
+	if actual := snapshot.Checksum(); actual != checksum {
+		t.Errorf("Was %d, but expected %d", actual, checksum)
+	}
+
+	if actual := snapshot.Len(); actual != 10 {
+		t.Errorf("Was %d, but expected %d", actual, 10)
+	}
+
+	current := r.Snapshot()
+	if actual, expect := nodeIDs(current.GetN(testKey, 3)), nodeIDs(r.GetN(testKey, 3)); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	if actual, expect := current.Nodes(), r.Nodes(); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
+
+func TestEmptySnapshot(t *testing.T) {
+	var r Ring
+	if _, ok := r.Snapshot().Get(testKey); ok {
+		t.Errorf("Expected no node for empty snapshot")
+	}
+}
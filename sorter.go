@@ -0,0 +1,164 @@
+package hrw
+
+// Sorter sorts like package-level functions do, but reuses it's buffers
+// between calls, so sorting in tight loops doesn't allocate. Slices
+// returned by Sorter are valid until it's next call. Zero value is ready
+// to use, Sorter isn't safe for concurrent use.
+type Sorter struct {
+	rule, weight, order []uint64
+	sorted, radix       []int32
+	done                bitset
+	cfg                 Config
+}
+
+// NewSorter creates Sorter configured by options
+func NewSorter(opts ...Option) *Sorter {
+	return &Sorter{cfg: NewConfig(opts...)}
+}
+
+// SortByWeight is like package-level SortByWeight, but uses weight function
+// of Sorter configuration
+func (s *Sorter) SortByWeight(nodes []uint64, hash uint64) []uint64 {
+	return s.SortByWeightFunc(nodes, hash, s.weightFunc())
+}
+
+// SortByWeightFunc is like package-level SortByWeightFunc
+func (s *Sorter) SortByWeightFunc(nodes []uint64, hash uint64, fn WeightFunc) []uint64 {
+	s.order = s.sortByWeightFunc(nodes, hash, fn).order(s.order)
+	return s.order
+}
+
+// SortSliceByValue is like package-level SortSliceByValue, but uses
+// algorithm of Sorter configuration
+func (s *Sorter) SortSliceByValue(slice interface{}, hash uint64) {
+	s.check(s.TrySortSliceByValue(slice, hash))
+}
+
+// SortSliceByIndex is like package-level SortSliceByIndex
+func (s *Sorter) SortSliceByIndex(slice interface{}, hash uint64) {
+	s.check(s.TrySortSliceByIndex(slice, hash))
+}
+
+// Assign is like AssignParallel with Parallelism of Sorter configuration
+// as count of workers, but uses it's weight function
+func (s *Sorter) Assign(dst []int, nodes []uint64, hashes []uint64) {
+	fn, custom := s.weightFunc(), s.customWeight()
+	assignParallel(dst, hashes, s.config().Parallelism, func(dst []int, hashes []uint64) {
+		for k, hash := range hashes {
+			if custom {
+				dst[k] = top1IndexFunc(nodes, hash, fn)
+			} else {
+				dst[k] = Top1Index(nodes, hash)
+			}
+		}
+	})
+}
+
+// config returns configuration of Sorter, nil Sorter has default one
+func (s *Sorter) config() Config {
+	if s == nil {
+		return Config{}
+	}
+	return s.cfg
+}
+
+// customWeight reports whether nodes are weighted unlike SortByWeight
+func (s *Sorter) customWeight() bool {
+	cfg := s.config()
+	return cfg.Weight != nil || cfg.Algorithm == V3
+}
+
+// weightFunc returns function weighting nodes
+func (s *Sorter) weightFunc() WeightFunc {
+	cfg := s.config()
+	switch {
+	case cfg.Weight != nil:
+		return cfg.Weight
+	case cfg.Algorithm == V3:
+		return MixPremixed
+	default:
+		return weight
+	}
+}
+
+// check panics with err in strict mode or when Sorter is configured so
+func (s *Sorter) check(err error) {
+	if strictError(err) && s.config().Strict {
+		panic(err)
+	}
+	checkStrict(err)
+}
+
+// sortByWeightFunc orders nodes, nil Sorter allocates new buffers
+func (s *Sorter) sortByWeightFunc(nodes []uint64, hash uint64, fn WeightFunc) hashed {
+	l := len(nodes)
+	h := hashed{length: l, nodes: nodes}
+	if s == nil {
+		h.sorted, h.weight = make([]int32, 0, l), make([]uint64, 0, l)
+	} else {
+		s.sorted, s.weight = reuse32(s.sorted, l), reuse(s.weight, l)
+		h.sorted, h.weight = s.sorted, s.weight
+	}
+
+	for i, node := range nodes {
+		h.sorted = append(h.sorted, int32(i))
+		h.weight = append(h.weight, fn(node, hash))
+	}
+
+	var radix []int32
+	if s != nil && l >= radixThreshold {
+		s.radix = reuse32(s.radix, l)
+		radix = s.radix[:l]
+	}
+
+	h.sort(radix)
+	if debugChecks {
+		debugCheckSorted(h, nodes, hash, fn)
+	}
+	return h
+}
+
+// ruleBuffer returns empty slice of capacity n for rule
+func (s *Sorter) ruleBuffer(n int) []uint64 {
+	if s == nil {
+		return make([]uint64, 0, n)
+	}
+
+	s.rule = reuse(s.rule, n)
+	return s.rule
+}
+
+// reuse returns empty buf when it has capacity n or allocates new one
+func reuse(buf []uint64, n int) []uint64 {
+	if buf == nil || cap(buf) < n {
+		return make([]uint64, 0, n)
+	}
+	return buf[:0]
+}
+
+// reuse32 is like reuse for int32 indexes
+func reuse32(buf []int32, n int) []int32 {
+	if buf == nil || cap(buf) < n {
+		return make([]int32, 0, n)
+	}
+	return buf[:0]
+}
+
+// bitset returns empty bitset of n bits
+func (s *Sorter) bitset(n int) bitset {
+	if s == nil {
+		return newBitset(n)
+	}
+
+	words := (n + 63) / 64
+	if cap(s.done) < words {
+		s.done = newBitset(n)
+		return s.done
+	}
+
+	s.done = s.done[:words]
+	for i := range s.done {
+		s.done[i] = 0
+	}
+	return s.done
+}
@@ -0,0 +1,78 @@
+package hrw
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestSorter(t *testing.T) {
+	var s Sorter
+	for _, n := range []int{0, 10, 1000, 100} {
+		var (
+			nodes  = make([]uint64, 0, n)
+			values = make([]string, 0, n)
+			hash   = Hash(testKey)
+		)
+
+		for i := 0; i < n; i++ {
+			nodes = append(nodes, uint64(i))
+			values = append(values, "node-"+strconv.Itoa(i))
+		}
+
+		if actual, expect := s.SortByWeight(nodes, hash), SortByWeight(nodes, hash); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+
+		actual, expect := append([]string(nil), values...), append([]string(nil), values...)
+		s.SortSliceByValue(actual, hash)
+		SortSliceByValue(expect, hash)
+		if !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+
+		actual, expect = append(actual[:0], values...), append(expect[:0], values...)
+		s.SortSliceByIndex(actual, hash)
+		SortSliceByIndex(expect, hash)
+		if !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Was %#v, but expected %#v", actual, expect)
+		}
+	}
+}
+
+func TestSorterAllocs(t *testing.T) {
+	if debugChecks {
+		t.Skip("hrwdebug checks allocate")
+	}
+
+	var (
+		s     Sorter
+		nodes = make([]uint64, 1000)
+	)
+
+	for i := range nodes {
+		nodes[i] = uint64(i)
+	}
+
+	s.SortByWeight(nodes, 0)
+	if allocs := testing.AllocsPerRun(10, func() { s.SortByWeight(nodes, 1) }); allocs != 0 {
+		t.Errorf("Was %.0f allocations, but expected 0", allocs)
+	}
+}
+
+func BenchmarkSorter_1000(b *testing.B) {
+	var (
+		s     Sorter
+		nodes = make([]uint64, 1000)
+	)
+
+	for i := range nodes {
+		nodes[i] = uint64(i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.SortByWeight(nodes, uint64(i))
+	}
+}
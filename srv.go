@@ -0,0 +1,138 @@
+package hrw
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// zeroSRVWeight is weight of SRV records of weight 0 when other records
+// of the same priority have positive weights, RFC 2782 gives them
+// a very small chance of being selected
+const zeroSRVWeight = 0.01
+
+// SRVSource keeps membership of Ring in sync with DNS SRV records, so
+// operators steer traffic by editing DNS. Every record is a node with
+// "target:port" ID, SRV weight as Weight and SRV priority as Tier, so
+// records of higher priority values receive keys only when ones of lower
+// values are exhausted. Answers without records are rejected and Ring
+// keeps previous membership.
+type SRVSource struct {
+	Ring *Ring
+	// Service, Proto and Name are queried like net.LookupSRV does
+	Service, Proto, Name string
+	// Lookup resolves records, net.DefaultResolver.LookupSRV by default
+	Lookup func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	// OnError is called by Run for failed lookups
+	OnError func(err error)
+
+	mu   sync.Mutex
+	last []Node
+}
+
+// NodesFromSRV converts SRV records into nodes, see SRVSource. Records
+// of the same target and port are merged into one of the lowest priority.
+func NodesFromSRV(records []*net.SRV) []Node {
+	var (
+		nodes   []Node
+		index   = make(map[string]int, len(records))
+		weights = make(map[uint16]bool)
+	)
+
+	for _, rec := range records {
+		if rec.Weight > 0 {
+			weights[rec.Priority] = true
+		}
+	}
+
+	for _, rec := range records {
+		w := float64(rec.Weight)
+		if w == 0 && weights[rec.Priority] {
+			w = zeroSRVWeight
+		}
+
+		n := Node{
+			ID:     net.JoinHostPort(strings.TrimSuffix(rec.Target, "."), strconv.Itoa(int(rec.Port))),
+			Weight: w,
+			Tier:   int(rec.Priority),
+		}
+
+		if i, ok := index[n.ID]; !ok {
+			index[n.ID] = len(nodes)
+			nodes = append(nodes, n)
+		} else if n.Tier < nodes[i].Tier {
+			nodes[i] = n
+		}
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+// Load resolves records and applies them to Ring, it reports whether
+// membership was changed. Unchanged records aren't applied again.
+func (s *SRVSource) Load(ctx context.Context) (bool, error) {
+	lookup := s.Lookup
+	if lookup == nil {
+		lookup = net.DefaultResolver.LookupSRV
+	}
+
+	_, records, err := lookup(ctx, s.Service, s.Proto, s.Name)
+	if err != nil {
+		return false, err
+	}
+
+	nodes := NodesFromSRV(records)
+	if len(nodes) == 0 {
+		return false, fmt.Errorf("%w: no SRV records of %s", ErrEmptyInput, s.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sameSRVNodes(nodes, s.last) {
+		return false, nil
+	}
+
+	s.Ring.replace(nodes)
+	s.last = nodes
+	return true, nil
+}
+
+// Run resolves records every interval until ctx is done
+func (s *SRVSource) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Load(ctx); err != nil && s.OnError != nil {
+			s.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sameSRVNodes reports whether nodes made by NodesFromSRV are equal,
+// only fields filled by it are compared
+func sameSRVNodes(a, b []Node) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].ID != b[i].ID || a[i].Weight != b[i].Weight || a[i].Tier != b[i].Tier {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package hrw
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestNodesFromSRV(t *testing.T) {
+	nodes := NodesFromSRV([]*net.SRV{
+		{Target: "b.example.com.", Port: 80, Priority: 10, Weight: 0},
+		{Target: "a.example.com.", Port: 80, Priority: 10, Weight: 3},
+		{Target: "c.example.com.", Port: 80, Priority: 20, Weight: 0},
+		{Target: "a.example.com.", Port: 80, Priority: 5, Weight: 1},
+	})
+
+	expect := []Node{
+		{ID: "a.example.com:80", Weight: 1, Tier: 5},
+		{ID: "b.example.com:80", Weight: zeroSRVWeight, Tier: 10},
+		{ID: "c.example.com:80", Weight: 0, Tier: 20},
+	}
+
+	if !reflect.DeepEqual(nodes, expect) {
+		t.Errorf("Was %#v, but expected %#v", nodes, expect)
+	}
+}
+
+func TestSRVSource(t *testing.T) {
+	var (
+		records []*net.SRV
+		r       = NewRing()
+		src     = &SRVSource{
+			Ring: r,
+			Name: "example.com",
+			Lookup: func(context.Context, string, string, string) (string, []*net.SRV, error) {
+				return "", records, nil
+			},
+		}
+	)
+
+	if _, err := src.Load(context.Background()); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrEmptyInput)
+	}
+
+	records = []*net.SRV{{Target: "a.", Port: 1, Weight: 1}, {Target: "b.", Port: 1, Weight: 1}}
+	if changed, err := src.Load(context.Background()); err != nil || !changed {
+		t.Errorf("Was %v (%v), but expected change", changed, err)
+	} else if r.Len() != 2 {
+		t.Errorf("Was %d, but expected %d", r.Len(), 2)
+	}
+
+	if changed, _ := src.Load(context.Background()); changed {
+		t.Errorf("Expected unchanged records to be skipped")
+	}
+}
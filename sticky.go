@@ -0,0 +1,29 @@
+package hrw
+
+// GetSticky returns node for key keeping previous owner, so keys move only
+// when they must. Previous is kept while it's among threshold most
+// preferable nodes for key, otherwise the most preferable node is returned.
+// Moved reports whether result differs from non-empty previous, ok is false
+// when Ring has no active nodes. Threshold <= 0 treated as 1.
+func (r *Ring) GetSticky(key []byte, previous string, threshold int) (n Node, moved, ok bool) {
+	return r.view().GetSticky(key, previous, threshold)
+}
+
+// GetSticky is like Ring.GetSticky
+func (s *Snapshot) GetSticky(key []byte, previous string, threshold int) (n Node, moved, ok bool) {
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	nodes := s.GetN(key, threshold)
+	if len(nodes) == 0 {
+		return Node{}, previous != "", false
+	}
+
+	for _, node := range nodes {
+		if node.ID == previous {
+			return node, false, true
+		}
+	}
+	return nodes[0], previous != "", true
+}
@@ -0,0 +1,32 @@
+package hrw
+
+import "testing"
+
+func TestGetSticky(t *testing.T) {
+	var (
+		key = []byte("key")
+		r   = NewRing(Node{ID: "a"}, Node{ID: "b"}, Node{ID: "c"})
+		top = r.GetN(key, 3)
+	)
+
+	if n, moved, ok := r.GetSticky(key, "", 2); !ok || moved || n.ID != top[0].ID {
+		t.Errorf("Was %#v (%v, %v), but expected %#v", n.ID, moved, ok, top[0].ID)
+	}
+
+	if n, moved, _ := r.GetSticky(key, top[1].ID, 2); moved || n.ID != top[1].ID {
+		t.Errorf("Was %#v (%v), but expected %#v", n.ID, moved, top[1].ID)
+	}
+
+	if n, moved, _ := r.GetSticky(key, top[2].ID, 2); !moved || n.ID != top[0].ID {
+		t.Errorf("Was %#v (%v), but expected %#v", n.ID, moved, top[0].ID)
+	}
+
+	r.Remove(top[1].ID)
+	if n, moved, _ := r.GetSticky(key, top[1].ID, 2); !moved || n.ID != top[0].ID {
+		t.Errorf("Was %#v (%v), but expected %#v", n.ID, moved, top[0].ID)
+	}
+
+	if _, moved, ok := NewRing().GetSticky(key, "a", 1); ok || !moved {
+		t.Errorf("Was %v, %v, but expected true, false", moved, ok)
+	}
+}
@@ -0,0 +1,53 @@
+package hrw
+
+import "context"
+
+// Assignment is a key with it's nodes
+type Assignment struct {
+	Key   []byte
+	Nodes []Node
+}
+
+// Stream reads keys from channel and sends their n most preferable nodes
+// to returned channel in order of keys, every key is selected by current
+// view of Ring. Memory is bounded: next key is read only when previous
+// assignment is received. Returned channel is closed when keys channel
+// is closed or ctx is done.
+func (r *Ring) Stream(ctx context.Context, keys <-chan []byte, n int) <-chan Assignment {
+	return stream(ctx, keys, func(key []byte) []Node { return r.GetN(key, n) })
+}
+
+// Stream is like Ring.Stream, but selects all keys by Snapshot
+func (s *Snapshot) Stream(ctx context.Context, keys <-chan []byte, n int) <-chan Assignment {
+	return stream(ctx, keys, func(key []byte) []Node { return s.GetN(key, n) })
+}
+
+func stream(ctx context.Context, keys <-chan []byte, fn func(key []byte) []Node) <-chan Assignment {
+	out := make(chan Assignment)
+	go func() {
+		defer close(out)
+
+		for {
+			var (
+				key []byte
+				ok  bool
+			)
+
+			select {
+			case <-ctx.Done():
+				return
+			case key, ok = <-keys:
+				if !ok {
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- Assignment{Key: key, Nodes: fn(key)}:
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,48 @@
+package hrw
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	var (
+		r    = NewRing(testNodes(5)...)
+		keys = make(chan []byte)
+	)
+
+	go func() {
+		defer close(keys)
+		for i := 0; i < 100; i++ {
+			keys <- []byte("key-" + strconv.Itoa(i))
+		}
+	}()
+
+	var i int
+	for a := range r.Stream(context.Background(), keys, 2) {
+		if expect := []byte("key-" + strconv.Itoa(i)); !reflect.DeepEqual(a.Key, expect) {
+			t.Errorf("Was %q, but expected %q", a.Key, expect)
+		}
+
+		if expect := r.GetN(a.Key, 2); !reflect.DeepEqual(a.Nodes, expect) {
+			t.Errorf("Was %#v, but expected %#v", a.Nodes, expect)
+		}
+		i++
+	}
+
+	if i != 100 {
+		t.Errorf("Was %d assignments, but expected 100", i)
+	}
+}
+
+func TestStreamCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := NewRing(testNodes(3)...).Snapshot().Stream(ctx, make(chan []byte), 1)
+
+	cancel()
+	if _, ok := <-out; ok {
+		t.Errorf("Expected stream to be closed")
+	}
+}
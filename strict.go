@@ -0,0 +1,37 @@
+package hrw
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+var strictMode int32
+
+// SetStrict enables or disables strict mode. In strict mode functions that
+// leave unsupported input untouched (SortSliceByValue, SortSliceByIndex and
+// their variants) panic with error returned by their Try* counterparts.
+// Nil elements don't panic, such slices are sorted with nils placed last.
+func SetStrict(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&strictMode, v)
+}
+
+// Strict reports whether strict mode is enabled
+func Strict() bool {
+	return atomic.LoadInt32(&strictMode) == 1
+}
+
+func checkStrict(err error) {
+	if strictError(err) && Strict() {
+		panic(err)
+	}
+}
+
+// strictError reports whether err panics in strict mode, nil elements
+// don't since slice is sorted anyway
+func strictError(err error) bool {
+	return err != nil && !errors.Is(err, ErrNilElement)
+}
@@ -0,0 +1,89 @@
+package hrw
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTrySortSliceByValue(t *testing.T) {
+	hash := Hash(testKey)
+	cases := []struct {
+		name   string
+		slice  interface{}
+		expect error
+		// reflect marks slices supported only with reflection
+		reflect bool
+	}{
+		{name: "not slice", slice: 10, expect: ErrNotSlice},
+		{name: "nil", slice: nil, expect: ErrNotSlice},
+		{name: "unsupported element", slice: []float64{1, 2}, expect: ErrUnsupportedElement, reflect: true},
+		{name: "empty", slice: []float64{}, reflect: true},
+		{name: "strings", slice: []string{"a", "b"}},
+		{name: "hashers", slice: []hashString{"a", "b"}, reflect: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.reflect {
+				requireReflect(t)
+			}
+
+			for _, a := range []Algorithm{V1, NSPCC, V2} {
+				if err := a.TrySortSliceByValue(tc.slice, hash); !errors.Is(err, tc.expect) {
+					t.Errorf("%s: was %v, but expected %v", a, err, tc.expect)
+				}
+			}
+		})
+	}
+
+	if err := Algorithm(100).TrySortSliceByValue([]string{}, hash); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("Was %v, but expected %v", err, ErrUnknownAlgorithm)
+	}
+
+	if err := TrySortSliceByIndex(10, hash); !errors.Is(err, ErrNotSlice) {
+		t.Errorf("Was %v, but expected %v", err, ErrNotSlice)
+	}
+}
+
+func TestStrict(t *testing.T) {
+	SetStrict(true)
+	defer SetStrict(false)
+
+	if !Strict() {
+		t.Fatalf("Expected strict mode to be enabled")
+	}
+
+	expectPanic := func(name string, fn func()) {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if err, ok := recover().(error); !ok || !errors.Is(err, ErrUnsupportedElement) && !errors.Is(err, ErrNotSlice) {
+					t.Errorf("Expected panic with hrw error, got %v", err)
+				}
+			}()
+			fn()
+		})
+	}
+
+	hash := Hash(testKey)
+	expectPanic("SortSliceByValue", func() { SortSliceByValue([]byte{1, 2}, hash) })
+	expectPanic("SortSliceByIndex", func() { SortSliceByIndex(10, hash) })
+	expectPanic("SortSliceByValueNSPCC", func() { SortSliceByValueNSPCC([]byte{1, 2}, hash) })
+	expectPanic("V2", func() { V2.SortSliceByValue(10, hash) })
+
+	// supported values must not panic
+	SortSliceByValue([]string{"a", "b"}, hash)
+
+	t.Run("nil elements", func(t *testing.T) {
+		for name, sort := range map[string]func(slice []Hasher){
+			"SortSliceByValue": func(slice []Hasher) { SortSliceByValue(slice, hash) },
+			"Sorter":           func(slice []Hasher) { NewSorter(WithStrictErrors()).SortSliceByValue(slice, hash) },
+		} {
+			slice := []Hasher{hashString("a"), nil, hashString("b")}
+			sort(slice)
+
+			if slice[2] != nil {
+				t.Errorf("%s: was %#v, but expected nil last", name, slice)
+			}
+		}
+	})
+}
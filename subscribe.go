@@ -0,0 +1,134 @@
+package hrw
+
+// Event describes change of Ring
+type Event struct {
+	// Version of Ring after change
+	Version uint64
+	// Added, Removed and Changed are IDs of nodes added, removed and
+	// changed (weight, state or hash) by change
+	Added, Removed, Changed []string
+	// Moved is estimated share of key space which most preferable node
+	// was changed, it doesn't take penalties into account
+	Moved float64
+}
+
+// Subscribe returns channel of Ring change events and function to cancel
+// subscription. Events are sent without blocking, so they're dropped
+// when channel buffer is full; gaps can be detected by Event.Version.
+func (r *Ring) Subscribe(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+
+	r.mu.Lock()
+	if r.subs == nil {
+		r.subs = make(map[chan Event]struct{})
+	}
+	r.subs[ch] = struct{}{}
+	r.mu.Unlock()
+
+	return ch, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		if _, ok := r.subs[ch]; ok {
+			delete(r.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// notify sends event to subscribers, it's called under Ring lock
+func (r *Ring) notify(e Event) {
+	for ch := range r.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+func diffSnapshots(old, cur *Snapshot) Event {
+	var (
+		e       = Event{Version: cur.version}
+		i, j    int
+		reshape = old.alg != cur.alg
+	)
+
+	for i < len(old.nodes) || j < len(cur.nodes) {
+		switch {
+		case j == len(cur.nodes) || i < len(old.nodes) && old.nodes[i].ID < cur.nodes[j].ID:
+			e.Removed = append(e.Removed, old.nodes[i].ID)
+			i++
+		case i == len(old.nodes) || cur.nodes[j].ID < old.nodes[i].ID:
+			e.Added = append(e.Added, cur.nodes[j].ID)
+			j++
+		default:
+			a, b := &old.nodes[i], &cur.nodes[j]
+			if a.hash != b.hash {
+				reshape = true
+			}
+
+			if a.hash != b.hash || a.State != b.State || old.cost.weight(a.Node) != cur.cost.weight(b.Node) {
+				e.Changed = append(e.Changed, b.ID)
+			}
+			i, j = i+1, j+1
+		}
+	}
+
+	e.Moved = movedShare(old, cur, reshape)
+	return e
+}
+
+// movedShare estimates share of keys which changed owner. For HRW it's
+// total variation distance between shares of nodes, when ordering is
+// reshaped (algorithm or hash changed) keys are reassigned independently.
+func movedShare(old, cur *Snapshot, reshape bool) float64 {
+	var (
+		a, b   = old.shares(), cur.shares()
+		result float64
+	)
+
+	if reshape {
+		result = 1
+		for id, p := range a {
+			result -= p * b[id]
+		}
+		return result
+	}
+
+	for id, p := range a {
+		if d := p - b[id]; d > 0 {
+			result += d
+		}
+	}
+	return result
+}
+
+// shares returns share of key space owned by every active node,
+// only nodes of the lowest tier own keys
+func (s *Snapshot) shares() map[string]float64 {
+	var (
+		result = make(map[string]float64, len(s.nodes))
+		total  float64
+		tier   int
+		found  bool
+	)
+
+	for i := range s.nodes {
+		if s.nodes[i].State == StateActive && (!found || s.nodes[i].Tier < tier) {
+			tier, found = s.nodes[i].Tier, true
+		}
+	}
+
+	for i := range s.nodes {
+		if s.nodes[i].State == StateActive && s.nodes[i].Tier == tier {
+			w := s.cost.weight(s.nodes[i].Node)
+			result[s.nodes[i].ID] = w
+			total += w
+		}
+	}
+
+	for id := range result {
+		result[id] /= total
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+package hrw
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRingSubscribe(t *testing.T) {
+	r := NewRing(testNodes(4)...)
+	events, cancel := r.Subscribe(10)
+
+	r.Add(Node{ID: "node-4"})
+	r.Remove("node-0")
+	r.Add(Node{ID: "node-1", Weight: 2})
+	r.SetAlgorithm(V2)
+	r.Add()
+	cancel()
+	cancel()
+
+	expect := []Event{
+		{Version: 2, Added: []string{"node-4"}, Moved: 0.2},
+		{Version: 3, Removed: []string{"node-0"}, Moved: 0.2},
+		{Version: 4, Changed: []string{"node-1"}, Moved: 0.15},
+		{Version: 5, Moved: 1 - 0.4*0.4 - 3*0.2*0.2},
+		{Version: 6},
+	}
+
+	var actual []Event
+	for e := range events {
+		actual = append(actual, e)
+	}
+
+	if len(actual) != len(expect) {
+		t.Fatalf("Was %d events, but expected %d", len(actual), len(expect))
+	}
+
+	for i := range expect {
+		if math.Abs(actual[i].Moved-expect[i].Moved) > 1e-9 {
+			t.Errorf("Was %#v, but expected %#v", actual[i].Moved, expect[i].Moved)
+		}
+
+		actual[i].Moved, expect[i].Moved = 0, 0
+		if !reflect.DeepEqual(actual[i], expect[i]) {
+			t.Errorf("Was %#v, but expected %#v", actual[i], expect[i])
+		}
+	}
+}
+
+func TestMovedShareTiers(t *testing.T) {
+	var (
+		old = NewRing(Node{ID: "a"}, Node{ID: "b"}).view()
+		cur = NewRing(Node{ID: "a"}, Node{ID: "b", Tier: 1}).view()
+	)
+
+	// b of higher tier doesn't own keys anymore
+	if actual := movedShare(old, cur, false); math.Abs(actual-0.5) > 1e-9 {
+		t.Errorf("Was %#v, but expected %#v", actual, 0.5)
+	}
+}
+
+func TestRingSubscribeSlowReader(t *testing.T) {
+	r := NewRing()
+	events, cancel := r.Subscribe(1)
+	defer cancel()
+
+	r.Add(Node{ID: "a"})
+	r.Add(Node{ID: "b"})
+
+	if e := <-events; e.Version != 2 {
+		t.Errorf("Was %d, but expected %d", e.Version, 2)
+	}
+
+	select {
+	case e := <-events:
+		t.Errorf("Expected event to be dropped, got %#v", e)
+	default:
+	}
+}
@@ -0,0 +1,211 @@
+package hrw
+
+import (
+	"math/bits"
+	"sync"
+)
+
+// LookupTable answers Get in O(1) using precomputed table of slots.
+// Every key is mapped to one of slots and every slot is owned by the node
+// HRW selects for it, so membership changes move only keys of slots that
+// changed their owner. Building of table takes O(size * nodes), Add and
+// Remove update it incrementally in O(size).
+type LookupTable struct {
+	mu     sync.RWMutex
+	alg    Algorithm
+	hashFn HashFunc
+	cost   CostFunc
+	nodes  []member
+	index  map[string]int32
+	hashes []uint64
+	slots  []int32
+}
+
+// NewLookupTable creates table with size slots over active nodes.
+// Size should be much greater than count of nodes, e.g. 100 slots per node
+// keeps imbalance caused by slots themselves about 10%.
+func NewLookupTable(size int, nodes ...Node) *LookupTable {
+	t := newLookupTable(size, V1, nil, nil)
+	t.Update(nodes...)
+	return t
+}
+
+// LookupTable returns table with size slots over active nodes of Ring,
+// it uses hash, algorithm and cost function of Ring. Table doesn't follow Ring
+// membership changes, call Update, Add or Remove to change it.
+func (r *Ring) LookupTable(size int) *LookupTable {
+	s := r.view()
+	t := newLookupTable(size, s.alg, s.hashFn, s.cost)
+
+	nodes := make([]Node, 0, len(s.nodes))
+	for i := range s.nodes {
+		nodes = append(nodes, s.nodes[i].Node)
+	}
+
+	t.Update(nodes...)
+	return t
+}
+
+func newLookupTable(size int, alg Algorithm, fn HashFunc, cost CostFunc) *LookupTable {
+	t := &LookupTable{
+		alg:    alg,
+		hashFn: fn,
+		cost:   cost,
+		hashes: make([]uint64, size),
+		slots:  make([]int32, size),
+	}
+
+	for s := range t.hashes {
+		t.hashes[s] = HashUint(uint64(s))
+	}
+	return t
+}
+
+// Size returns count of slots
+func (t *LookupTable) Size() int {
+	return len(t.slots)
+}
+
+// Update rebuilds whole table over active nodes
+func (t *LookupTable) Update(nodes ...Node) {
+	var (
+		members = make([]member, 0, len(nodes))
+		index   = make(map[string]int32, len(nodes))
+	)
+
+	for _, n := range nodes {
+		if n.State != StateActive {
+			continue
+		} else if i, ok := index[n.ID]; ok {
+			members[i] = newMember(n, t.hashFn)
+			continue
+		}
+
+		index[n.ID] = int32(len(members))
+		members = append(members, newMember(n, t.hashFn))
+	}
+
+	slots := make([]int32, len(t.slots))
+	for s := range slots {
+		slots[s] = ownerOf(members, t.hashes[s], t.alg, t.cost)
+	}
+
+	t.mu.Lock()
+	t.nodes, t.index, t.slots = members, index, slots
+	t.mu.Unlock()
+}
+
+// Add adds node or updates known one, recomputing only slots node may
+// claim or lose. Inactive nodes are removed.
+func (t *LookupTable) Add(n Node) {
+	if n.State != StateActive {
+		t.Remove(n.ID)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	m := newMember(n, t.hashFn)
+	i, ok := t.index[n.ID]
+	if ok {
+		t.nodes[i] = m
+		// node could lose owned slots when it's weight decreased
+		for s, owner := range t.slots {
+			if owner == i {
+				t.slots[s] = ownerOf(t.nodes, t.hashes[s], t.alg, t.cost)
+			}
+		}
+	} else {
+		i = int32(len(t.nodes))
+		t.index[n.ID] = i
+		t.nodes = append(t.nodes, m)
+	}
+
+	for s, owner := range t.slots {
+		if owner == i {
+			continue
+		}
+
+		c := memberCandidate(&m, t.hashes[s], t.alg, t.cost)
+		if owner < 0 || c.less(t.candidate(owner, s)) {
+			t.slots[s] = i
+		}
+	}
+}
+
+// Remove removes node, recomputing only slots it owned
+func (t *LookupTable) Remove(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	i, ok := t.index[id]
+	if !ok {
+		return
+	}
+
+	last := int32(len(t.nodes) - 1)
+	delete(t.index, id)
+	if i != last {
+		t.nodes[i] = t.nodes[last]
+		t.index[t.nodes[i].ID] = i
+	}
+	t.nodes[last] = member{}
+	t.nodes = t.nodes[:last]
+
+	for s, owner := range t.slots {
+		switch owner {
+		case i:
+			t.slots[s] = ownerOf(t.nodes, t.hashes[s], t.alg, t.cost)
+		case last:
+			t.slots[s] = i
+		}
+	}
+}
+
+// Get returns node owning slot of key
+func (t *LookupTable) Get(key []byte) (Node, bool) {
+	hash := t.hashFn.hash(key)
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if len(t.nodes) == 0 || len(t.slots) == 0 {
+		return Node{}, false
+	}
+	return t.nodes[t.slots[slotOf(hash, len(t.slots))]].Node, true
+}
+
+func (t *LookupTable) candidate(i int32, slot int) candidate {
+	return memberCandidate(&t.nodes[i], t.hashes[slot], t.alg, t.cost)
+}
+
+// slotOf maps hash onto [0, size) without division
+func slotOf(hash uint64, size int) int {
+	hi, _ := bits.Mul64(hash, uint64(size))
+	return int(hi)
+}
+
+// ownerOf returns index of member HRW selects for hash or -1
+func ownerOf(nodes []member, hash uint64, alg Algorithm, cost CostFunc) int32 {
+	var (
+		owner int32 = -1
+		best  candidate
+	)
+
+	for i := range nodes {
+		c := memberCandidate(&nodes[i], hash, alg, cost)
+		if owner < 0 || c.less(best) {
+			owner, best = int32(i), c
+		}
+	}
+	return owner
+}
+
+// memberCandidate returns candidate of active member for hash,
+// members of lower tier are preferred like Ring does
+func memberCandidate(m *member, hash uint64, alg Algorithm, cost CostFunc) candidate {
+	c := alg.candidate(m.hash, hash, cost.weight(m.Node))
+	c.tier = m.Tier
+	return c
+}
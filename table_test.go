@@ -0,0 +1,158 @@
+package hrw
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestLookupTable(t *testing.T) {
+	const (
+		size = 10
+		keys = 100000
+	)
+
+	var (
+		nodes  = testNodes(size)
+		table  = NewLookupTable(size*1000, nodes...)
+		counts = make(map[string]int, size)
+		key    = make([]byte, 8)
+	)
+
+	if table.Size() != size*1000 {
+		t.Errorf("Was %d, but expected %d", table.Size(), size*1000)
+	}
+
+	for i := uint64(0); i < keys; i++ {
+		binary.BigEndian.PutUint64(key, i)
+		n, ok := table.Get(key)
+		if !ok {
+			t.Fatalf("Expected node for key %d", i)
+		}
+		counts[n.ID]++
+
+		slot := slotOf(Hash(key), table.Size())
+		if expect := NewRing(nodes...).pick(HashUint(uint64(slot)), 1)[0]; expect.ID != n.ID {
+			t.Fatalf("Was %q, but expected %q", n.ID, expect.ID)
+		}
+	}
+
+	mean := float64(keys) / size
+	delta := mean * 0.05
+	for node, count := range counts {
+		if d := mean - float64(count); d > delta || -d > delta {
+			t.Errorf("Node %s received %d keys, expected %.0f (+/- %.2f)", node, count, mean, delta)
+		}
+	}
+
+	t.Run("tiers", func(t *testing.T) {
+		tiered := testNodes(size)
+		for i := range tiered {
+			tiered[i].Tier = i % 3
+		}
+
+		r := NewRing(tiered...)
+		table := r.LookupTable(1000)
+		for slot := 0; slot < table.Size(); slot++ {
+			expect := r.pick(HashUint(uint64(slot)), 1)[0]
+			if actual := table.nodes[table.slots[slot]]; actual.ID != expect.ID {
+				t.Fatalf("Was %q, but expected %q", actual.ID, expect.ID)
+			}
+		}
+
+		// incremental updates respect tiers too
+		table.Add(Node{ID: "node-x", Tier: -1})
+		table.Remove("node-x")
+		for slot := 0; slot < table.Size(); slot++ {
+			expect := r.pick(HashUint(uint64(slot)), 1)[0]
+			if actual := table.nodes[table.slots[slot]]; actual.ID != expect.ID {
+				t.Fatalf("Was %q, but expected %q", actual.ID, expect.ID)
+			}
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if _, ok := NewLookupTable(100).Get(testKey); ok {
+			t.Errorf("Expected no node for empty table")
+		}
+	})
+
+	t.Run("from ring", func(t *testing.T) {
+		r := NewRing(nodes...)
+		r.Add(Node{ID: "node-1", State: StateDown})
+		r.SetHash(HashSHA256)
+
+		table := r.LookupTable(1000)
+		for i := uint64(0); i < 1000; i++ {
+			binary.BigEndian.PutUint64(key, i)
+			n, _ := table.Get(key)
+			if n.ID == "node-1" {
+				t.Fatalf("Inactive node was selected")
+			}
+
+			slot := slotOf(HashSHA256(key), table.Size())
+			if expect := r.pick(HashUint(uint64(slot)), 1)[0]; expect.ID != n.ID {
+				t.Fatalf("Was %q, but expected %q", n.ID, expect.ID)
+			}
+		}
+	})
+}
+
+func TestLookupTableIncremental(t *testing.T) {
+	var (
+		rnd   = rand.New(rand.NewSource(42))
+		nodes = make(map[string]Node)
+		table = NewLookupTable(2000)
+	)
+
+	owners := func(t *LookupTable) []string {
+		result := make([]string, 0, len(t.slots))
+		for _, owner := range t.slots {
+			if owner < 0 {
+				result = append(result, "")
+				continue
+			}
+			result = append(result, t.nodes[owner].ID)
+		}
+		return result
+	}
+
+	for step := 0; step < 200; step++ {
+		id := "node-" + strconv.Itoa(rnd.Intn(30))
+		switch rnd.Intn(4) {
+		case 0:
+			table.Remove(id)
+			delete(nodes, id)
+		case 1:
+			n := Node{ID: id, State: StateDown}
+			table.Add(n)
+			delete(nodes, id)
+		default:
+			n := Node{ID: id, Weight: float64(1 + rnd.Intn(4))}
+			table.Add(n)
+			nodes[id] = n
+		}
+
+		list := make([]Node, 0, len(nodes))
+		for _, n := range nodes {
+			list = append(list, n)
+		}
+
+		expect := NewLookupTable(table.Size(), list...)
+		if actual, expect := owners(table), owners(expect); !reflect.DeepEqual(actual, expect) {
+			t.Fatalf("Step %d: incremental table differs from full rebuild", step)
+		}
+	}
+}
+
+func BenchmarkLookupTable_5000(b *testing.B) {
+	table := NewLookupTable(1<<16, testNodes(5000)...)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = table.Get(testKey)
+	}
+}
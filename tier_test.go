@@ -0,0 +1,43 @@
+package hrw
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRingTiers(t *testing.T) {
+	var (
+		nodes = testNodes(6)
+		r     = NewRing(nodes...)
+	)
+
+	for i := range nodes[3:] {
+		nodes[3+i].Tier = 1
+	}
+
+	var (
+		tiered  = NewRing(nodes...)
+		tier0   = nodeIDs(NewRing(nodes[:3]...).GetN(testKey, 3))
+		tier1   = nodeIDs(NewRing(nodes[3:]...).GetN(testKey, 3))
+		overall = nodeIDs(r.GetN(testKey, 6))
+	)
+
+	if actual := nodeIDs(tiered.GetN(testKey, 2)); !reflect.DeepEqual(actual, tier0[:2]) {
+		t.Errorf("Was %#v, but expected %#v", actual, tier0[:2])
+	}
+
+	expect := append(append([]string{}, tier0...), tier1[:2]...)
+	if actual := nodeIDs(tiered.GetN(testKey, 5)); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	// spill to the next tier when preferred one is down
+	tiered.Blacklist(0, tier0...)
+	if actual := nodeIDs(tiered.GetN(testKey, 2)); !reflect.DeepEqual(actual, tier1[:2]) {
+		t.Errorf("Was %#v, but expected %#v", actual, tier1[:2])
+	}
+
+	if actual := nodeIDs(r.GetN(testKey, 6)); !reflect.DeepEqual(actual, overall) {
+		t.Errorf("Was %#v, but expected %#v", actual, overall)
+	}
+}
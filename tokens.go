@@ -0,0 +1,77 @@
+package hrw
+
+import (
+	"math"
+	"math/bits"
+)
+
+// TokenRange is contiguous range of tokens owned by node, bounds are
+// inclusive. Tokens are signed 64-bit like ones of Cassandra, see Token.
+type TokenRange struct {
+	Start int64
+	End   int64
+	Node  Node
+}
+
+// Token returns token of key, it's Hash of key shifted to signed range,
+// so tokens keep order of hashes
+func Token(key []byte) int64 {
+	return int64(Hash(key) ^ 1<<63)
+}
+
+// PartitionOfToken returns partition of token when token space is split
+// into partitions equal contiguous parts. It panics if partitions <= 0.
+func PartitionOfToken(token int64, partitions int) int {
+	if partitions <= 0 {
+		panic("hrw: PartitionOfToken called with non-positive partitions count")
+	}
+
+	hi, _ := bits.Mul64(uint64(token)^1<<63, uint64(partitions))
+	return int(hi)
+}
+
+// TokenRanges splits token space into partitions equal parts, places
+// every part on node selected by HRW for partition number and returns
+// ranges of adjacent parts of the same node in token order. Ranges cover
+// whole token space, they're empty when there are no active nodes.
+// It panics if partitions <= 0.
+func (r *Ring) TokenRanges(partitions int) []TokenRange {
+	return r.view().TokenRanges(partitions)
+}
+
+// TokenRanges is like Ring.TokenRanges
+func (s *Snapshot) TokenRanges(partitions int) []TokenRange {
+	if partitions <= 0 {
+		panic("hrw: TokenRanges called with non-positive partitions count")
+	}
+
+	var result []TokenRange
+	for p := 0; p < partitions; p++ {
+		nodes := s.pick(HashUint(uint64(p)), 1)
+		if len(nodes) == 0 {
+			return nil
+		}
+
+		end := int64(math.MaxInt64)
+		if p+1 < partitions {
+			end = partitionStart(p+1, partitions) - 1
+		}
+
+		if n := len(result); n > 0 && result[n-1].Node.ID == nodes[0].ID {
+			result[n-1].End = end
+			continue
+		}
+		result = append(result, TokenRange{Start: partitionStart(p, partitions), End: end, Node: nodes[0]})
+	}
+	return result
+}
+
+// partitionStart returns the first token of partition p
+func partitionStart(p, partitions int) int64 {
+	// ceil(p * 2^64 / partitions), so PartitionOfToken agrees with ranges
+	q, rem := bits.Div64(uint64(p), 0, uint64(partitions))
+	if rem != 0 {
+		q++
+	}
+	return int64(q ^ 1<<63)
+}
@@ -0,0 +1,61 @@
+package hrw
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPartitionOfToken(t *testing.T) {
+	for _, partitions := range []int{1, 3, 7, 1024} {
+		if p := PartitionOfToken(math.MinInt64, partitions); p != 0 {
+			t.Errorf("Was %d, but expected %d", p, 0)
+		}
+		if p := PartitionOfToken(math.MaxInt64, partitions); p != partitions-1 {
+			t.Errorf("Was %d, but expected %d", p, partitions-1)
+		}
+
+		for p := 1; p < partitions; p++ {
+			start := partitionStart(p, partitions)
+			if actual := PartitionOfToken(start, partitions); actual != p {
+				t.Errorf("Was %d, but expected %d", actual, p)
+			}
+			if actual := PartitionOfToken(start-1, partitions); actual != p-1 {
+				t.Errorf("Was %d, but expected %d", actual, p-1)
+			}
+		}
+	}
+}
+
+func TestTokenRanges(t *testing.T) {
+	r := NewRing(testNodes(5)...)
+	ranges := r.TokenRanges(64)
+
+	if ranges[0].Start != math.MinInt64 || ranges[len(ranges)-1].End != math.MaxInt64 {
+		t.Errorf("Expected ranges to cover token space: %#v", ranges)
+	}
+
+	for i := 1; i < len(ranges); i++ {
+		if ranges[i].Start != ranges[i-1].End+1 {
+			t.Errorf("Was %d, but expected %d", ranges[i].Start, ranges[i-1].End+1)
+		} else if ranges[i].Node.ID == ranges[i-1].Node.ID {
+			t.Errorf("Expected adjacent ranges of %q to be merged", ranges[i].Node.ID)
+		}
+	}
+
+	for _, key := range [][]byte{testKey, []byte("a"), []byte("b")} {
+		var (
+			token = Token(key)
+			owner = r.pick(HashUint(uint64(PartitionOfToken(token, 64))), 1)[0]
+		)
+
+		for _, rng := range ranges {
+			if rng.Start <= token && token <= rng.End && rng.Node.ID != owner.ID {
+				t.Errorf("Was %q, but expected %q", rng.Node.ID, owner.ID)
+			}
+		}
+	}
+
+	if ranges := NewRing().TokenRanges(8); ranges != nil {
+		t.Errorf("Was %#v, but expected nil", ranges)
+	}
+}
@@ -0,0 +1,171 @@
+package hrw
+
+import "sort"
+
+// Domain is a vertex of topology tree, leaves hold nodes
+type Domain struct {
+	// Name of domain, the same as element of Node.Location
+	Name string
+	// Level of domain, empty for root
+	Level string
+	// Children are nested domains ordered by name
+	Children []*Domain
+	// Nodes attached to domain, only domains of the lowest level have them
+	Nodes []Node
+}
+
+// SetTopology sets names of topology levels from the top one,
+// e.g. "region", "zone", "rack", Node.Location follows them
+func (r *Ring) SetTopology(levels ...string) {
+	r.update(func(s *Snapshot) bool {
+		s.levels = append([]string(nil), levels...)
+		return true
+	})
+}
+
+// Topology returns topology tree of Ring members
+func (r *Ring) Topology() *Domain {
+	return r.view().Topology()
+}
+
+// GetSpread returns up to n active nodes for key spreading them across
+// domains of given levels: every next node is taken from the least used
+// domain of the first level, then of the second one and so on, preferring
+// nodes in HRW order among equally used ones. So "rack", "host" levels
+// place replicas on distinct racks first and reuse racks only when all of
+// them are used. ErrNoEligibleNodes is returned when none of nodes
+// is selectable.
+func (r *Ring) GetSpread(key []byte, n int, levels ...string) ([]Node, error) {
+	return r.view().GetSpread(key, n, levels...)
+}
+
+// Topology is like Ring.Topology
+func (s *Snapshot) Topology() *Domain {
+	root := new(Domain)
+	for i := range s.nodes {
+		d := root
+		for l, level := range s.levels {
+			d = d.child(s.nodes[i].locate(l), level)
+		}
+		d.Nodes = append(d.Nodes, s.nodes[i].Node)
+	}
+	return root
+}
+
+// GetSpread is like Ring.GetSpread
+func (s *Snapshot) GetSpread(key []byte, n int, levels ...string) ([]Node, error) {
+	depths, err := s.levelDepths(levels)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.eligible(key)
+	if err != nil {
+		return nil, err
+	}
+	return spread(list, n, depths), nil
+}
+
+// spread takes up to n nodes of list spreading them across domains
+// at depths, list is modified
+func spread(list []Node, n int, depths []int) []Node {
+	var (
+		result = make([]Node, 0, n)
+		used   = make([]map[string]int, len(depths))
+	)
+
+	for i := range used {
+		used[i] = make(map[string]int)
+	}
+
+	for len(result) < n && len(list) > 0 {
+		best := 0
+		for i := 1; i < len(list) && !unused(used, depths, list[best]); i++ {
+			if spreadLess(used, depths, list[i], list[best]) {
+				best = i
+			}
+		}
+
+		for l, depth := range depths {
+			used[l][list[best].path(depth)]++
+		}
+
+		result = append(result, list[best])
+		list = append(list[:best], list[best+1:]...)
+	}
+	return result
+}
+
+func (s *Snapshot) levelDepths(levels []string) ([]int, error) {
+	depths := make([]int, 0, len(levels))
+	for _, level := range levels {
+		depth, ok := s.levelDepth(level)
+		if !ok {
+			return nil, unknownLevel(level)
+		}
+		depths = append(depths, depth)
+	}
+	return depths, nil
+}
+
+func (s *Snapshot) levelDepth(level string) (int, bool) {
+	for i := range s.levels {
+		if s.levels[i] == level {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// spreadLess reports whether node a uses less loaded domains than b
+func spreadLess(used []map[string]int, depths []int, a, b Node) bool {
+	for l, depth := range depths {
+		ua, ub := used[l][a.path(depth)], used[l][b.path(depth)]
+		if ua != ub {
+			return ua < ub
+		}
+	}
+	return false
+}
+
+// unused reports whether domains of node at all levels are unused yet,
+// so no other node can be better
+func unused(used []map[string]int, depths []int, n Node) bool {
+	for l, depth := range depths {
+		if used[l][n.path(depth)] > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// locate returns name of node domain at depth, empty for missing ones
+func (n Node) locate(depth int) string {
+	if depth < len(n.Location) {
+		return n.Location[depth]
+	}
+	return ""
+}
+
+// path returns unique key of node domain at depth
+func (n Node) path(depth int) string {
+	var key string
+	for i := 0; i <= depth; i++ {
+		key += n.locate(i) + "\x00"
+	}
+	return key
+}
+
+func (d *Domain) child(name, level string) *Domain {
+	i := sort.Search(len(d.Children), func(i int) bool { return d.Children[i].Name >= name })
+
+	if i < len(d.Children) && d.Children[i].Name == name {
+		return d.Children[i]
+	}
+
+	c := &Domain{Name: name, Level: level}
+	d.Children = append(d.Children, nil)
+	copy(d.Children[i+1:], d.Children[i:])
+	d.Children[i] = c
+	return c
+}
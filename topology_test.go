@@ -0,0 +1,70 @@
+package hrw
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func topologyNodes() []Node {
+	nodes := testNodes(12)
+	for i := range nodes {
+		zone := "zone-" + strconv.Itoa(i%3)
+		nodes[i].Location = []string{zone, zone + "/rack-" + strconv.Itoa(i/3%2)}
+	}
+	return nodes
+}
+
+func TestTopology(t *testing.T) {
+	r := NewRing(topologyNodes()...)
+	r.SetTopology("zone", "rack")
+
+	root := r.Topology()
+	if len(root.Children) != 3 {
+		t.Fatalf("Was %d zones, but expected 3", len(root.Children))
+	}
+
+	for _, zone := range root.Children {
+		if zone.Level != "zone" || len(zone.Children) != 2 {
+			t.Errorf("Was %q with %d racks, but expected zone with 2 racks", zone.Level, len(zone.Children))
+		}
+
+		for _, rack := range zone.Children {
+			if rack.Level != "rack" || len(rack.Nodes) != 2 {
+				t.Errorf("Was %q with %d nodes, but expected rack with 2 nodes", rack.Level, len(rack.Nodes))
+			}
+		}
+	}
+}
+
+func TestGetSpread(t *testing.T) {
+	r := NewRing(topologyNodes()...)
+	r.SetTopology("zone", "rack")
+
+	nodes, err := r.GetSpread(testKey, 7, "zone", "rack")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first, _ := r.Get(testKey); nodes[0].ID != first.ID {
+		t.Errorf("Was %q, but expected %q", nodes[0].ID, first.ID)
+	}
+
+	zones, racks := make(map[string]int), make(map[string]int)
+	for i, n := range nodes {
+		zones[n.Location[0]]++
+		racks[n.Location[1]]++
+
+		if i == 2 && len(zones) != 3 {
+			t.Errorf("Was %d zones for 3 nodes, but expected 3", len(zones))
+		}
+	}
+
+	if len(racks) != 6 {
+		t.Errorf("Was %d racks for 7 nodes, but expected 6", len(racks))
+	}
+
+	if _, err := r.GetSpread(testKey, 3, "host"); !errors.Is(err, ErrUnknownLevel) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrUnknownLevel)
+	}
+}
@@ -0,0 +1,71 @@
+package hrw
+
+type (
+	// Trace is a single node considered by selection, it's emitted to
+	// TraceFunc for every selectable node in order of preference
+	Trace struct {
+		// Hash of key, see Snapshot.Hash
+		Hash uint64
+		// Node considered for key
+		Node Node
+		// Raw is score of node before weighting
+		Raw uint64
+		// Score is weighted score with penalty, lower is preferred
+		Score float64
+		// Weight is share of node weight among selectable nodes
+		// of it's tier
+		Weight float64
+		// Rank is position of node in order of preference by score,
+		// before pins and anti-affinity groups are applied
+		Rank int
+		// Selected is position of node in returned nodes,
+		// -1 when node isn't returned
+		Selected int
+	}
+
+	// TraceFunc receives traces of selection, it's called synchronously
+	// by selection methods, so it should be cheap
+	TraceFunc func(Trace)
+)
+
+// SetTrace sets function receiving traces of every selection made by Ring,
+// nil disables tracing. Tracing doesn't change placement, so Version isn't
+// changed.
+func (r *Ring) SetTrace(fn TraceFunc) {
+	r.update(func(s *Snapshot) bool {
+		s.trace = fn
+		return false
+	})
+}
+
+// emitTrace passes candidates ordered by preference to trace function,
+// result is list of nodes returned for them
+func (s *Snapshot) emitTrace(hash uint64, list []candidate, result []Node) {
+	total := make(map[int]float64)
+	for _, c := range list {
+		total[c.tier] += s.cost.weight(s.nodes[c.index].Node)
+	}
+
+	selected := make(map[string]int, len(result))
+	for i, node := range result {
+		selected[node.ID] = i
+	}
+
+	for rank, c := range list {
+		node := s.nodes[c.index].Node
+		pos, ok := selected[node.ID]
+		if !ok {
+			pos = -1
+		}
+
+		s.trace(Trace{
+			Hash:     hash,
+			Node:     node,
+			Raw:      c.raw,
+			Score:    c.score,
+			Weight:   s.cost.weight(node) / total[c.tier],
+			Rank:     rank,
+			Selected: pos,
+		})
+	}
+}
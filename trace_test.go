@@ -0,0 +1,137 @@
+package hrw
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	var (
+		traces []Trace
+		r      = NewRing(testNodes(5)...)
+	)
+
+	version := r.Version()
+	r.SetTrace(func(tr Trace) { traces = append(traces, tr) })
+	if r.Version() != version {
+		t.Errorf("Was %d, but expected %d", r.Version(), version)
+	}
+
+	nodes := r.GetN(testKey, 2)
+	if len(traces) != 5 {
+		t.Fatalf("Was %d, but expected %d", len(traces), 5)
+	}
+
+	var (
+		ids   []string
+		total float64
+		hash  = r.Snapshot().Hash(testKey)
+	)
+
+	for i, tr := range traces {
+		if tr.Rank != i || tr.Hash != hash {
+			t.Errorf("Was %#v, but expected rank %d and hash %#x", tr, i, hash)
+		}
+
+		expect := -1
+		if i < len(nodes) {
+			expect = i
+		}
+		if tr.Selected != expect {
+			t.Errorf("Was %d, but expected %d", tr.Selected, expect)
+		}
+		if i < len(nodes) {
+			ids = append(ids, tr.Node.ID)
+		}
+		total += tr.Weight
+	}
+
+	if expect := nodeIDs(nodes); !reflect.DeepEqual(ids, expect) {
+		t.Errorf("Was %#v, but expected %#v", ids, expect)
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("Was %v, but expected %v", total, 1)
+	}
+
+	traces = nil
+	r.SetTrace(nil)
+	r.Get(testKey)
+	if traces != nil {
+		t.Errorf("Was %#v, but expected nil", traces)
+	}
+}
+
+func TestTraceSelected(t *testing.T) {
+	var traces []Trace
+
+	selected := func(nodes []Node) {
+		t.Helper()
+
+		byID := make(map[string]Trace, len(traces))
+		for _, tr := range traces {
+			byID[tr.Node.ID] = tr
+		}
+
+		for i, n := range nodes {
+			if actual := byID[n.ID].Selected; actual != i {
+				t.Errorf("Was %d for %q, but expected %d", actual, n.ID, i)
+			}
+		}
+	}
+
+	t.Run("pinned", func(t *testing.T) {
+		r := NewRing(testNodes(5)...)
+		r.SetTrace(func(tr Trace) { traces = append(traces, tr) })
+
+		last := r.GetN(testKey, 5)[4]
+		r.Pin(testKey, last.ID)
+
+		traces = nil
+		nodes := r.GetN(testKey, 2)
+		if nodes[0].ID != last.ID {
+			t.Fatalf("Was %q, but expected pinned %q", nodes[0].ID, last.ID)
+		}
+		selected(nodes)
+
+		if tr := traces[len(traces)-1]; tr.Node.ID != last.ID || tr.Selected != 0 {
+			t.Errorf("Was %#v, but expected pinned node selected first", tr)
+		}
+	})
+
+	t.Run("groups", func(t *testing.T) {
+		nodes := testNodes(4)
+		for i := range nodes {
+			nodes[i].Group = "group"
+		}
+		nodes[3].Group = "other"
+
+		r := NewRing(nodes...)
+		r.SetTrace(func(tr Trace) { traces = append(traces, tr) })
+
+		traces = nil
+		selected(r.GetN(testKey, 2))
+	})
+
+	t.Run("tiers", func(t *testing.T) {
+		nodes := testNodes(4)
+		nodes[0].Weight, nodes[2].Tier, nodes[3].Tier = 3, 1, 1
+
+		r := NewRing(nodes...)
+		r.SetTrace(func(tr Trace) { traces = append(traces, tr) })
+
+		traces = nil
+		r.Get(testKey)
+
+		total := make(map[int]float64)
+		for _, tr := range traces {
+			total[tr.Node.Tier] += tr.Weight
+		}
+
+		for tier, w := range total {
+			if math.Abs(w-1) > 1e-9 {
+				t.Errorf("Was %v for tier %d, but expected %v", w, tier, 1)
+			}
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package hrw
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Transport is http.RoundTripper sending requests to backends selected
+// by Ring for affinity key of request. Request host is rewritten to address
+// of the most preferable backend, on connection error request is sent to
+// the next one. Requests of non-idempotent methods are resent only when
+// connection wasn't established, so they're never sent twice.
+type Transport struct {
+	Ring *Ring
+	// Base sends rewritten requests, http.DefaultTransport by default
+	Base http.RoundTripper
+	// Key returns affinity key of request, AffinityPath by default
+	Key func(r *http.Request) []byte
+	// Address returns host of backend, node ID used by default
+	Address func(n Node) string
+	// Attempts is count of backends tried, values <= 0 mean all of them
+	Attempts int
+}
+
+// AffinityPath returns URL path of request as affinity key
+func AffinityPath(r *http.Request) []byte {
+	return []byte(r.URL.Path)
+}
+
+// AffinityHeader returns function using header of request as affinity key
+func AffinityHeader(name string) func(r *http.Request) []byte {
+	return func(r *http.Request) []byte {
+		return []byte(r.Header.Get(name))
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var (
+		base    = t.Base
+		key     = t.Key
+		address = t.Address
+	)
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if key == nil {
+		key = AffinityPath
+	}
+	if address == nil {
+		address = func(n Node) string { return n.ID }
+	}
+
+	s := t.Ring.view()
+	attempts := t.Attempts
+	if attempts <= 0 {
+		attempts = s.Len()
+	}
+
+	backends := s.GetN(key(req), attempts)
+	if len(backends) == 0 {
+		return nil, fmt.Errorf("%w: no backends for %s", ErrNoEligibleNodes, req.URL)
+	}
+
+	var err error
+	for i, n := range backends {
+		out := req.Clone(req.Context())
+		out.URL.Host, out.Host = address(n), address(n)
+
+		if i > 0 && req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return nil, err
+			} else if out.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		var resp *http.Response
+		if resp, err = base.RoundTrip(out); err == nil {
+			return resp, nil
+		} else if req.Context().Err() != nil || !retryable(req, err) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// retryable reports whether request failed with err can be sent again
+func retryable(req *http.Request, err error) bool {
+	var op *net.OpError
+	if errors.As(err, &op) && op.Op == "dial" {
+		return true
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,97 @@
+package hrw
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	// closed listener gives address refusing connections
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := ln.Addr().String()
+	_ = ln.Close()
+
+	var (
+		live  = strings.TrimPrefix(srv.URL, "http://")
+		ring  = NewRing(Node{ID: dead}, Node{ID: live})
+		key   []byte
+		order []Node
+	)
+
+	// find key preferring dead backend
+	for i := 0; len(order) == 0 || order[0].ID != dead; i++ {
+		key = []byte("/" + strings.Repeat("k", i))
+		order = ring.GetN(key, 2)
+	}
+
+	client := &http.Client{Transport: &Transport{Ring: ring}}
+	resp, err := client.Post("http://backend"+string(key), "text/plain", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if body, _ := io.ReadAll(resp.Body); string(body) != "hello" {
+		t.Errorf("Was %q, but expected %q", body, "hello")
+	}
+}
+
+func TestTransportAffinity(t *testing.T) {
+	var (
+		hosts []string
+		ring  = NewRing(testNodes(3)...)
+		tr    = &Transport{
+			Ring: ring,
+			Key:  AffinityHeader("X-User"),
+			Base: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				hosts = append(hosts, r.URL.Host)
+				return nil, errors.New("failed")
+			}),
+		}
+	)
+
+	req, _ := http.NewRequest(http.MethodPost, "http://backend/", nil)
+	req.Header.Set("X-User", "alice")
+
+	// non-idempotent request isn't resent after connection was made
+	if _, err := tr.RoundTrip(req); err == nil {
+		t.Errorf("Expected error")
+	}
+
+	expect, _ := ring.Get([]byte("alice"))
+	if len(hosts) != 1 || hosts[0] != expect.ID {
+		t.Errorf("Was %#v, but expected %#v", hosts, []string{expect.ID})
+	}
+
+	hosts = nil
+	req.Method = http.MethodGet
+	if _, err := tr.RoundTrip(req); err == nil {
+		t.Errorf("Expected error")
+	} else if len(hosts) != 3 {
+		t.Errorf("Was %d, but expected %d attempts", len(hosts), 3)
+	}
+
+	if _, err := (&Transport{Ring: NewRing()}).RoundTrip(req); !errors.Is(err, ErrNoEligibleNodes) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrNoEligibleNodes)
+	}
+}
@@ -0,0 +1,157 @@
+package hrw
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// ExpiryAction is applied to nodes which missed heartbeats
+	ExpiryAction uint8
+
+	lease struct {
+		ttl      time.Duration
+		deadline time.Time
+		expired  bool
+	}
+)
+
+const (
+	// ExpireRemove removes expired nodes from Ring
+	ExpireRemove ExpiryAction = iota
+	// ExpireDown marks expired nodes as StateDown,
+	// the next heartbeat makes them active again
+	ExpireDown
+)
+
+// SetExpiryAction sets action applied to expired nodes, ExpireRemove by default
+func (r *Ring) SetExpiryAction(a ExpiryAction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expiry = a
+}
+
+// AddWithTTL adds nodes which expire when Heartbeat isn't called for them
+// during ttl, expired nodes are handled by Expire
+func (r *Ring) AddWithTTL(ttl time.Duration, nodes ...Node) {
+	r.Add(nodes...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.leases == nil {
+		r.leases = make(map[string]*lease)
+	}
+
+	deadline := r.clock().Add(ttl)
+	for _, n := range nodes {
+		r.leases[n.ID] = &lease{ttl: ttl, deadline: deadline}
+	}
+}
+
+// Heartbeat extends TTL of nodes and returns count of known ones,
+// nodes marked down by ExpireDown become active again
+func (r *Ring) Heartbeat(ids ...string) int {
+	var (
+		known   int
+		revived []string
+	)
+
+	r.mu.Lock()
+	now := r.clock()
+	for _, id := range ids {
+		l, ok := r.leases[id]
+		if !ok {
+			continue
+		}
+
+		known++
+		l.deadline = now.Add(l.ttl)
+		if l.expired {
+			l.expired = false
+			revived = append(revived, id)
+		}
+	}
+
+	// leases and states are changed under the same lock, so concurrent
+	// Expire can't mark revived node down after it
+	if len(revived) > 0 {
+		r.setStateLocked(StateActive, revived)
+	}
+	r.mu.Unlock()
+	return known
+}
+
+// Expire applies expiry action to nodes which TTL is over and returns their IDs
+func (r *Ring) Expire() []string {
+	var expired []string
+
+	r.mu.Lock()
+	now, action := r.clock(), r.expiry
+	for id, l := range r.leases {
+		if l.expired || now.Before(l.deadline) {
+			continue
+		}
+
+		expired = append(expired, id)
+		if action == ExpireDown {
+			l.expired = true
+		} else {
+			delete(r.leases, id)
+		}
+	}
+
+	if action == ExpireDown && len(expired) > 0 {
+		r.setStateLocked(StateDown, expired)
+	}
+	r.mu.Unlock()
+
+	if len(expired) == 0 {
+		return nil
+	} else if action != ExpireDown {
+		r.Remove(expired...)
+	}
+	return expired
+}
+
+// RunExpiry calls Expire every interval until ctx is done
+func (r *Ring) RunExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Expire()
+		}
+	}
+}
+
+func (r *Ring) setState(state State, ids []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.setStateLocked(state, ids)
+}
+
+// setStateLocked is like setState, but r.mu must be held by caller
+func (r *Ring) setStateLocked(state State, ids []string) {
+	r.updateLocked(func(s *Snapshot) bool {
+		var changed bool
+		for _, id := range ids {
+			if i, ok := findMember(s.nodes, id); ok && s.nodes[i].State != state {
+				s.nodes[i].State = state
+				changed = true
+			}
+		}
+		return changed
+	})
+}
+
+func (r *Ring) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
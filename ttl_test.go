@@ -0,0 +1,140 @@
+package hrw
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRingExpire(t *testing.T) {
+	var (
+		now = time.Unix(0, 0)
+		r   = NewRing(Node{ID: "static"})
+	)
+
+	r.now = func() time.Time { return now }
+	r.AddWithTTL(time.Minute, Node{ID: "a"}, Node{ID: "b"})
+
+	now = now.Add(30 * time.Second)
+	if actual := r.Heartbeat("a", "unknown"); actual != 1 {
+		t.Errorf("Was %d, but expected %d", actual, 1)
+	}
+
+	if expired := r.Expire(); expired != nil {
+		t.Errorf("Expected no expired nodes, got %#v", expired)
+	}
+
+	now = now.Add(45 * time.Second)
+	if expired := r.Expire(); !reflect.DeepEqual(expired, []string{"b"}) {
+		t.Errorf("Was %#v, but expected %#v", expired, []string{"b"})
+	}
+
+	if actual := nodeIDs(r.Nodes()); !reflect.DeepEqual(actual, []string{"a", "static"}) {
+		t.Errorf("Was %#v, but expected %#v", actual, []string{"a", "static"})
+	}
+
+	if actual := r.Heartbeat("b"); actual != 0 {
+		t.Errorf("Was %d, but expected %d", actual, 0)
+	}
+}
+
+func TestRingExpireDown(t *testing.T) {
+	var (
+		now = time.Unix(0, 0)
+		r   = NewRing()
+	)
+
+	r.now = func() time.Time { return now }
+	r.SetExpiryAction(ExpireDown)
+	r.AddWithTTL(time.Minute, Node{ID: "a"}, Node{ID: "b"})
+
+	now = now.Add(time.Minute)
+	expired := r.Expire()
+	sort.Strings(expired)
+	if !reflect.DeepEqual(expired, []string{"a", "b"}) {
+		t.Errorf("Was %#v, but expected %#v", expired, []string{"a", "b"})
+	}
+
+	if _, ok := r.Get(testKey); ok {
+		t.Errorf("Expected no active nodes")
+	}
+
+	if expired := r.Expire(); expired != nil {
+		t.Errorf("Expected no expired nodes, got %#v", expired)
+	}
+
+	r.Heartbeat("a")
+	if n, _ := r.Get(testKey); n.ID != "a" {
+		t.Errorf("Was %#v, but expected %#v", n.ID, "a")
+	}
+}
+
+func TestRingExpireHeartbeatRace(t *testing.T) {
+	r := NewRing()
+	r.SetExpiryAction(ExpireDown)
+	// zero TTL makes every Expire mark heartbeating nodes down again
+	r.AddWithTTL(0, testNodes(16)...)
+	ids := nodeIDs(r.Nodes())
+
+	var (
+		wg   sync.WaitGroup
+		done = make(chan struct{})
+	)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			r.Expire()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			r.Heartbeat(ids...)
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// state of node must agree with it's lease whenever lock is free,
+	// otherwise delayed state change can outlive heartbeat reviving node
+	for stop := false; !stop; {
+		select {
+		case <-done:
+			stop = true
+		default:
+		}
+
+		r.mu.Lock()
+		for _, n := range r.view().nodes {
+			if l := r.leases[n.ID]; l.expired != (n.State == StateDown) {
+				t.Fatalf("Node %q is %v, but lease expired is %v", n.ID, n.State, l.expired)
+			}
+		}
+		r.mu.Unlock()
+	}
+
+	r.Heartbeat(ids...)
+	if actual := len(r.GetN(testKey, len(ids))); actual != len(ids) {
+		t.Errorf("Was %d, but expected %d", actual, len(ids))
+	}
+}
+
+func TestRingRunExpiry(t *testing.T) {
+	r := NewRing()
+	r.AddWithTTL(time.Millisecond, Node{ID: "a"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	r.RunExpiry(ctx, time.Millisecond)
+	if actual := r.Len(); actual != 0 {
+		t.Errorf("Was %d, but expected %d", actual, 0)
+	}
+}
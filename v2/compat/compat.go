@@ -0,0 +1,175 @@
+// Package compat preserves API and orderings of v1 on top of v2 core, so
+// code can migrate to v2 package by package. Import it in place of v1:
+//
+//	import hrw "github.com/im-kulikov/hrw/v2/compat"
+//
+// Like v1 it uses reflection and leaves unsupported slices untouched.
+package compat
+
+import (
+	"encoding/binary"
+	"reflect"
+
+	"github.com/im-kulikov/hrw/v2"
+)
+
+// Hasher interface used by SortSliceByValue
+type Hasher interface{ Hash() uint64 }
+
+var hasherType = reflect.TypeOf((*Hasher)(nil)).Elem()
+
+// Hash uses murmur3 hash to return uint64
+func Hash(key []byte) uint64 {
+	return hrw.Hash(key)
+}
+
+// SortByWeight receive nodes and hash, and sort it by weight
+func SortByWeight(nodes []uint64, hash uint64) []uint64 {
+	order := hrw.Order(nodes, hash)
+	result := make([]uint64, 0, len(order))
+	for _, i := range order {
+		result = append(result, uint64(i))
+	}
+	return result
+}
+
+// SortSliceByValue received []T and hash to sort by value-weight,
+// unsupported values are left untouched. Nil elements of Hasher slices
+// are placed last in original order.
+//
+// Integers are hashed like v1 does: elements of []int, []int64, []int16
+// and []int8 as 8 byte big endian two's complement padded by 8 zero bytes,
+// elements of []int32 as 4 bytes padded by 12 zero bytes.
+func SortSliceByValue(slice interface{}, hash uint64) {
+	val := reflect.ValueOf(slice)
+	if val.Kind() != reflect.Slice || val.Len() == 0 {
+		return
+	}
+
+	var (
+		length = val.Len()
+		rule   = make([]uint64, 0, length)
+		nils   []int
+	)
+
+	switch slice := slice.(type) {
+	case []int:
+		for _, v := range slice {
+			rule = append(rule, hrw.Weight(hashInt(int64(v)), hash))
+		}
+	case []int64:
+		for _, v := range slice {
+			rule = append(rule, hrw.Weight(hashInt(v), hash))
+		}
+	case []int16:
+		for _, v := range slice {
+			rule = append(rule, hrw.Weight(hashInt(int64(v)), hash))
+		}
+	case []int8:
+		for _, v := range slice {
+			rule = append(rule, hrw.Weight(hashInt(int64(v)), hash))
+		}
+	case []int32:
+		var key [16]byte
+		for _, v := range slice {
+			binary.BigEndian.PutUint32(key[:], uint32(v))
+			rule = append(rule, hrw.Weight(Hash(key[:]), hash))
+		}
+	case []string:
+		for _, v := range slice {
+			rule = append(rule, hrw.Weight(hash, hrw.HashString(v)))
+		}
+	default:
+		elem := val.Type().Elem()
+		if !elem.Implements(hasherType) && elem.Kind() != reflect.Interface {
+			return
+		}
+
+		for i := 0; i < length; i++ {
+			v := val.Index(i)
+			if isNil(v) {
+				nils = append(nils, i)
+				continue
+			}
+
+			h, ok := v.Interface().(Hasher)
+			if !ok {
+				return
+			}
+			rule = append(rule, hrw.Weight(hash, h.Hash()))
+		}
+	}
+
+	permute(reflect.Swapper(slice), withNils(hrw.Order(rule, hash), nils, length))
+}
+
+// SortSliceByIndex received []T and hash to sort by index-weight
+func SortSliceByIndex(slice interface{}, hash uint64) {
+	val := reflect.ValueOf(slice)
+	if val.Kind() != reflect.Slice {
+		return
+	}
+
+	rule := make([]uint64, val.Len())
+	for i := range rule {
+		rule[i] = uint64(i)
+	}
+	permute(reflect.Swapper(slice), hrw.Order(rule, hash))
+}
+
+// hashInt returns hash of v the way v1 hashes elements of []int
+func hashInt(v int64) uint64 {
+	var key [16]byte
+	binary.BigEndian.PutUint64(key[:], uint64(v))
+	return Hash(key[:])
+}
+
+// isNil reports whether v is nil interface, pointer, map, slice or function
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// withNils maps order of non-nil elements to indexes of whole slice
+// and appends indexes of nil elements
+func withNils(order []int, nils []int, length int) []int {
+	if len(nils) == 0 {
+		return order
+	}
+
+	index := make([]int, 0, length-len(nils))
+	for i, n := 0, 0; i < length; i++ {
+		if n < len(nils) && nils[n] == i {
+			n++
+			continue
+		}
+		index = append(index, i)
+	}
+
+	result := make([]int, 0, length)
+	for _, i := range order {
+		result = append(result, index[i])
+	}
+	return append(result, nils...)
+}
+
+// permute reorders slice by swap, so it's i-th element is element
+// perm[i] of original slice
+func permute(swap func(i, j int), perm []int) {
+	done := make([]bool, len(perm))
+	for i := range perm {
+		for j := i; !done[j]; {
+			done[j] = true
+			k := perm[j]
+			if k == i {
+				break
+			}
+			swap(j, k)
+			j = k
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package compat
+
+import (
+	"reflect"
+	"testing"
+)
+
+type hashUint uint64
+
+func (h hashUint) Hash() uint64 { return uint64(h) }
+
+// expectations are produced by v1 for Hash("key")
+func TestSortSliceByValue(t *testing.T) {
+	hash := Hash([]byte("key"))
+
+	cases := []struct {
+		actual, expect interface{}
+	}{
+		{
+			actual: []int{-2, -1, 0, 1, 2, 3, 4, 5},
+			expect: []int{4, 5, -2, 3, -1, 1, 0, 2},
+		},
+		{
+			actual: []int32{-2, -1, 0, 1, 2, 3, 4, 5},
+			expect: []int32{5, 1, -1, 4, 3, 2, -2, 0},
+		},
+		{
+			actual: []string{"a", "b", "c", "d", "e", "f"},
+			expect: []string{"e", "a", "f", "c", "b", "d"},
+		},
+		{
+			actual: []float64{1, 2, 3},
+			expect: []float64{1, 2, 3},
+		},
+	}
+
+	for _, tc := range cases {
+		SortSliceByValue(tc.actual, hash)
+		if !reflect.DeepEqual(tc.actual, tc.expect) {
+			t.Errorf("Was %#v, but expected %#v", tc.actual, tc.expect)
+		}
+	}
+}
+
+func TestSortSliceByValueNil(t *testing.T) {
+	var (
+		actual = []interface{}{hashUint(1), nil, hashUint(2), hashUint(3), nil}
+		expect = []interface{}{hashUint(1), hashUint(2), hashUint(3)}
+	)
+
+	SortSliceByValue(actual, 42)
+	SortSliceByValue(expect, 42)
+	if expect = append(expect, nil, nil); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
+
+func TestSortSliceByIndex(t *testing.T) {
+	actual := []string{"a", "b", "c", "d", "e", "f"}
+	SortSliceByIndex(actual, Hash([]byte("key")))
+
+	if expect := []string{"b", "a", "f", "d", "c", "e"}; !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
+
+func TestSortByWeight(t *testing.T) {
+	actual := SortByWeight([]uint64{10, 20, 30, 40, 50}, Hash([]byte("key")))
+
+	if expect := []uint64{2, 0, 3, 1, 4}; !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
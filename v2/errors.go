@@ -0,0 +1,33 @@
+package hrw
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrEmptyInput returned when input required to be non-empty is empty
+	ErrEmptyInput = errors.New("hrw: empty input")
+	// ErrWeightsLengthMismatch returned when weights don't match elements
+	ErrWeightsLengthMismatch = errors.New("hrw: weights length mismatch")
+	// ErrInvalidWeight returned for weights which aren't positive numbers
+	ErrInvalidWeight = errors.New("hrw: invalid weight")
+	// ErrNoEligibleNodes returned when there are no nodes to select
+	ErrNoEligibleNodes = errors.New("hrw: no eligible nodes")
+	// ErrInvalidNode returned for nodes which can't be added into Ring
+	ErrInvalidNode = errors.New("hrw: invalid node")
+	// ErrUnknownNode returned for IDs missing in Ring
+	ErrUnknownNode = errors.New("hrw: unknown node")
+)
+
+func emptyInput(what string) error {
+	return fmt.Errorf("%w: no %s", ErrEmptyInput, what)
+}
+
+func weightsMismatch(weights, length int) error {
+	return fmt.Errorf("%w: %d weights for %d elements", ErrWeightsLengthMismatch, weights, length)
+}
+
+func invalidCapacity(i int, c float64) error {
+	return fmt.Errorf("%w: %v at %d", ErrInvalidWeight, c, i)
+}
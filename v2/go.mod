@@ -0,0 +1,3 @@
+module github.com/im-kulikov/hrw/v2
+
+go 1.19
@@ -0,0 +1,179 @@
+// Package hrw implements Rendezvous hashing.
+// http://en.wikipedia.org/wiki/Rendezvous_hashing.
+//
+// Version 2 keeps the minimal API: Ring is the primary type holding nodes,
+// free functions order slices and are thin wrappers over the same core.
+// Element types are checked at compile time by type parameters instead of
+// reflection, invalid input is reported by errors instead of being
+// silently left untouched. Integers of every type are hashed as 8 byte
+// big endian two's complement, so equal numbers are placed equally.
+//
+// Order places nodes like SortByWeight of v1 and Ring places them like
+// Ring of v1 with V2 algorithm, so keys don't move during migration.
+package hrw
+
+import (
+	"math"
+	"sort"
+
+	"github.com/im-kulikov/hrw/v2/internal/murmur3"
+)
+
+// Integer is a constraint of integer types ordered by SortInts
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Hash returns murmur3 hash of key
+func Hash(key []byte) uint64 {
+	return murmur3.Sum64(key)
+}
+
+// HashString returns Hash of s without converting it to []byte
+func HashString(s string) uint64 {
+	return murmur3.Sum64String(s)
+}
+
+// HashInt returns Hash of 8 byte big endian two's complement of v
+func HashInt[T Integer](v T) uint64 {
+	return murmur3.Sum64Uint64(uint64(v))
+}
+
+// Weight mixes hashes of node and key, nodes with lower weights are
+// preferred
+func Weight(node, key uint64) uint64 {
+	return murmur3.Fmix64(node ^ key)
+}
+
+// Score returns Weight of node scaled by it's capacity, so node receives
+// share of keys proportional to capacity. Lower scores are preferred.
+func Score(node, key uint64, capacity float64) float64 {
+	u := (float64(Weight(node, key)>>11) + 0.5) / (1 << 53)
+	return -math.Log1p(-u) / capacity
+}
+
+// Order returns indexes of nodes in order of preference for key.
+// Ties of weights are broken by node values and then by indexes.
+func Order(nodes []uint64, key uint64) []int {
+	return order(nodes, key, nil)
+}
+
+// OrderWeighted is like Order, but nodes are ordered by Score with given
+// capacities. Capacities must be as many as nodes and positive.
+func OrderWeighted(nodes []uint64, capacities []float64, key uint64) ([]int, error) {
+	if len(capacities) != len(nodes) {
+		return nil, weightsMismatch(len(capacities), len(nodes))
+	}
+
+	for i, c := range capacities {
+		if !(c > 0) || math.IsInf(c, 1) {
+			return nil, invalidCapacity(i, c)
+		}
+	}
+	return order(nodes, key, capacities), nil
+}
+
+// SortFunc sorts s in order of preference for key, hash returns hash
+// of element
+func SortFunc[T any](s []T, key uint64, hash func(T) uint64) {
+	apply(s, order(hashes(s, hash), key, nil))
+}
+
+// SortWeightedFunc is like SortFunc, but elements are ordered by Score
+// with given capacities, see OrderWeighted
+func SortWeightedFunc[T any](s []T, capacities []float64, key uint64, hash func(T) uint64) error {
+	idx, err := OrderWeighted(hashes(s, hash), capacities, key)
+	if err != nil {
+		return err
+	}
+
+	apply(s, idx)
+	return nil
+}
+
+// SortStrings sorts s in order of preference for key
+func SortStrings[T ~string](s []T, key uint64) {
+	SortFunc(s, key, func(v T) uint64 { return HashString(string(v)) })
+}
+
+// SortInts sorts s in order of preference for key, see HashInt
+func SortInts[T Integer](s []T, key uint64) {
+	SortFunc(s, key, HashInt[T])
+}
+
+// Top returns the most preferable element of s for key,
+// ErrEmptyInput is returned for empty s
+func Top[T any](s []T, key uint64, hash func(T) uint64) (T, error) {
+	var (
+		best   T
+		weight uint64
+		node   uint64
+	)
+
+	if len(s) == 0 {
+		return best, emptyInput("slice")
+	}
+
+	for i, v := range s {
+		h := hash(v)
+		if w := Weight(h, key); i == 0 || w < weight || w == weight && h < node {
+			best, weight, node = v, w, h
+		}
+	}
+	return best, nil
+}
+
+// hashes returns hashes of elements of s
+func hashes[T any](s []T, hash func(T) uint64) []uint64 {
+	result := make([]uint64, len(s))
+	for i, v := range s {
+		result[i] = hash(v)
+	}
+	return result
+}
+
+// order returns indexes of nodes ordered by weights or by scores when
+// capacities are given
+func order(nodes []uint64, key uint64, capacities []float64) []int {
+	var (
+		idx     = make([]int, len(nodes))
+		weights = make([]uint64, len(nodes))
+		scores  []float64
+	)
+
+	for i, node := range nodes {
+		idx[i], weights[i] = i, Weight(node, key)
+	}
+
+	if capacities != nil {
+		scores = make([]float64, len(nodes))
+		for i, node := range nodes {
+			scores[i] = Score(node, key, capacities[i])
+		}
+	}
+
+	sort.Slice(idx, func(i, j int) bool {
+		a, b := idx[i], idx[j]
+		switch {
+		case scores != nil && scores[a] != scores[b]:
+			return scores[a] < scores[b]
+		case weights[a] != weights[b]:
+			return weights[a] < weights[b]
+		case nodes[a] != nodes[b]:
+			return nodes[a] < nodes[b]
+		default:
+			return a < b
+		}
+	})
+	return idx
+}
+
+// apply reorders s so it's i-th element is s[idx[i]]
+func apply[T any](s []T, idx []int) {
+	sorted := make([]T, len(s))
+	for i, j := range idx {
+		sorted[i] = s[j]
+	}
+	copy(s, sorted)
+}
@@ -0,0 +1,116 @@
+package hrw
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestOrder(t *testing.T) {
+	nodes := []uint64{10, 20, 30, 40, 50}
+	order := Order(nodes, 42)
+
+	for i := 1; i < len(order); i++ {
+		if a, b := Weight(nodes[order[i-1]], 42), Weight(nodes[order[i]], 42); a > b {
+			t.Errorf("Was %d before %d, but expected ascending weights", a, b)
+		}
+	}
+
+	if again := Order(nodes, 42); !reflect.DeepEqual(again, order) {
+		t.Errorf("Was %#v, but expected %#v", again, order)
+	}
+}
+
+func TestOrderWeighted(t *testing.T) {
+	nodes := []uint64{1, 2, 3}
+
+	if _, err := OrderWeighted(nodes, []float64{1, 1}, 0); !errors.Is(err, ErrWeightsLengthMismatch) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrWeightsLengthMismatch)
+	}
+	if _, err := OrderWeighted(nodes, []float64{1, 0, 1}, 0); !errors.Is(err, ErrInvalidWeight) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrInvalidWeight)
+	}
+
+	// heavier node wins proportionally more keys
+	wins := make([]int, len(nodes))
+	for key := uint64(0); key < 10000; key++ {
+		order, err := OrderWeighted(nodes, []float64{1, 1, 2}, HashInt(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		wins[order[0]]++
+	}
+
+	if wins[2] < 4500 || wins[2] > 5500 {
+		t.Errorf("Was %d wins of heavy node, but expected about %d", wins[2], 5000)
+	}
+}
+
+func TestSortFunc(t *testing.T) {
+	var (
+		actual = []string{"a", "b", "c", "d", "e"}
+		expect = make([]string, 0, len(actual))
+		key    = HashString("key")
+	)
+
+	nodes := make([]uint64, 0, len(actual))
+	for _, s := range actual {
+		nodes = append(nodes, HashString(s))
+	}
+	for _, i := range Order(nodes, key) {
+		expect = append(expect, actual[i])
+	}
+
+	SortStrings(actual, key)
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	top, err := Top([]string{"a", "b", "c", "d", "e"}, key, HashString)
+	if err != nil || top != expect[0] {
+		t.Errorf("Was %q (%v), but expected %q", top, err, expect[0])
+	}
+
+	if _, err := Top([]string(nil), key, HashString); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrEmptyInput)
+	}
+}
+
+func TestSortInts(t *testing.T) {
+	var (
+		small = []int8{-1, 0, 1, 2, 3}
+		large = []int64{-1, 0, 1, 2, 3}
+	)
+
+	SortInts(small, 42)
+	SortInts(large, 42)
+	for i := range small {
+		if int64(small[i]) != large[i] {
+			t.Errorf("Was %#v, but expected %#v", small, large)
+			break
+		}
+	}
+}
+
+func TestSortWeightedFunc(t *testing.T) {
+	s := []int{1, 2, 3}
+	if err := SortWeightedFunc(s, []float64{1}, 0, HashInt[int]); !errors.Is(err, ErrWeightsLengthMismatch) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrWeightsLengthMismatch)
+	}
+	if !reflect.DeepEqual(s, []int{1, 2, 3}) {
+		t.Errorf("Was %#v, but expected untouched slice", s)
+	}
+}
+
+func BenchmarkSortStrings(b *testing.B) {
+	s := make([]string, 100)
+	for i := range s {
+		s[i] = strconv.Itoa(i)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		SortStrings(s, uint64(i))
+	}
+}
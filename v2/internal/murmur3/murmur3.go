@@ -0,0 +1,157 @@
+// Package murmur3 implements 64-bit variant of MurmurHash3, which is the
+// first half of x64 128-bit MurmurHash3 with zero seed.
+// https://github.com/aappleby/smhasher/blob/master/src/MurmurHash3.cpp
+package murmur3
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	c1 = 0x87c37b91114253d5
+	c2 = 0x4cf5ad432745937f
+)
+
+// Sum64 returns MurmurHash3 sum of data
+func Sum64(data []byte) uint64 {
+	var (
+		h1, h2 uint64
+		length = uint64(len(data))
+	)
+
+	for ; len(data) >= 16; data = data[16:] {
+		h1, h2 = block(h1, h2, binary.LittleEndian.Uint64(data), binary.LittleEndian.Uint64(data[8:]))
+	}
+	return tail(h1, h2, data, length)
+}
+
+// Sum64String returns MurmurHash3 sum of s without converting it to []byte
+func Sum64String(s string) uint64 {
+	var (
+		h1, h2 uint64
+		length = uint64(len(s))
+	)
+
+	for ; len(s) >= 16; s = s[16:] {
+		h1, h2 = block(h1, h2, stringUint64(s), stringUint64(s[8:]))
+	}
+
+	var buf [16]byte
+	return tail(h1, h2, buf[:copy(buf[:], s)], length)
+}
+
+// Sum64Uint64 returns MurmurHash3 sum of big-endian encoding of v
+func Sum64Uint64(v uint64) uint64 {
+	h1 := mixK1(bits.ReverseBytes64(v))
+	return finalize(h1, 0, 8)
+}
+
+func block(h1, h2, k1, k2 uint64) (uint64, uint64) {
+	h1 ^= mixK1(k1)
+	h1 = bits.RotateLeft64(h1, 27)
+	h1 += h2
+	h1 = h1*5 + 0x52dce729
+
+	h2 ^= mixK2(k2)
+	h2 = bits.RotateLeft64(h2, 31)
+	h2 += h1
+	h2 = h2*5 + 0x38495ab5
+	return h1, h2
+}
+
+// tail mixes the last len(data) < 16 bytes and finalizes sum
+func tail(h1, h2 uint64, data []byte, length uint64) uint64 {
+	var k1, k2 uint64
+	switch len(data) {
+	case 15:
+		k2 ^= uint64(data[14]) << 48
+		fallthrough
+	case 14:
+		k2 ^= uint64(data[13]) << 40
+		fallthrough
+	case 13:
+		k2 ^= uint64(data[12]) << 32
+		fallthrough
+	case 12:
+		k2 ^= uint64(data[11]) << 24
+		fallthrough
+	case 11:
+		k2 ^= uint64(data[10]) << 16
+		fallthrough
+	case 10:
+		k2 ^= uint64(data[9]) << 8
+		fallthrough
+	case 9:
+		k2 ^= uint64(data[8])
+		h2 ^= mixK2(k2)
+		fallthrough
+	case 8:
+		k1 ^= uint64(data[7]) << 56
+		fallthrough
+	case 7:
+		k1 ^= uint64(data[6]) << 48
+		fallthrough
+	case 6:
+		k1 ^= uint64(data[5]) << 40
+		fallthrough
+	case 5:
+		k1 ^= uint64(data[4]) << 32
+		fallthrough
+	case 4:
+		k1 ^= uint64(data[3]) << 24
+		fallthrough
+	case 3:
+		k1 ^= uint64(data[2]) << 16
+		fallthrough
+	case 2:
+		k1 ^= uint64(data[1]) << 8
+		fallthrough
+	case 1:
+		k1 ^= uint64(data[0])
+		h1 ^= mixK1(k1)
+	}
+	return finalize(h1, h2, length)
+}
+
+func finalize(h1, h2, length uint64) uint64 {
+	h1 ^= length
+	h2 ^= length
+
+	h1 += h2
+	h2 += h1
+
+	h1 = Fmix64(h1)
+	h2 = Fmix64(h2)
+
+	h1 += h2
+	return h1
+}
+
+func stringUint64(s string) uint64 {
+	_ = s[7]
+	return uint64(s[0]) | uint64(s[1])<<8 | uint64(s[2])<<16 | uint64(s[3])<<24 |
+		uint64(s[4])<<32 | uint64(s[5])<<40 | uint64(s[6])<<48 | uint64(s[7])<<56
+}
+
+// Fmix64 is a 64-bit finalizer of MurmurHash3
+func Fmix64(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
+
+func mixK1(k uint64) uint64 {
+	k *= c1
+	k = bits.RotateLeft64(k, 31)
+	return k * c2
+}
+
+func mixK2(k uint64) uint64 {
+	k *= c2
+	k = bits.RotateLeft64(k, 33)
+	return k * c1
+}
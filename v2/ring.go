@@ -0,0 +1,180 @@
+package hrw
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+type (
+	// Node is a member of Ring
+	Node struct {
+		// ID uniquely identifies node, it's hash used to calculate scores
+		ID string
+		// Weight is a relative capacity of node, zero means 1
+		Weight float64
+	}
+
+	// Ring holds nodes and selects them for keys. It's safe for concurrent
+	// use: changes copy nodes and publish them atomically, so readers never
+	// take a lock. Zero value is an empty Ring ready to use.
+	Ring struct {
+		mu    sync.Mutex
+		state atomic.Pointer[[]member]
+	}
+
+	member struct {
+		Node
+		hash uint64
+	}
+)
+
+// NewRing creates Ring with given nodes, see Ring.Add
+func NewRing(nodes ...Node) (*Ring, error) {
+	r := new(Ring)
+	if err := r.Add(nodes...); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Add adds nodes into Ring, nodes with known ID are replaced. Nothing is
+// added and ErrInvalidNode is returned when some node has empty ID,
+// negative or non-finite weight, or IDs repeat.
+func (r *Ring) Add(nodes ...Node) error {
+	seen := make(map[string]struct{}, len(nodes))
+	for _, n := range nodes {
+		if err := validate(n); err != nil {
+			return err
+		} else if _, ok := seen[n.ID]; ok {
+			return fmt.Errorf("%w: %q repeated", ErrInvalidNode, n.ID)
+		}
+		seen[n.ID] = struct{}{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.members()
+	list := make([]member, 0, len(old)+len(nodes))
+	for _, m := range old {
+		if _, ok := seen[m.ID]; !ok {
+			list = append(list, m)
+		}
+	}
+
+	for _, n := range nodes {
+		if n.Weight == 0 {
+			n.Weight = 1
+		}
+		list = append(list, member{Node: n, hash: HashString(n.ID)})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	r.state.Store(&list)
+	return nil
+}
+
+// Remove removes nodes with given IDs from Ring. Nothing is removed and
+// ErrUnknownNode is returned when some ID is missing.
+func (r *Ring) Remove(ids ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.members()
+	drop := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		i := sort.Search(len(old), func(i int) bool { return old[i].ID >= id })
+		if i == len(old) || old[i].ID != id {
+			return fmt.Errorf("%w: %q", ErrUnknownNode, id)
+		}
+		drop[id] = struct{}{}
+	}
+
+	list := make([]member, 0, len(old))
+	for _, m := range old {
+		if _, ok := drop[m.ID]; !ok {
+			list = append(list, m)
+		}
+	}
+
+	r.state.Store(&list)
+	return nil
+}
+
+// Nodes returns copy of Ring nodes ordered by ID
+func (r *Ring) Nodes() []Node {
+	list := r.members()
+	result := make([]Node, 0, len(list))
+	for _, m := range list {
+		result = append(result, m.Node)
+	}
+	return result
+}
+
+// Len returns count of Ring nodes
+func (r *Ring) Len() int {
+	return len(r.members())
+}
+
+// Get returns the most preferable node for key,
+// ErrNoEligibleNodes is returned for empty Ring
+func (r *Ring) Get(key []byte) (Node, error) {
+	list, err := r.GetN(key, 1)
+	if err != nil {
+		return Node{}, err
+	}
+	return list[0], nil
+}
+
+// GetN returns up to n nodes for key in order of preference,
+// ErrNoEligibleNodes is returned for empty Ring
+func (r *Ring) GetN(key []byte, n int) ([]Node, error) {
+	list := r.members()
+	if len(list) == 0 {
+		return nil, fmt.Errorf("%w: ring is empty", ErrNoEligibleNodes)
+	}
+
+	var (
+		hashes     = make([]uint64, len(list))
+		capacities = make([]float64, len(list))
+	)
+
+	for i, m := range list {
+		hashes[i], capacities[i] = m.hash, m.Weight
+	}
+
+	if n > len(list) {
+		n = len(list)
+	} else if n < 0 {
+		n = 0
+	}
+
+	result := make([]Node, 0, n)
+	for _, i := range order(hashes, Hash(key), capacities)[:n] {
+		result = append(result, list[i].Node)
+	}
+	return result, nil
+}
+
+// members returns current nodes of Ring, they must not be modified
+func (r *Ring) members() []member {
+	if p := r.state.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// validate checks that node can be added into Ring
+func validate(n Node) error {
+	switch {
+	case n.ID == "":
+		return fmt.Errorf("%w: empty ID", ErrInvalidNode)
+	case n.Weight < 0 || math.IsNaN(n.Weight) || math.IsInf(n.Weight, 0):
+		return fmt.Errorf("%w: weight %v of %q", ErrInvalidNode, n.Weight, n.ID)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,89 @@
+package hrw
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func testNodes(n int) []Node {
+	nodes := make([]Node, 0, n)
+	for i := 0; i < n; i++ {
+		nodes = append(nodes, Node{ID: "node-" + strconv.Itoa(i)})
+	}
+	return nodes
+}
+
+func TestRing(t *testing.T) {
+	var r Ring
+	if _, err := r.Get([]byte("key")); !errors.Is(err, ErrNoEligibleNodes) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrNoEligibleNodes)
+	}
+
+	if err := r.Add(testNodes(5)...); err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, err := r.GetN([]byte("key"), 10)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(nodes) != 5 {
+		t.Errorf("Was %d, but expected %d", len(nodes), 5)
+	}
+
+	first, err := r.Get([]byte("key"))
+	if err != nil || first != nodes[0] {
+		t.Errorf("Was %#v (%v), but expected %#v", first, err, nodes[0])
+	}
+
+	if err := r.Remove(first.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	rest, err := r.GetN([]byte("key"), 10)
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(rest, nodes[1:]) {
+		t.Errorf("Was %#v, but expected %#v", rest, nodes[1:])
+	}
+
+	if err := r.Remove("node-1", "missing"); !errors.Is(err, ErrUnknownNode) {
+		t.Errorf("Was %#v, but expected %#v", err, ErrUnknownNode)
+	} else if r.Len() != 4 {
+		t.Errorf("Was %d, but expected %d", r.Len(), 4)
+	}
+}
+
+func TestRingAddInvalid(t *testing.T) {
+	for _, nodes := range [][]Node{
+		{{ID: ""}},
+		{{ID: "a", Weight: -1}},
+		{{ID: "a"}, {ID: "a"}},
+	} {
+		r, _ := NewRing(Node{ID: "b"})
+		if err := r.Add(nodes...); !errors.Is(err, ErrInvalidNode) {
+			t.Errorf("Was %#v, but expected %#v", err, ErrInvalidNode)
+		} else if r.Len() != 1 {
+			t.Errorf("Was %d, but expected %d", r.Len(), 1)
+		}
+	}
+}
+
+func TestRingWeights(t *testing.T) {
+	r, err := NewRing(Node{ID: "a"}, Node{ID: "b"}, Node{ID: "c", Weight: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wins := 0
+	for i := 0; i < 10000; i++ {
+		if n, _ := r.Get([]byte(strconv.Itoa(i))); n.ID == "c" {
+			wins++
+		}
+	}
+
+	if wins < 4500 || wins > 5500 {
+		t.Errorf("Was %d wins of heavy node, but expected about %d", wins, 5000)
+	}
+}
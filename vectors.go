@@ -0,0 +1,53 @@
+package hrw
+
+import "encoding/hex"
+
+// Vector is a canonical test vector of SortSliceByValue for []string,
+// it's used to verify compatibility of ports of this package.
+type Vector struct {
+	// Key is hex encoded key
+	Key string `json:"key"`
+	// Hash of key
+	Hash uint64 `json:"hash"`
+	// Nodes in original order
+	Nodes []string `json:"nodes"`
+	// NodeHashes are hashes of nodes in original order
+	NodeHashes []uint64 `json:"node_hashes"`
+	// Scores of nodes in original order, the lowest score comes first
+	Scores []uint64 `json:"scores"`
+	// Order is indexes of nodes in sorted order
+	Order []uint64 `json:"order"`
+	// Sorted is nodes in sorted order
+	Sorted []string `json:"sorted"`
+}
+
+// GenerateVectors returns test vector for every key over nodes
+func GenerateVectors(keys [][]byte, nodes []string) []Vector {
+	result := make([]Vector, 0, len(keys))
+	for _, key := range keys {
+		v := Vector{
+			Key:        hex.EncodeToString(key),
+			Hash:       Hash(key),
+			Nodes:      append([]string{}, nodes...),
+			NodeHashes: make([]uint64, 0, len(nodes)),
+			Scores:     make([]uint64, 0, len(nodes)),
+		}
+
+		rule := make([]uint64, 0, len(nodes))
+		for _, node := range nodes {
+			h := Hash([]byte(node))
+			v.NodeHashes = append(v.NodeHashes, h)
+			v.Scores = append(v.Scores, valueWeight(h, v.Hash))
+			rule = append(rule, weight(v.Hash, h))
+		}
+
+		v.Order = SortByWeight(rule, v.Hash)
+		v.Sorted = make([]string, 0, len(nodes))
+		for _, i := range v.Order {
+			v.Sorted = append(v.Sorted, nodes[i])
+		}
+
+		result = append(result, v)
+	}
+	return result
+}
@@ -0,0 +1,38 @@
+package hrw
+
+import (
+	"encoding/hex"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestGenerateVectors(t *testing.T) {
+	var (
+		nodes = []string{"a", "b", "c", "d", "e", "f"}
+		keys  = [][]byte{testKey, []byte(""), {0, 1, 2}}
+	)
+
+	vectors := GenerateVectors(keys, nodes)
+	if len(vectors) != len(keys) {
+		t.Fatalf("Was %d, but expected %d", len(vectors), len(keys))
+	}
+
+	for i, v := range vectors {
+		if actual, expect := v.Key, hex.EncodeToString(keys[i]); actual != expect {
+			t.Errorf("Was %q, but expected %q", actual, expect)
+		}
+
+		expect := append([]string{}, nodes...)
+		SortSliceByValue(expect, Hash(keys[i]))
+		if !reflect.DeepEqual(v.Sorted, expect) {
+			t.Errorf("Was %#v, but expected %#v", v.Sorted, expect)
+		}
+
+		if !sort.SliceIsSorted(v.Order, func(a, b int) bool {
+			return v.Scores[v.Order[a]] < v.Scores[v.Order[b]]
+		}) {
+			t.Errorf("Order %v doesn't follow scores %v", v.Order, v.Scores)
+		}
+	}
+}
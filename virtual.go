@@ -0,0 +1,76 @@
+package hrw
+
+import "strconv"
+
+// VirtualNodes returns count virtual nodes of physical node n with IDs
+// derived as "<ID>#<i>". Every virtual node has weight of n, so physical
+// node is selected with probability proportional to count * weight.
+func VirtualNodes(n Node, count int) []Node {
+	nodes := make([]Node, 0, count)
+	for i := 0; i < count; i++ {
+		v := n
+		v.ID = n.ID + "#" + strconv.Itoa(i)
+		v.Physical = n.ID
+		nodes = append(nodes, v)
+	}
+	return nodes
+}
+
+// PhysicalID returns ID of physical node for virtual one or ID of node itself
+func (n Node) PhysicalID() string {
+	if n.Physical != "" {
+		return n.Physical
+	}
+	return n.ID
+}
+
+// GetNPhysical returns up to n active nodes for key in order of preference,
+// skipping virtual nodes of already selected physical ones
+func (r *Ring) GetNPhysical(key []byte, n int) []Node {
+	return r.view().GetNPhysical(key, n)
+}
+
+// GetNPhysical is like Ring.GetNPhysical
+func (s *Snapshot) GetNPhysical(key []byte, n int) []Node {
+	if n <= 0 {
+		return nil
+	}
+
+	var (
+		result = make([]Node, 0, n)
+		seen   = make(map[string]struct{}, n)
+	)
+
+	for _, node := range s.GetN(key, len(s.nodes)) {
+		id := node.PhysicalID()
+		if _, ok := seen[id]; ok {
+			continue
+		}
+
+		seen[id] = struct{}{}
+		if result = append(result, node); len(result) == n {
+			break
+		}
+	}
+	return result
+}
+
+// RemovePhysical removes nodes with given IDs and all their virtual nodes
+func (r *Ring) RemovePhysical(ids ...string) {
+	remove := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		remove[id] = struct{}{}
+	}
+
+	r.update(func(s *Snapshot) bool {
+		nodes := s.nodes[:0]
+		for i := range s.nodes {
+			if _, ok := remove[s.nodes[i].PhysicalID()]; !ok {
+				nodes = append(nodes, s.nodes[i])
+			}
+		}
+
+		s.nodes = nodes
+		return true
+	})
+}
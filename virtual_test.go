@@ -0,0 +1,70 @@
+package hrw
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestVirtualNodes(t *testing.T) {
+	expect := []Node{
+		{ID: "a#0", Weight: 2, Physical: "a"},
+		{ID: "a#1", Weight: 2, Physical: "a"},
+		{ID: "a#2", Weight: 2, Physical: "a"},
+	}
+
+	actual := VirtualNodes(Node{ID: "a", Weight: 2}, 3)
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	for _, n := range actual {
+		if id := n.PhysicalID(); id != "a" {
+			t.Errorf("Was %#v, but expected %#v", id, "a")
+		}
+	}
+
+	if id := (Node{ID: "b"}).PhysicalID(); id != "b" {
+		t.Errorf("Was %#v, but expected %#v", id, "b")
+	}
+}
+
+func TestRingGetNPhysical(t *testing.T) {
+	const keys = 100000
+	var (
+		r      = NewRing()
+		key    = make([]byte, 8)
+		counts = make(map[string]int)
+	)
+
+	r.Add(VirtualNodes(Node{ID: "a"}, 10)...)
+	r.Add(VirtualNodes(Node{ID: "b"}, 30)...)
+	r.Add(Node{ID: "c"})
+
+	for i := uint64(0); i < keys; i++ {
+		binary.BigEndian.PutUint64(key, i)
+
+		nodes := r.GetNPhysical(key, 3)
+		if len(nodes) != 3 {
+			t.Fatalf("Was %d nodes, but expected 3", len(nodes))
+		}
+
+		seen := make(map[string]bool)
+		for _, n := range nodes {
+			if seen[n.PhysicalID()] {
+				t.Fatalf("Physical node %s selected twice", n.PhysicalID())
+			}
+			seen[n.PhysicalID()] = true
+		}
+		counts[nodes[0].PhysicalID()]++
+	}
+
+	if share := float64(counts["b"]) / keys; share < 0.72 || share > 0.74 {
+		t.Errorf("Node b received %.3f of keys, expected 0.73", share)
+	}
+
+	r.RemovePhysical("b")
+	if actual := r.Len(); actual != 11 {
+		t.Errorf("Was %#v, but expected %#v", actual, 11)
+	}
+}
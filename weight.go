@@ -0,0 +1,48 @@
+package hrw
+
+import "github.com/im-kulikov/hrw/internal/murmur3"
+
+// SetWeightFunc replaces function used by Ring to weight nodes, raw weights
+// are scaled by node weights like V2 does. Nil restores Algorithm of Ring.
+// LookupTable and SkeletonTree ignore it.
+func (r *Ring) SetWeightFunc(fn WeightFunc) {
+	r.update(func(s *Snapshot) bool {
+		s.weight = fn
+		return true
+	})
+}
+
+func (s *Snapshot) candidate(value, hash uint64, nodeWeight float64) candidate {
+	if s.weight == nil {
+		return s.alg.candidate(value, hash, nodeWeight)
+	}
+
+	raw := s.weight(value, hash)
+	return candidate{raw: raw, score: score(raw, nodeWeight)}
+}
+
+// MixMurmur3 is WeightFunc used by default, it's murmur3 64-bit finalizer
+// of node and key hashes combined by xor
+func MixMurmur3(node, hash uint64) uint64 {
+	return weight(node, hash)
+}
+
+// MixSplitMix64 is WeightFunc based on splitmix64 finalizer, it's faster
+// than MixMurmur3 on some platforms and has comparable avalanche behavior
+func MixSplitMix64(node, hash uint64) uint64 {
+	z := (node ^ hash) + 0x9e3779b97f4a7c15
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+// Premix mixes node value independently of key, so structure of sequential
+// values (0, 1, 2, ...) isn't preserved by xor with key hash
+func Premix(v uint64) uint64 {
+	return murmur3.Fmix64(v + 0x9e3779b97f4a7c15)
+}
+
+// MixPremixed is WeightFunc used by V3, it's MixMurmur3 of premixed node
+func MixPremixed(node, hash uint64) uint64 {
+	return weight(Premix(node), hash)
+}
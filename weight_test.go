@@ -0,0 +1,99 @@
+package hrw
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortByWeightFunc(t *testing.T) {
+	var (
+		nodes = []uint64{1, 2, 3, 4, 5}
+		hash  = Hash(testKey)
+	)
+
+	if actual, expect := SortByWeightFunc(nodes, hash, weight), SortByWeight(nodes, hash); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	// legacy system prefers nodes closest to hash
+	legacy := func(node, hash uint64) uint64 {
+		if node > hash {
+			return node - hash
+		}
+		return hash - node
+	}
+
+	expect := []uint64{2, 1, 3, 0, 4}
+	if actual := SortByWeightFunc(nodes, 3, legacy); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
+
+func TestRingSetWeightFunc(t *testing.T) {
+	var (
+		r      = NewRing(testNodes(5)...)
+		expect = nodeIDs(r.GetN(testKey, 5))
+	)
+
+	r.SetWeightFunc(func(node, hash uint64) uint64 { return ^weight(node, hash) })
+	if actual := nodeIDs(r.GetN(testKey, 5)); reflect.DeepEqual(actual, expect) {
+		t.Errorf("Expected order to change, got %#v", actual)
+	}
+
+	r.SetAlgorithm(V2)
+	r.SetWeightFunc(weight)
+	v2 := NewRing(testNodes(5)...)
+	v2.SetAlgorithm(V2)
+	if actual, expect := nodeIDs(r.GetN(testKey, 5)), nodeIDs(v2.GetN(testKey, 5)); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+
+	r.SetWeightFunc(nil)
+	r.SetAlgorithm(V1)
+	if actual := nodeIDs(r.GetN(testKey, 5)); !reflect.DeepEqual(actual, expect) {
+		t.Errorf("Was %#v, but expected %#v", actual, expect)
+	}
+}
+
+func TestMixSplitMix64(t *testing.T) {
+	// reference values of splitmix64 generator seeded with 0
+	expect := []uint64{0xe220a8397b1dcdaf, 0x6e789e6aa1b965f4, 0x06c45d188009454f}
+	for i, e := range expect {
+		if actual := MixSplitMix64(uint64(i)*0x9e3779b97f4a7c15, 0); actual != e {
+			t.Errorf("Was %#x, but expected %#x", actual, e)
+		}
+	}
+
+	const (
+		nodes = 10
+		keys  = 100000
+	)
+
+	counts := make([]int, nodes)
+	list := make([]uint64, nodes)
+	for i := range list {
+		list[i] = uint64(i)
+	}
+
+	for i := uint64(0); i < keys; i++ {
+		counts[SortByWeightFunc(list, HashUint64(i), MixSplitMix64)[0]]++
+	}
+
+	for i, c := range counts {
+		if d := float64(c)/keys*nodes - 1; d > 0.05 || d < -0.05 {
+			t.Errorf("Node %d received %d keys, expected %d", i, c, keys/nodes)
+		}
+	}
+}
+
+func BenchmarkMix(b *testing.B) {
+	for name, fn := range map[string]WeightFunc{"murmur3": MixMurmur3, "splitmix64": MixSplitMix64} {
+		b.Run(name, func(b *testing.B) {
+			var sum uint64
+			for i := 0; i < b.N; i++ {
+				sum += fn(uint64(i), 0x9e3779b97f4a7c15)
+			}
+			_ = sum
+		})
+	}
+}